@@ -2,18 +2,22 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
+	"math"
+	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/mudler/skillserver/pkg/domain"
 	"github.com/mudler/skillserver/pkg/git"
+	"github.com/mudler/skillserver/pkg/logging"
 	"github.com/mudler/skillserver/pkg/mcp"
 	"github.com/mudler/skillserver/pkg/web"
 )
@@ -41,16 +45,44 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
-// setupLogger configures logging based on the enable flag
-// When disabled, all logs go to io.Discard to avoid interfering with stdio MCP protocol
-func setupLogger(enable bool) *log.Logger {
-	var writer io.Writer
-	if enable {
-		writer = os.Stderr // Use stderr for logs when enabled (doesn't interfere with MCP stdio)
-	} else {
-		writer = io.Discard // Discard all logs when disabled
+// parseFileMode parses an octal permission string such as "0644"
+func parseFileMode(value string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid file mode %q (expected octal permissions such as 0644)", value)
 	}
-	return log.New(writer, "", log.LstdFlags)
+	return os.FileMode(mode), nil
+}
+
+// parseTimeout parses a duration such as "30s", or a plain number of seconds
+func parseTimeout(name, value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid %s %q (expected a positive duration such as 30s)", name, value)
+	}
+	return timeout, nil
+}
+
+// parseSize parses a positive number of bytes
+func parseSize(name, value string) (int, error) {
+	size, err := strconv.Atoi(value)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("invalid %s %q (expected a positive number of bytes)", name, value)
+	}
+	return size, nil
+}
+
+// setupLogger configures logging based on the enable flag, logging messages of level and above
+// When disabled, all logs are discarded to avoid interfering with stdio MCP protocol
+func setupLogger(enable bool, level slog.Level) *slog.Logger {
+	if !enable {
+		return logging.Discard()
+	}
+	// Use stderr for logs when enabled (doesn't interfere with MCP stdio)
+	return logging.New(os.Stderr, level)
 }
 
 // extractRepoName extracts a repository name from a URL (same logic as GitSyncer)
@@ -81,6 +113,7 @@ func extractRepoName(repoURL string) string {
 func main() {
 	// Get default values from environment variables
 	defaultDir := getEnvOrDefault("SKILLSERVER_DIR", getEnvOrDefault("SKILLS_DIR", "./skills"))
+	defaultHost := getEnvOrEmpty("SKILLSERVER_HOST")
 	defaultPort := getEnvOrDefault("SKILLSERVER_PORT", getEnvOrDefault("PORT", "8080"))
 	defaultGitRepos := getEnvOrEmpty("SKILLSERVER_GIT_REPOS")
 	if defaultGitRepos == "" {
@@ -88,18 +121,96 @@ func main() {
 	}
 	// Logging defaults to false (disabled) to avoid interfering with MCP stdio
 	defaultEnableLogging := getEnvBool("SKILLSERVER_ENABLE_LOGGING", false)
+	defaultLogLevel := getEnvOrEmpty("SKILLSERVER_LOG_LEVEL")
+	defaultMCPScopes := getEnvOrEmpty("SKILLSERVER_MCP_SCOPES")
+	defaultMCPTransport := getEnvOrDefault("SKILLSERVER_MCP_TRANSPORT", "stdio")
+	defaultMCPPath := getEnvOrDefault("SKILLSERVER_MCP_PATH", "/mcp")
+	defaultMCPMaxExportSize := getEnvOrDefault("SKILLSERVER_MCP_MAX_EXPORT_SIZE", strconv.Itoa(mcp.DefaultMaxExportSize))
+	defaultMCPMaxReadSize := getEnvOrDefault("SKILLSERVER_MCP_MAX_READ_SIZE", strconv.Itoa(mcp.DefaultMaxReadSize))
+	defaultGitProxy := getEnvOrEmpty("SKILLSERVER_GIT_PROXY")
+	defaultGitUsername := getEnvOrEmpty("SKILLSERVER_GIT_USERNAME")
+	defaultGitToken := getEnvOrEmpty("SKILLSERVER_GIT_TOKEN")
+	defaultGitSSHKey := getEnvOrEmpty("SKILLSERVER_GIT_SSH_KEY")
+	defaultGitSSHKnownHosts := getEnvOrEmpty("SKILLSERVER_GIT_SSH_KNOWN_HOSTS")
+	defaultGitSSHInsecure := getEnvBool("SKILLSERVER_GIT_SSH_INSECURE_IGNORE_HOST_KEY", false)
+	defaultGitReset := getEnvOrEmpty("SKILLSERVER_GIT_RESET")
+	defaultGitSyncTimeout := getEnvOrDefault("SKILLSERVER_GIT_SYNC_TIMEOUT", git.DefaultSyncTimeout.String())
+	defaultGitSyncConcurrency := getEnvOrDefault("SKILLSERVER_GIT_SYNC_CONCURRENCY", strconv.Itoa(git.DefaultSyncConcurrency))
+	defaultAllowedExtensions := getEnvOrEmpty("SKILLSERVER_ALLOWED_RESOURCE_EXTENSIONS")
+	defaultDeniedExtensions := getEnvOrEmpty("SKILLSERVER_DENIED_RESOURCE_EXTENSIONS")
+	defaultResourceDirs := getEnvOrEmpty("SKILLSERVER_RESOURCE_DIRS")
+	defaultImportDirMode := getEnvOrDefault("SKILLSERVER_IMPORT_DIR_MODE", "0755")
+	defaultImportFileMode := getEnvOrDefault("SKILLSERVER_IMPORT_FILE_MODE", "0644")
+	defaultImportMaxExtractedSize := getEnvOrDefault("SKILLSERVER_IMPORT_MAX_EXTRACTED_SIZE", strconv.Itoa(domain.DefaultMaxExtractedSize))
+	defaultImportMaxEntries := getEnvOrDefault("SKILLSERVER_IMPORT_MAX_ENTRIES", strconv.Itoa(domain.DefaultMaxArchiveEntries))
+	defaultShutdownTimeout := getEnvOrDefault("SKILLSERVER_SHUTDOWN_TIMEOUT", web.DefaultShutdownTimeout.String())
+	defaultWatch := getEnvBool("SKILLSERVER_WATCH", false)
+	defaultIndexDir := getEnvOrEmpty("SKILLSERVER_INDEX_DIR")
+	defaultOverlayDir := getEnvOrEmpty("SKILLSERVER_OVERLAY_DIR")
+	defaultTLSCert := getEnvOrEmpty("SKILLSERVER_TLS_CERT")
+	defaultTLSKey := getEnvOrEmpty("SKILLSERVER_TLS_KEY")
+	defaultCORSOrigins := getEnvOrEmpty("SKILLSERVER_CORS_ORIGINS")
+	defaultAPIKey := getEnvOrEmpty("SKILLSERVER_API_KEY")
+	defaultAPIKeyReads := getEnvBool("SKILLSERVER_API_KEY_READS", false)
+	defaultWebhookSecret := getEnvOrEmpty("SKILLSERVER_WEBHOOK_SECRET")
+	defaultRateLimit := getEnvOrDefault("SKILLSERVER_RATE_LIMIT", "0")
+	defaultSearchFuzziness := getEnvOrDefault("SKILLSERVER_SEARCH_FUZZINESS", strconv.Itoa(domain.DefaultSearchFuzziness))
+	defaultMaxResourceSize := getEnvOrDefault("SKILLSERVER_MAX_RESOURCE_SIZE", strconv.Itoa(web.DefaultMaxResourceSize))
+	defaultMaxArchiveSize := getEnvOrDefault("SKILLSERVER_MAX_ARCHIVE_SIZE", strconv.Itoa(web.DefaultMaxArchiveSize))
+	defaultMaxContentLength := getEnvOrDefault("SKILLSERVER_MAX_CONTENT_LENGTH", strconv.Itoa(domain.DefaultMaxContentLength))
+	defaultStrictNames := getEnvBool("SKILLSERVER_STRICT_NAMES", false)
 
 	// Parse command line flags (flags override environment variables)
 	skillsDir := flag.String("dir", defaultDir, "Directory to store skills (env: SKILLSERVER_DIR or SKILLS_DIR)")
+	host := flag.String("host", defaultHost, "Host or IP address the web server listens on, e.g. \"127.0.0.1\" (default: all interfaces) (env: SKILLSERVER_HOST)")
 	port := flag.String("port", defaultPort, "Port for the web server (env: SKILLSERVER_PORT or PORT)")
 	gitReposFlag := flag.String("git-repos", defaultGitRepos, "Comma-separated list of Git repository URLs to sync (env: SKILLSERVER_GIT_REPOS or GIT_REPOS)")
 	enableLogging := flag.Bool("enable-logging", defaultEnableLogging, "Enable logging to stderr (env: SKILLSERVER_ENABLE_LOGGING). Default: false (disabled to avoid interfering with MCP stdio)")
+	logLevelFlag := flag.String("log-level", defaultLogLevel, "Minimum level of log messages: debug, info, warn or error; setting it enables logging (default: info) (env: SKILLSERVER_LOG_LEVEL)")
+	mcpScopesFlag := flag.String("mcp-scopes", defaultMCPScopes, "Named MCP scopes served over HTTP at /mcp/<name>, e.g. \"ops=repo:ops-skills;dev=local\" (env: SKILLSERVER_MCP_SCOPES)")
+	mcpTransportFlag := flag.String("mcp-transport", defaultMCPTransport, "Transport for the MCP server: \"stdio\", or \"http\" to serve it over the streamable HTTP transport on the web server (env: SKILLSERVER_MCP_TRANSPORT)")
+	mcpPathFlag := flag.String("mcp-path", defaultMCPPath, "Path the MCP server is served at with the http transport (env: SKILLSERVER_MCP_PATH)")
+	mcpMaxExportSizeFlag := flag.String("mcp-max-export-size", defaultMCPMaxExportSize, "Largest skill archive the export_skill MCP tool returns, in bytes before base64 encoding (env: SKILLSERVER_MCP_MAX_EXPORT_SIZE)")
+	mcpMaxReadSizeFlag := flag.String("mcp-max-read-size", defaultMCPMaxReadSize, "Largest resource content the read_skill_resource MCP tool returns per call, in bytes before base64 encoding; larger files are read in chunks (env: SKILLSERVER_MCP_MAX_READ_SIZE)")
+	gitProxyFlag := flag.String("git-proxy", defaultGitProxy, "Proxy URL for git operations over HTTP(S), overriding HTTPS_PROXY/HTTP_PROXY/NO_PROXY (env: SKILLSERVER_GIT_PROXY)")
+	gitUsernameFlag := flag.String("git-username", defaultGitUsername, "Username for Git repositories accessed over HTTP(S), used with the token (default: git) (env: SKILLSERVER_GIT_USERNAME)")
+	gitTokenFlag := flag.String("git-token", defaultGitToken, "Password or personal access token for Git repositories accessed over HTTP(S); prefer the environment variable, as flags are visible in the process list (env: SKILLSERVER_GIT_TOKEN)")
+	gitSSHKeyFlag := flag.String("git-ssh-key", defaultGitSSHKey, "Private key for Git repositories accessed over SSH; repositories can override it with sshKey in the repo config (env: SKILLSERVER_GIT_SSH_KEY)")
+	gitSSHKnownHostsFlag := flag.String("git-ssh-known-hosts", defaultGitSSHKnownHosts, "known_hosts file used to verify SSH host keys (default: SSH_KNOWN_HOSTS or ~/.ssh/known_hosts) (env: SKILLSERVER_GIT_SSH_KNOWN_HOSTS)")
+	gitSSHInsecureFlag := flag.Bool("git-ssh-insecure-ignore-host-key", defaultGitSSHInsecure, "Skip SSH host key verification, e.g. in CI (env: SKILLSERVER_GIT_SSH_INSECURE_IGNORE_HOST_KEY)")
+	gitResetFlag := flag.String("git-reset", defaultGitReset, "What to do when a pull finds local changes in a repository checkout: \"hard\" to discard them and reset to the remote head (default: fail the sync and report them) (env: SKILLSERVER_GIT_RESET)")
+	gitSyncTimeoutFlag := flag.String("git-sync-timeout", defaultGitSyncTimeout, "How long syncing a single git repository may take before it is cancelled, so an unreachable host cannot hold up the others, e.g. \"2m\" (env: SKILLSERVER_GIT_SYNC_TIMEOUT)")
+	gitSyncConcurrencyFlag := flag.String("git-sync-concurrency", defaultGitSyncConcurrency, "How many git repositories are synced at a time (env: SKILLSERVER_GIT_SYNC_CONCURRENCY)")
+	allowedExtensionsFlag := flag.String("allowed-resource-extensions", defaultAllowedExtensions, "Comma-separated file extensions allowed for resources and imported files, e.g. \".md,.py,.sh\" (default: all) (env: SKILLSERVER_ALLOWED_RESOURCE_EXTENSIONS)")
+	deniedExtensionsFlag := flag.String("denied-resource-extensions", defaultDeniedExtensions, "Comma-separated file extensions rejected for resources and imported files, e.g. \".exe,.so\" (env: SKILLSERVER_DENIED_RESOURCE_EXTENSIONS)")
+	resourceDirsFlag := flag.String("resource-dirs", defaultResourceDirs, "Comma-separated skill subdirectories holding resources, as directory=type pairs with type script, reference or asset, e.g. \"bin=script,docs=reference,files=asset\" (default: scripts, references and assets) (env: SKILLSERVER_RESOURCE_DIRS)")
+	importDirModeFlag := flag.String("import-dir-mode", defaultImportDirMode, "Permissions for directories extracted from imported skill archives (env: SKILLSERVER_IMPORT_DIR_MODE)")
+	importFileModeFlag := flag.String("import-file-mode", defaultImportFileMode, "Permissions for files extracted from imported skill archives; executables also get the execute bit (env: SKILLSERVER_IMPORT_FILE_MODE)")
+	importMaxExtractedSizeFlag := flag.String("import-max-extracted-size", defaultImportMaxExtractedSize, "Largest total size of the files extracted from an imported skill archive, in bytes (env: SKILLSERVER_IMPORT_MAX_EXTRACTED_SIZE)")
+	importMaxEntriesFlag := flag.String("import-max-entries", defaultImportMaxEntries, "Largest number of entries an imported skill archive may contain (env: SKILLSERVER_IMPORT_MAX_ENTRIES)")
+	shutdownTimeoutFlag := flag.String("shutdown-timeout", defaultShutdownTimeout, "How long to wait for in-flight requests on shutdown, e.g. \"30s\" or \"2m\" (env: SKILLSERVER_SHUTDOWN_TIMEOUT)")
+	watchFlag := flag.Bool("watch", defaultWatch, "Re-index skills when files in the skills directory change on disk, e.g. when SKILL.md is edited directly (env: SKILLSERVER_WATCH)")
+	apiKeyFlag := flag.String("api-key", defaultAPIKey, "Require \"Authorization: Bearer <key>\" on REST API requests that change anything; prefer the environment variable, as flags are visible in the process list (env: SKILLSERVER_API_KEY)")
+	apiKeyReadsFlag := flag.Bool("api-key-reads", defaultAPIKeyReads, "Require the API key on REST API reads and the MCP HTTP endpoints too (env: SKILLSERVER_API_KEY_READS)")
+	webhookSecretFlag := flag.String("webhook-secret", defaultWebhookSecret, "Shared secret git webhooks must be signed with (GitHub X-Hub-Signature-256 or GitLab X-Gitlab-Token); prefer the environment variable, as flags are visible in the process list (env: SKILLSERVER_WEBHOOK_SECRET)")
+	rateLimitFlag := flag.String("rate-limit", defaultRateLimit, "Requests per second allowed to the REST API and MCP HTTP endpoints from each client IP, e.g. \"5\" or \"0.5\" (default: 0, unlimited) (env: SKILLSERVER_RATE_LIMIT)")
+	tlsCertFlag := flag.String("tls-cert", defaultTLSCert, "Certificate file (PEM) for serving the web server over HTTPS, used with the key (default: plain HTTP) (env: SKILLSERVER_TLS_CERT)")
+	tlsKeyFlag := flag.String("tls-key", defaultTLSKey, "Private key file (PEM) of the HTTPS certificate (env: SKILLSERVER_TLS_KEY)")
+	corsOriginsFlag := flag.String("cors-origins", defaultCORSOrigins, "Comma-separated origins allowed to call the REST API from a browser, e.g. \"https://app.example.com\", or \"*\" for any (default: CORS disabled) (env: SKILLSERVER_CORS_ORIGINS)")
+	overlayDirFlag := flag.String("overlay-dir", defaultOverlayDir, "Local overlay directory: writes to git repository skills are stored there and shadow the git content instead of being refused (default: disabled) (env: SKILLSERVER_OVERLAY_DIR)")
+	indexDirFlag := flag.String("index-dir", defaultIndexDir, "Directory for the search index (default: a directory in the user cache directory named after the skills directory) (env: SKILLSERVER_INDEX_DIR)")
+	searchFuzzinessFlag := flag.String("search-fuzziness", defaultSearchFuzziness, fmt.Sprintf("Edit distance within which search terms match, from 0 (exact) to %d (env: SKILLSERVER_SEARCH_FUZZINESS)", domain.MaxSearchFuzziness))
+	maxResourceSizeFlag := flag.String("max-resource-size", defaultMaxResourceSize, "Largest resource that can be created or uploaded, in bytes (env: SKILLSERVER_MAX_RESOURCE_SIZE)")
+	maxArchiveSizeFlag := flag.String("max-archive-size", defaultMaxArchiveSize, "Largest skill or resource archive that can be uploaded, in bytes (env: SKILLSERVER_MAX_ARCHIVE_SIZE)")
+	maxContentLengthFlag := flag.String("max-content-length", defaultMaxContentLength, "Longest SKILL.md body that can be stored, in bytes; longer skills on disk are skipped with a warning (env: SKILLSERVER_MAX_CONTENT_LENGTH)")
+	strictNamesFlag := flag.Bool("strict-names", defaultStrictNames, "Skip skills whose frontmatter name differs from their directory name, as the Agent Skills specification requires, instead of serving them with the frontmatter name as their ID (env: SKILLSERVER_STRICT_NAMES)")
 	flag.Parse()
 
-	// Setup logger based on flag
-	logger := setupLogger(*enableLogging)
-	log.SetOutput(logger.Writer())
-	log.SetFlags(logger.Flags())
+	// Setup logger based on flags; choosing a log level enables logging
+	loggingEnabled := *enableLogging || *logLevelFlag != ""
+	logLevel, logLevelErr := logging.ParseLevel(*logLevelFlag)
+	logger := setupLogger(loggingEnabled && logLevelErr == nil, logLevel)
+	slog.SetDefault(logger)
 
 	// Get final values (flags take precedence over env vars)
 	finalDir := *skillsDir
@@ -112,11 +223,12 @@ func main() {
 	// Load git repos from config file or use command line/env repos
 	var gitRepos []string
 	configRepos, err := configManager.LoadConfig()
-	if err != nil && *enableLogging {
-		log.Printf("Warning: Failed to load git repo config: %v", err)
+	if err != nil {
+		logger.Warn("Failed to load git repo config", "error", err)
 	}
 
 	// If config file has repos, use them; otherwise use command line/env repos
+	repoConfigs := configRepos
 	if len(configRepos) > 0 {
 		for _, repo := range configRepos {
 			if repo.Enabled {
@@ -128,41 +240,180 @@ func main() {
 		if finalGitRepos != "" {
 			parsedRepos := strings.Split(finalGitRepos, ",")
 			for i := range parsedRepos {
-				parsedRepos[i] = strings.TrimSpace(parsedRepos[i])
+				parsedRepos[i] = git.NormalizeRepoURL(parsedRepos[i])
 			}
 			gitRepos = parsedRepos
 
 			// Save to config file if we have repos from command line/env
 			if len(gitRepos) > 0 {
-				configs := make([]git.GitRepoConfig, len(gitRepos))
-				for i, url := range gitRepos {
-					configs[i] = git.GitRepoConfig{
-						ID:      git.GenerateID(url),
-						URL:     url,
-						Name:    git.ExtractRepoName(url),
-						Enabled: true,
-					}
+				configs := make([]git.GitRepoConfig, 0, len(gitRepos))
+				for _, url := range gitRepos {
+					configs = append(configs, git.NewRepoConfig(url, configs))
 				}
-				if err := configManager.SaveConfig(configs); err != nil && *enableLogging {
-					log.Printf("Warning: Failed to save git repo config: %v", err)
+				repoConfigs = configs
+				if err := configManager.SaveConfig(configs); err != nil {
+					logger.Warn("Failed to save git repo config", "error", err)
 				}
 			}
 		}
 	}
 
-	// Extract git repo names from URLs for read-only detection
-	var gitRepoNames []string
-	for _, repoURL := range gitRepos {
-		repoName := git.ExtractRepoName(repoURL)
-		if repoName != "" {
-			gitRepoNames = append(gitRepoNames, repoName)
+	// Directory names of the enabled git repos, for read-only detection
+	gitRepoNames := git.EnabledRepoDirNames(repoConfigs)
+
+	// Run the startup self-check before touching the index
+	var problems []startupProblem
+	if logLevelErr != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: logLevelErr.Error()})
+	}
+	addr, err := web.ListenAddr(*host, finalPort)
+	if err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: fmt.Sprintf("invalid web server address: %v", err)})
+	}
+	startupProblems, readOnly := runStartupChecks(finalDir, addr, gitRepos)
+	problems = append(problems, startupProblems...)
+	problems = append(problems, checkGitProxy(*gitProxyFlag)...)
+	sshOptions := git.SSHOptions{
+		KeyPath:               *gitSSHKeyFlag,
+		KnownHosts:            *gitSSHKnownHostsFlag,
+		InsecureIgnoreHostKey: *gitSSHInsecureFlag,
+	}
+	problems = append(problems, checkGitSSH(sshOptions)...)
+	gitResetPolicy, err := git.ParseResetPolicy(*gitResetFlag)
+	if err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+	}
+	gitSyncTimeout, err := parseTimeout("git sync timeout", *gitSyncTimeoutFlag)
+	if err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+	}
+	gitSyncConcurrency, err := strconv.Atoi(*gitSyncConcurrencyFlag)
+	if err != nil || gitSyncConcurrency <= 0 {
+		problems = append(problems, startupProblem{Fatal: true, Message: fmt.Sprintf("invalid git sync concurrency %q (expected a positive number)", *gitSyncConcurrencyFlag)})
+	}
+
+	scopes, err := domain.ParseScopes(*mcpScopesFlag)
+	if err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: fmt.Sprintf("invalid MCP scopes: %v", err)})
+	}
+	problems = append(problems, checkMCPTransport(*mcpTransportFlag, *mcpPathFlag, scopes)...)
+
+	importOptions := domain.DefaultImportOptions
+	importOptions.Extensions = domain.ExtensionPolicy{
+		Allowed: domain.ParseExtensionList(*allowedExtensionsFlag),
+		Denied:  domain.ParseExtensionList(*deniedExtensionsFlag),
+	}
+	resourceDirs, err := domain.ParseResourceDirs(*resourceDirsFlag)
+	if err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: fmt.Sprintf("invalid resource directories: %v", err)})
+	}
+	if importOptions.DirMode, err = parseFileMode(*importDirModeFlag); err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: fmt.Sprintf("invalid import directory mode: %v", err)})
+	}
+	if importOptions.FileMode, err = parseFileMode(*importFileModeFlag); err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: fmt.Sprintf("invalid import file mode: %v", err)})
+	}
+	importMaxExtractedSize, err := parseSize("import max extracted size", *importMaxExtractedSizeFlag)
+	if err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+	}
+	importOptions.MaxExtractedSize = int64(importMaxExtractedSize)
+	if importOptions.MaxEntries, err = strconv.Atoi(*importMaxEntriesFlag); err != nil || importOptions.MaxEntries <= 0 {
+		problems = append(problems, startupProblem{Fatal: true, Message: fmt.Sprintf("invalid import max entries %q (expected a positive number)", *importMaxEntriesFlag)})
+	}
+
+	var corsOrigins []string
+	for _, origin := range strings.Split(*corsOriginsFlag, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			corsOrigins = append(corsOrigins, origin)
+		}
+	}
+	if len(corsOrigins) > 0 {
+		if err := web.ValidateCORSOrigins(corsOrigins); err != nil {
+			problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+		}
+	}
+	if *tlsCertFlag != "" || *tlsKeyFlag != "" {
+		if err := web.ValidateTLS(*tlsCertFlag, *tlsKeyFlag); err != nil {
+			problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
 		}
 	}
+	if *apiKeyReadsFlag && *apiKeyFlag == "" {
+		problems = append(problems, startupProblem{Message: "API key for reads requested but no API key is set; the API is not authenticated"})
+	}
 
-	// Initialize skill manager
-	skillManager, err := domain.NewFileSystemManager(finalDir, gitRepoNames)
+	rateLimit, err := strconv.ParseFloat(*rateLimitFlag, 64)
+	if err != nil || rateLimit < 0 || math.IsNaN(rateLimit) || math.IsInf(rateLimit, 0) {
+		problems = append(problems, startupProblem{Fatal: true, Message: fmt.Sprintf("invalid rate limit %q (expected a number of requests per second, or 0 for unlimited)", *rateLimitFlag)})
+	}
+
+	shutdownTimeout, err := parseTimeout("shutdown timeout", *shutdownTimeoutFlag)
+	if err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+	}
+
+	mcpMaxExportSize, err := parseSize("MCP max export size", *mcpMaxExportSizeFlag)
+	if err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+	}
+	mcpMaxReadSize, err := parseSize("MCP max read size", *mcpMaxReadSizeFlag)
+	if err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+	}
+	maxResourceSize, err := parseSize("max resource size", *maxResourceSizeFlag)
+	if err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+	}
+	maxArchiveSize, err := parseSize("max archive size", *maxArchiveSizeFlag)
+	if err != nil {
+		problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+	}
+	maxContentLength, err := parseSize("max content length", *maxContentLengthFlag)
 	if err != nil {
-		log.Fatalf("Failed to initialize skill manager: %v", err)
+		problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+	}
+
+	searchFuzziness, err := strconv.Atoi(*searchFuzzinessFlag)
+	if err != nil || searchFuzziness < 0 || searchFuzziness > domain.MaxSearchFuzziness {
+		problems = append(problems, startupProblem{Fatal: true, Message: fmt.Sprintf("invalid search fuzziness %q (expected 0 to %d)", *searchFuzzinessFlag, domain.MaxSearchFuzziness)})
+	}
+
+	// Initialize skill manager
+	var skillManager *domain.FileSystemManager
+	if !hasFatalProblem(problems) {
+		indexDir := *indexDirFlag
+		if indexDir == "" {
+			indexDir = domain.DefaultIndexDir(finalDir)
+		}
+		skillManager, err = domain.NewFileSystemManagerWithIndexDir(finalDir, indexDir, gitRepoNames)
+		if err != nil {
+			problems = append(problems, startupProblem{
+				Fatal:   true,
+				Message: fmt.Sprintf("failed to initialize skill manager (search index could not be opened): %v", err),
+			})
+		} else if err := skillManager.SetSearchFuzziness(searchFuzziness); err != nil {
+			problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+		} else if err := skillManager.SetOverlayDir(*overlayDirFlag); err != nil {
+			problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+		} else if err := skillManager.SetMaxContentLength(maxContentLength); err != nil {
+			problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+		} else if err := skillManager.SetStrictNames(*strictNamesFlag); err != nil {
+			problems = append(problems, startupProblem{Fatal: true, Message: err.Error()})
+		} else {
+			skillManager.SetResourceDirs(resourceDirs)
+			if err := skillManager.RecoveredIndex(); err != nil {
+				problems = append(problems, startupProblem{Message: fmt.Sprintf("%v; it has been rebuilt from the skills directory", err)})
+			}
+		}
+	}
+
+	// Fatal problems always go to stderr (which doesn't interfere with MCP stdio) so we never exit silently
+	if hasFatalProblem(problems) {
+		reportStartupProblems(os.Stderr, problems)
+		os.Exit(1)
+	}
+	if logger.Enabled(context.Background(), slog.LevelWarn) {
+		reportStartupProblems(os.Stderr, problems)
 	}
 
 	// Get FileSystemManager reference for handlers
@@ -170,18 +421,46 @@ func main() {
 
 	// Initialize Git syncer if repos are provided
 	var gitSyncer *git.GitSyncer
+	// Syncing several repositories back to back re-indexes once, after the last of them
 	gitSyncer = git.NewGitSyncer(finalDir, gitRepos, func() error {
-		return skillManager.RebuildIndex()
+		skillManager.RequestReindex()
+		return nil
 	})
-	// Configure git syncer output based on logging flag
-	if *enableLogging {
-		gitSyncer.SetProgressWriter(os.Stderr) // Use stderr for git progress
-		gitSyncer.SetLogger(os.Stderr)         // Use stderr for log messages
+	gitSyncer.SetRepoConfigs(repoConfigs)
+	gitSyncer.SetProxy(*gitProxyFlag)
+	gitSyncer.SetSSHOptions(sshOptions)
+	gitSyncer.SetHTTPCredentials(git.HTTPCredentials{Username: *gitUsernameFlag, Token: *gitTokenFlag})
+	gitSyncer.SetResetPolicy(gitResetPolicy)
+	gitSyncer.SetSyncTimeout(gitSyncTimeout)
+	gitSyncer.SetSyncConcurrency(gitSyncConcurrency)
+	// Configure git syncer output based on logging flag; git progress is only shown at debug level
+	if loggingEnabled {
+		if logger.Enabled(context.Background(), slog.LevelDebug) {
+			gitSyncer.SetProgressWriter(os.Stderr) // Use stderr for git progress
+		}
+		gitSyncer.SetLogger(logger)
+		skillManager.SetLogger(logger)
+		skillManager.SetReindexErrorHandler(func(err error) {
+			logger.Error("Failed to re-index skills", "error", err)
+		})
 	}
-	if err := gitSyncer.Start(); err != nil {
-		log.Printf("Warning: Failed to start Git syncer: %v", err)
-	} else if *enableLogging {
-		log.Println("Git syncer started")
+
+	// Watch the skills directory for changes made outside the API
+	var watcher *domain.Watcher
+	if *watchFlag {
+		watcher, err = domain.NewWatcher(skillManager, domain.DefaultWatchDebounce)
+		if err == nil {
+			if loggingEnabled {
+				watcher.SetLogger(logger)
+			}
+			err = watcher.Start()
+		}
+		if err != nil {
+			logger.Warn("Failed to watch skills directory", "error", err)
+			watcher = nil
+		} else {
+			logger.Info("Watching skills directory for changes", "dir", finalDir)
+		}
 	}
 
 	// Create context for graceful shutdown
@@ -193,49 +472,135 @@ func main() {
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
 	// Start web server in a goroutine (non-blocking)
-	webServer := web.NewServer(skillManager, fsManager, gitRepos, gitSyncer, configManager, *enableLogging)
+	webServer := web.NewServer(skillManager, fsManager, gitRepos, gitSyncer, configManager, loggingEnabled)
+	webServer.SetImportOptions(importOptions)
+	webServer.SetMaxResourceSize(maxResourceSize)
+	webServer.SetMaxArchiveSize(maxArchiveSize)
+	if len(corsOrigins) > 0 {
+		// Already validated with the other startup checks
+		webServer.SetCORSOrigins(corsOrigins)
+	}
+	if *tlsCertFlag != "" {
+		// Already validated with the other startup checks
+		webServer.SetTLS(*tlsCertFlag, *tlsKeyFlag)
+	}
+	webServer.SetRateLimit(rateLimit)
+	webServer.SetAPIKey(*apiKeyFlag, *apiKeyReadsFlag)
+	webServer.SetWebhookSecret(*webhookSecretFlag)
+	webServer.SetReadOnly(readOnly)
+	// The MCP path is only served with the http transport
+	mcpPath := ""
+	if *mcpTransportFlag == "http" {
+		mcpPath = *mcpPathFlag
+	}
+	scopeNames := make([]string, len(scopes))
+	for i, scope := range scopes {
+		scopeNames[i] = scope.Name
+	}
+	runtimeLogLevel := ""
+	if loggingEnabled {
+		runtimeLogLevel = logging.LevelName(logLevel)
+	}
+	webServer.SetRuntimeConfig(web.RuntimeConfig{
+		Host:             *host,
+		Port:             finalPort,
+		TLS:              *tlsCertFlag != "",
+		GitProxy:         *gitProxyFlag,
+		MCPScopes:        scopeNames,
+		MCPTransport:     *mcpTransportFlag,
+		MCPPath:          mcpPath,
+		CORSOrigins:      corsOrigins,
+		ShutdownTimeout:  shutdownTimeout,
+		EnableLogging:    loggingEnabled,
+		LogLevel:         runtimeLogLevel,
+		MCPMaxExportSize: mcpMaxExportSize,
+		MCPMaxReadSize:   mcpMaxReadSize,
+		Watch:            watcher != nil,
+	})
+	if loggingEnabled {
+		webServer.SetLogger(logger)
+	}
+
+	// Serve each scope as its own MCP server backed by a filtered view of the skills
+	for _, scope := range scopes {
+		scopedServer := mcp.NewServer(domain.NewFilteredManager(skillManager, scope.Filter))
+		scopedServer.SetMaxExportSize(mcpMaxExportSize)
+		scopedServer.SetMaxReadSize(mcpMaxReadSize)
+		webServer.MountMCP("/mcp/"+scope.Name, scopedServer.HTTPHandler())
+		logger.Info("Serving MCP scope", "scope", scope.Name, "path", "/mcp/"+scope.Name)
+	}
+
+	// The MCP server shares the skill manager with the web server, whichever transport it uses
+	mcpServer := mcp.NewServer(skillManager)
+	mcpServer.SetMaxExportSize(mcpMaxExportSize)
+	mcpServer.SetMaxReadSize(mcpMaxReadSize)
+	if mcpPath != "" {
+		webServer.MountMCP(mcpPath, mcpServer.HTTPHandler())
+		logger.Info("Serving MCP", "path", mcpPath)
+	}
+
 	go func() {
-		addr := fmt.Sprintf(":%s", finalPort)
-		if *enableLogging {
-			log.Printf("Starting web server on %s", addr)
-		}
-		if err := webServer.Start(addr); err != nil {
-			log.Printf("Web server error: %v", err)
+		logger.Info("Starting web server", "addr", addr)
+		if err := webServer.Start(addr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Web server error", "error", err)
+			if mcpPath != "" {
+				// Without the web server there is no MCP server either, so don't keep running silently
+				fmt.Fprintf(os.Stderr, "skillserver: web server error: %v\n", err)
+				os.Exit(1)
+			}
 		}
 	}()
 
-	// Start MCP server on main thread (blocking, stdio)
-	mcpServer := mcp.NewServer(skillManager)
-
 	// Handle shutdown in a goroutine
 	go func() {
 		<-sigChan
-		if *enableLogging {
-			log.Println("Shutting down...")
-		}
+		logger.Info("Shutting down...")
 
-		// Stop Git syncer
+		// Stop Git syncer, cancelling in-flight git operations
 		if gitSyncer != nil {
 			gitSyncer.Stop()
 		}
 
+		// Stop watching the skills directory
+		if watcher != nil {
+			watcher.Stop()
+		}
+
 		// Shutdown web server
-		if err := webServer.Shutdown(); err != nil {
-			log.Printf("Error shutting down web server: %v", err)
+		if err := webServer.Shutdown(shutdownTimeout); err != nil {
+			logger.Error("Error shutting down web server", "error", err)
 		}
 
-		cancel()
-		if *enableLogging {
-			log.Println("Shutdown complete")
+		// Close the search index so it is reopened cleanly on the next start
+		if err := skillManager.Close(); err != nil {
+			logger.Error("Error closing search index", "error", err)
 		}
+
+		cancel()
+		logger.Info("Shutdown complete")
 	}()
 
+	// Run the initial sync once the web server is up, so /readyz reports it in progress
+	if err := gitSyncer.Start(); err != nil {
+		logger.Warn("Failed to start Git syncer", "error", git.RedactCredentials(err.Error()))
+	} else {
+		logger.Info("Git syncer started")
+	}
+
+	// Over HTTP the web server serves MCP, so just wait for shutdown
+	if mcpPath != "" {
+		<-ctx.Done()
+		return
+	}
+
 	// Run MCP server (blocks main thread)
 	// Note: No logging here to avoid interfering with stdio protocol
 	if err := mcpServer.Run(ctx); err != nil {
-		// Only log errors if logging is enabled
-		if *enableLogging {
-			log.Printf("MCP server error: %v", err)
-		}
+		// Only logged if logging is enabled
+		logger.Error("MCP server error", "error", err)
+	}
+	// The MCP client may also disconnect without a signal; closing the index twice does nothing
+	if err := skillManager.Close(); err != nil {
+		logger.Error("Error closing search index", "error", err)
 	}
 }
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/git"
+)
+
+// startupProblem describes a configuration problem found by the startup self-check
+type startupProblem struct {
+	Fatal   bool   // true if the server cannot run with this problem
+	Message string // Human readable description of the problem
+}
+
+// checkSkillsDir verifies that the skills directory exists (or can be created) and is writable
+// A directory that is not writable is served read-only, unless git repositories have to be synced into it
+// Returns whether the skills directory is read-only
+func checkSkillsDir(skillsDir string, repos []string) ([]startupProblem, bool) {
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		return []startupProblem{{Fatal: true, Message: fmt.Sprintf("skills directory %s cannot be created: %v", skillsDir, err)}}, false
+	}
+
+	info, err := os.Stat(skillsDir)
+	if err != nil {
+		return []startupProblem{{Fatal: true, Message: fmt.Sprintf("skills directory %s is not accessible: %v", skillsDir, err)}}, false
+	}
+	if !info.IsDir() {
+		return []startupProblem{{Fatal: true, Message: fmt.Sprintf("skills directory %s is not a directory", skillsDir)}}, false
+	}
+
+	probe, err := os.CreateTemp(skillsDir, ".write-check-*")
+	if err != nil {
+		if len(repos) > 0 {
+			return []startupProblem{{Fatal: true, Message: fmt.Sprintf("skills directory %s is not writable (needed for git sync): %v", skillsDir, err)}}, false
+		}
+		return []startupProblem{{Message: fmt.Sprintf("skills directory %s is not writable, serving it read-only: %v", skillsDir, err)}}, true
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return nil, false
+}
+
+// checkListenAddr verifies that the web server address can be bound
+func checkListenAddr(addr string) []startupProblem {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return []startupProblem{{Message: fmt.Sprintf("web server address %s cannot be bound (web UI and REST API will be unavailable): %v", addr, err)}}
+	}
+	ln.Close()
+	return nil
+}
+
+// checkGitRepos verifies that every configured repository URL is well-formed
+func checkGitRepos(repos []string) []startupProblem {
+	var problems []startupProblem
+	seen := make(map[string]string)
+	var configs []git.GitRepoConfig
+	for _, repoURL := range repos {
+		if err := git.ValidateRepoURL(repoURL); err != nil {
+			problems = append(problems, startupProblem{Message: err.Error()})
+			continue
+		}
+		// Repositories sharing a name get distinct directories, but the same repository listed twice would share one
+		name := git.RepoDirName(repoURL, configs)
+		if other, ok := seen[name]; ok {
+			problems = append(problems, startupProblem{Message: fmt.Sprintf("repositories %s and %s both map to directory %q", other, repoURL, name)})
+			continue
+		}
+		seen[name] = repoURL
+		configs = append(configs, git.GitRepoConfig{URL: repoURL, Name: name})
+	}
+	return problems
+}
+
+// checkGitProxy verifies that the git proxy override, if set, is a valid proxy URL
+func checkGitProxy(proxyURL string) []startupProblem {
+	if proxyURL == "" {
+		return nil
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+		return []startupProblem{{Fatal: true, Message: "invalid git proxy URL (expected http://, https:// or socks5://host:port)"}}
+	}
+	return nil
+}
+
+// checkGitSSH verifies that the SSH key and known_hosts files, if set, are readable
+func checkGitSSH(opts git.SSHOptions) []startupProblem {
+	var problems []startupProblem
+	for _, file := range []struct{ name, path string }{{"git SSH key", opts.KeyPath}, {"git SSH known_hosts file", opts.KnownHosts}} {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.ReadFile(file.path); err != nil {
+			problems = append(problems, startupProblem{Fatal: true, Message: fmt.Sprintf("%s %s cannot be read: %v", file.name, file.path, err)})
+		}
+	}
+	if opts.InsecureIgnoreHostKey {
+		problems = append(problems, startupProblem{Message: "git SSH host key verification is disabled"})
+	}
+	return problems
+}
+
+// checkMCPTransport verifies the MCP transport and, for the HTTP transport, that its path is free
+func checkMCPTransport(transport, path string, scopes []domain.Scope) []startupProblem {
+	switch transport {
+	case "stdio":
+		return nil
+	case "http":
+	default:
+		return []startupProblem{{Fatal: true, Message: fmt.Sprintf("invalid MCP transport %q (expected stdio or http)", transport)}}
+	}
+
+	if !strings.HasPrefix(path, "/") || path == "/" || strings.HasSuffix(path, "/") {
+		return []startupProblem{{Fatal: true, Message: fmt.Sprintf("invalid MCP path %q (expected a path such as /mcp)", path)}}
+	}
+	for _, reserved := range []string{"/api", "/healthz", "/readyz"} {
+		if path == reserved || strings.HasPrefix(path, reserved+"/") {
+			return []startupProblem{{Fatal: true, Message: fmt.Sprintf("MCP path %s conflicts with %s", path, reserved)}}
+		}
+	}
+	for _, scope := range scopes {
+		if path == "/mcp/"+scope.Name {
+			return []startupProblem{{Fatal: true, Message: fmt.Sprintf("MCP path %s conflicts with MCP scope %q", path, scope.Name)}}
+		}
+	}
+	return nil
+}
+
+// checkConflictingEnv reports when both the primary and the alternative environment variable are set to different values
+func checkConflictingEnv() []startupProblem {
+	var problems []startupProblem
+	pairs := [][2]string{
+		{"SKILLSERVER_DIR", "SKILLS_DIR"},
+		{"SKILLSERVER_PORT", "PORT"},
+		{"SKILLSERVER_GIT_REPOS", "GIT_REPOS"},
+	}
+	for _, pair := range pairs {
+		primary, alternative := os.Getenv(pair[0]), os.Getenv(pair[1])
+		if primary != "" && alternative != "" && primary != alternative {
+			problems = append(problems, startupProblem{Message: fmt.Sprintf("both %s and %s are set to different values, using %s", pair[0], pair[1], pair[0])})
+		}
+	}
+	return problems
+}
+
+// runStartupChecks runs all startup self-checks and returns the problems found
+// and whether the skills directory has to be served read-only
+func runStartupChecks(skillsDir, addr string, repos []string) ([]startupProblem, bool) {
+	var problems []startupProblem
+	problems = append(problems, checkConflictingEnv()...)
+	dirProblems, readOnly := checkSkillsDir(skillsDir, repos)
+	problems = append(problems, dirProblems...)
+	problems = append(problems, checkListenAddr(addr)...)
+	problems = append(problems, checkGitRepos(repos)...)
+	return problems, readOnly
+}
+
+// hasFatalProblem reports whether any of the problems prevents the server from running
+func hasFatalProblem(problems []startupProblem) bool {
+	for _, p := range problems {
+		if p.Fatal {
+			return true
+		}
+	}
+	return false
+}
+
+// reportStartupProblems writes a summary of the problems to w
+func reportStartupProblems(w io.Writer, problems []startupProblem) {
+	if len(problems) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "skillserver: startup check found %d problem(s):\n", len(problems))
+	for _, p := range problems {
+		level := "warning"
+		if p.Fatal {
+			level = "error"
+		}
+		fmt.Fprintf(w, "  - [%s] %s\n", level, p.Message)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+)
+
+var _ = Describe("Startup checks", func() {
+	Context("checkSkillsDir", func() {
+		var tempDir string
+
+		BeforeEach(func() {
+			var err error
+			tempDir, err = os.MkdirTemp("", "skillserver-startup-test")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.Chmod(filepath.Join(tempDir, "skills"), 0755)
+			os.RemoveAll(tempDir)
+		})
+
+		// readOnlyDir returns a directory that cannot be written to, skipping when permissions are not enforced
+		readOnlyDir := func() string {
+			dir := filepath.Join(tempDir, "skills")
+			Expect(os.Mkdir(dir, 0755)).To(Succeed())
+			Expect(os.Chmod(dir, 0555)).To(Succeed())
+			if probe, err := os.CreateTemp(dir, "probe-*"); err == nil {
+				probe.Close()
+				Skip("directory permissions are not enforced for this user")
+			}
+			return dir
+		}
+
+		It("should create a missing directory", func() {
+			dir := filepath.Join(tempDir, "skills")
+			problems, readOnly := checkSkillsDir(dir, nil)
+			Expect(problems).To(BeEmpty())
+			Expect(readOnly).To(BeFalse())
+			Expect(dir).To(BeADirectory())
+
+			entries, err := os.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+
+		It("should refuse a file", func() {
+			file := filepath.Join(tempDir, "skills")
+			Expect(os.WriteFile(file, []byte("not a directory"), 0644)).To(Succeed())
+			problems, _ := checkSkillsDir(file, nil)
+			Expect(problems).To(HaveLen(1))
+			Expect(problems[0].Fatal).To(BeTrue())
+			Expect(problems[0].Message).To(ContainSubstring(file))
+		})
+
+		It("should serve a directory that is not writable read-only when no git repositories are configured", func() {
+			problems, readOnly := checkSkillsDir(readOnlyDir(), nil)
+			Expect(readOnly).To(BeTrue())
+			Expect(problems).To(HaveLen(1))
+			Expect(problems[0].Fatal).To(BeFalse())
+			Expect(problems[0].Message).To(ContainSubstring("serving it read-only"))
+		})
+
+		It("should refuse a directory that is not writable when git repositories have to be synced", func() {
+			problems, readOnly := checkSkillsDir(readOnlyDir(), []string{"https://github.com/example/skills.git"})
+			Expect(readOnly).To(BeFalse())
+			Expect(problems).To(HaveLen(1))
+			Expect(problems[0].Fatal).To(BeTrue())
+			Expect(problems[0].Message).To(ContainSubstring("needed for git sync"))
+		})
+	})
+
+	Context("checkGitRepos", func() {
+		It("should report malformed URLs and repositories listed twice", func() {
+			problems := checkGitRepos([]string{
+				"https://github.com/example/skills.git",
+				"not a url",
+				"https://github.com/example/skills.git",
+			})
+			Expect(problems).To(HaveLen(2))
+			Expect(hasFatalProblem(problems)).To(BeFalse())
+			Expect(problems[1].Message).To(ContainSubstring("both map to directory"))
+		})
+	})
+
+	Context("checkGitProxy", func() {
+		It("should accept HTTP, HTTPS and SOCKS5 proxies", func() {
+			for _, proxy := range []string{"", "http://proxy:3128", "https://proxy:3128", "socks5://proxy:1080"} {
+				Expect(checkGitProxy(proxy)).To(BeEmpty(), proxy)
+			}
+		})
+
+		It("should refuse other proxy URLs", func() {
+			for _, proxy := range []string{"proxy:3128", "ftp://proxy:21", "http://"} {
+				Expect(hasFatalProblem(checkGitProxy(proxy))).To(BeTrue(), proxy)
+			}
+		})
+	})
+
+	Context("checkMCPTransport", func() {
+		It("should accept the stdio transport and a free HTTP path", func() {
+			Expect(checkMCPTransport("stdio", "", nil)).To(BeEmpty())
+			Expect(checkMCPTransport("http", "/mcp", nil)).To(BeEmpty())
+		})
+
+		It("should refuse unknown transports and paths taken by other routes", func() {
+			scopes := []domain.Scope{{Name: "ops"}}
+			Expect(hasFatalProblem(checkMCPTransport("grpc", "/mcp", nil))).To(BeTrue())
+			Expect(hasFatalProblem(checkMCPTransport("http", "mcp", nil))).To(BeTrue())
+			Expect(hasFatalProblem(checkMCPTransport("http", "/api/mcp", nil))).To(BeTrue())
+			Expect(hasFatalProblem(checkMCPTransport("http", "/mcp/ops", scopes))).To(BeTrue())
+		})
+	})
+
+	Context("reportStartupProblems", func() {
+		It("should list each problem with its level", func() {
+			var out bytes.Buffer
+			reportStartupProblems(&out, []startupProblem{
+				{Message: "first"},
+				{Fatal: true, Message: "second"},
+			})
+			Expect(out.String()).To(Equal("skillserver: startup check found 2 problem(s):\n  - [warning] first\n  - [error] second\n"))
+		})
+
+		It("should write nothing without problems", func() {
+			var out bytes.Buffer
+			reportStartupProblems(&out, nil)
+			Expect(out.String()).To(BeEmpty())
+		})
+	})
+})
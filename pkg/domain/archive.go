@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -11,47 +12,78 @@ import (
 	"strings"
 )
 
+// ExportStats describes the contents of an exported skill archive
+type ExportStats struct {
+	Files            int   // Number of regular files in the archive
+	UncompressedSize int64 // Total size of the files before compression
+}
+
 // ExportSkill creates a tar.gz archive containing the skill directory
 // Returns the archive data as bytes
 func ExportSkill(skillID string, skillsDir string) ([]byte, error) {
-	// Get the skill path
-	var skillPath string
-	if strings.Contains(skillID, "/") {
-		// Git repo skill
-		parts := strings.Split(skillID, "/")
-		if len(parts) == 2 {
-			repoName := parts[0]
-			skillDirName := parts[1]
-			repoPath := filepath.Join(skillsDir, repoName)
-			
-			// Find skill directory within repo
-			var err error
-			skillPath, err = findSkillDirByName(repoPath, skillDirName)
-			if err != nil {
-				return nil, fmt.Errorf("skill not found: %s", skillID)
-			}
-		} else {
-			return nil, fmt.Errorf("invalid skill ID format: %s", skillID)
-		}
-	} else {
-		// Local skill
-		skillPath = filepath.Join(skillsDir, skillID)
-		skillMdPath := filepath.Join(skillPath, "SKILL.md")
-		if _, err := os.Stat(skillMdPath); err != nil {
-			return nil, fmt.Errorf("skill not found: %s", skillID)
-		}
-	}
+	data, _, err := ExportSkillWithStats(skillID, skillsDir)
+	return data, err
+}
+
+// ExportSkillWithStats is like ExportSkill but also returns the file count and uncompressed size of the archive
+func ExportSkillWithStats(skillID string, skillsDir string) ([]byte, ExportStats, error) {
+	var stats ExportStats
 
-	// Get skill name (directory name)
-	skillName := filepath.Base(skillPath)
+	skillPath, err := exportSkillPath(skillID, skillsDir)
+	if err != nil {
+		return nil, stats, err
+	}
 
 	// Create a buffer to write the archive
 	var buf bytes.Buffer
 	gzw := gzip.NewWriter(&buf)
 	tw := tar.NewWriter(gzw)
 
-	// Walk the skill directory and add files to archive
-	err := filepath.Walk(skillPath, func(path string, info os.FileInfo, err error) error {
+	// Add the skill under its directory name
+	if err := addSkillToArchive(tw, skillPath, filepath.Base(skillPath), &stats); err != nil {
+		tw.Close()
+		gzw.Close()
+		return nil, stats, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	if err := closeArchive(tw, gzw); err != nil {
+		return nil, stats, err
+	}
+
+	return buf.Bytes(), stats, nil
+}
+
+// WriteSkillsArchive writes a tar.gz archive of several skills to w, each under a top-level directory
+// named after the skill ID, so git repository skills are nested under their repository name
+func WriteSkillsArchive(w io.Writer, skillIDs []string, skillsDir string) (ExportStats, error) {
+	var stats ExportStats
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+
+	for _, skillID := range skillIDs {
+		skillPath, err := exportSkillPath(skillID, skillsDir)
+		if err == nil {
+			err = addSkillToArchive(tw, skillPath, skillID, &stats)
+		}
+		if err != nil {
+			tw.Close()
+			gzw.Close()
+			return stats, fmt.Errorf("failed to add skill %s to archive: %w", skillID, err)
+		}
+	}
+
+	return stats, closeArchive(tw, gzw)
+}
+
+// exportSkillPath resolves the directory of a local or git repository skill to export
+func exportSkillPath(skillID string, skillsDir string) (string, error) {
+	return skillPathByName(skillsDir, skillID)
+}
+
+// addSkillToArchive walks a skill directory and adds its contents to tw under archiveDir
+func addSkillToArchive(tw *tar.Writer, skillPath string, archiveDir string, stats *ExportStats) error {
+	return filepath.Walk(skillPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -67,8 +99,8 @@ func ExportSkill(skillID string, skillsDir string) ([]byte, error) {
 			return err
 		}
 
-		// Create archive path: skill-name/relative-path
-		archivePath := filepath.Join(skillName, relPath)
+		// Create archive path: archive-dir/relative-path
+		archivePath := filepath.Join(archiveDir, relPath)
 		// Normalize path separators for tar format
 		archivePath = filepath.ToSlash(archivePath)
 
@@ -96,106 +128,182 @@ func ExportSkill(skillID string, skillsDir string) ([]byte, error) {
 		}
 		defer file.Close()
 
-		_, err = io.Copy(tw, file)
+		written, err := io.Copy(tw, file)
+		stats.Files++
+		stats.UncompressedSize += written
 		return err
 	})
+}
 
-	if err != nil {
-		tw.Close()
-		gzw.Close()
-		return nil, fmt.Errorf("failed to create archive: %w", err)
-	}
-
+// closeArchive flushes and closes the tar and gzip writers of an archive
+func closeArchive(tw *tar.Writer, gzw *gzip.Writer) error {
 	// Close tar writer
 	if err := tw.Close(); err != nil {
 		gzw.Close()
-		return nil, fmt.Errorf("failed to close tar writer: %w", err)
+		return fmt.Errorf("failed to close tar writer: %w", err)
 	}
 
 	// Close gzip writer
 	if err := gzw.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close gzip writer: %w", err)
+		return fmt.Errorf("failed to close gzip writer: %w", err)
 	}
+	return nil
+}
 
-	return buf.Bytes(), nil
+// ImportOptions controls how skill archives are extracted by ImportSkill
+// Modes stored in the archive are ignored, except for the executable bit of regular files
+type ImportOptions struct {
+	DirMode    os.FileMode
+	FileMode   os.FileMode
+	Extensions ExtensionPolicy // Archives containing disallowed files are rejected (SKILL.md is always allowed)
+	Name       string          // Import under this name instead of the archive's, rewriting the frontmatter name
+	Overwrite  bool            // Replace an existing local skill of the same name instead of failing
+	AutoFix    bool            // Import under the frontmatter name when the archive's directory is named differently, instead of failing
+
+	ResourceDirs        ResourceDirs // Directories resource archives may write to (nil = DefaultResourceDirs)
+	MaxResourceFileSize int64        // Largest file a resource archive may contain (0 = DefaultMaxResourceFileSize)
+
+	MaxExtractedSize int64 // Largest total size of the extracted files, guarding against archive bombs (0 = DefaultMaxExtractedSize)
+	MaxEntries       int   // Largest number of entries in the archive (0 = DefaultMaxArchiveEntries)
 }
 
-// ImportSkill extracts a tar.gz archive and imports the skill
-// Returns the skill name if successful
-func ImportSkill(archiveData []byte, skillsDir string) (string, error) {
-	// Create a reader from the archive data
-	r := bytes.NewReader(archiveData)
-	gzr, err := gzip.NewReader(r)
-	if err != nil {
-		return "", fmt.Errorf("failed to create gzip reader: %w", err)
+const (
+	// DefaultMaxExtractedSize is the largest total size of the files extracted from an imported skill archive by default
+	DefaultMaxExtractedSize = 500 * 1024 * 1024
+	// DefaultMaxArchiveEntries is the largest number of entries an imported skill archive may contain by default
+	DefaultMaxArchiveEntries = 10000
+)
+
+// ErrNameMismatch is returned when the name in an imported skill's frontmatter differs from the archive's directory name
+var ErrNameMismatch = errors.New("skill name in frontmatter does not match directory name")
+
+// ErrReadOnlySkill is returned when an import would overwrite a skill or repository synced from git
+var ErrReadOnlySkill = errors.New("cannot overwrite a read-only skill from a git repository")
+
+// DefaultImportOptions are the options used when importing a skill archive
+var DefaultImportOptions = ImportOptions{
+	DirMode:          0755,
+	FileMode:         0644,
+	MaxExtractedSize: DefaultMaxExtractedSize,
+	MaxEntries:       DefaultMaxArchiveEntries,
+}
+
+// limits returns the extraction limits, falling back to the defaults for unset ones
+func (m ImportOptions) limits() (maxSize int64, maxEntries int) {
+	maxSize, maxEntries = m.MaxExtractedSize, m.MaxEntries
+	if maxSize <= 0 {
+		maxSize = DefaultMaxExtractedSize
 	}
-	defer gzr.Close()
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxArchiveEntries
+	}
+	return maxSize, maxEntries
+}
 
-	tarReader := tar.NewReader(gzr)
+// maxResourceFileSize returns the largest file a resource archive may contain, falling back to the default
+func (m ImportOptions) maxResourceFileSize() int64 {
+	if m.MaxResourceFileSize <= 0 {
+		return DefaultMaxResourceFileSize
+	}
+	return m.MaxResourceFileSize
+}
 
-	var skillName string
-	var skillDir string
-	var hasSkillMd bool
+// fileMode returns the normalized mode for a regular file, keeping it executable if the archive marks it so
+func (m ImportOptions) fileMode(archiveMode int64) os.FileMode {
+	mode := m.FileMode.Perm()
+	if archiveMode&0111 != 0 {
+		// Grant execute to everyone who can read the file
+		mode |= (mode & 0444) >> 2
+	}
+	return mode
+}
 
+// ImportSkill extracts a tar.gz archive and imports the skill using DefaultImportOptions
+// Returns the skill name if successful
+func ImportSkill(archiveData []byte, skillsDir string) (string, error) {
+	return ImportSkillWithOptions(archiveData, skillsDir, DefaultImportOptions)
+}
+
+// ImportSkillWithOptions extracts a tar.gz archive and imports the skill, normalizing permissions
+// and enforcing the extension policy of opts
+// The skill is extracted and validated in a staging directory first, so an existing skill replaced
+// with opts.Overwrite is left untouched if the import fails
+// Returns the skill name if successful
+func ImportSkillWithOptions(archiveData []byte, skillsDir string, opts ImportOptions) (string, error) {
 	// First pass: validate archive structure and find skill name
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
+	skillName, skillMd, err := scanSkillArchive(archiveData, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// The directory is named after the skill, unless it is replaced by the target or frontmatter name anyway
+	if opts.Name == "" && !opts.AutoFix {
+		if err := ValidateSkillName(skillName); err != nil {
+			return "", fmt.Errorf("invalid skill name in archive: %w", err)
 		}
+	}
+	var frontmatterName string
+	if opts.Name == "" {
+		metadata, _, err := ParseFrontmatter(string(skillMd))
 		if err != nil {
-			return "", fmt.Errorf("failed to read tar header: %w", err)
+			return "", fmt.Errorf("failed to parse SKILL.md: %w", err)
 		}
-
-		// Extract skill name from first entry
-		if skillName == "" {
-			parts := strings.Split(header.Name, "/")
-			if len(parts) > 0 {
-				skillName = parts[0]
-				// Validate skill name
-				if err := ValidateSkillName(skillName); err != nil {
-					return "", fmt.Errorf("invalid skill name in archive: %w", err)
-				}
-				skillDir = filepath.Join(skillsDir, skillName)
-			}
+		frontmatterName = metadata.Name
+		if frontmatterName != skillName && !opts.AutoFix {
+			return "", fmt.Errorf("%w: SKILL.md names the skill %q but the archive's directory is %q; rename the directory to %q or the frontmatter name to %q",
+				ErrNameMismatch, frontmatterName, skillName, frontmatterName, skillName)
 		}
+	}
 
-		// Check for SKILL.md
-		if strings.HasSuffix(header.Name, "SKILL.md") {
-			hasSkillMd = true
+	// Determine the name to import the skill under
+	targetName := skillName
+	if opts.Name != "" {
+		if err := ValidateSkillName(opts.Name); err != nil {
+			return "", fmt.Errorf("invalid target name: %w", err)
 		}
-
-		// Validate path to prevent directory traversal
-		if strings.Contains(header.Name, "..") {
-			return "", fmt.Errorf("invalid path in archive: %s", header.Name)
+		targetName = opts.Name
+	} else if opts.AutoFix {
+		// The directory is renamed to match the frontmatter, e.g. after the archive's directory was renamed
+		if err := ValidateSkillName(frontmatterName); err != nil {
+			return "", fmt.Errorf("invalid skill name in frontmatter: %w", err)
 		}
+		targetName = frontmatterName
 	}
+	skillDir := filepath.Join(skillsDir, targetName)
 
-	if skillName == "" {
-		return "", fmt.Errorf("archive does not contain a skill directory")
+	// Check if skill already exists
+	exists := false
+	if _, err := os.Stat(skillDir); err == nil {
+		if !opts.Overwrite {
+			return "", fmt.Errorf("skill '%s' already exists", targetName)
+		}
+		if err := checkOverwritable(skillDir); err != nil {
+			return "", err
+		}
+		exists = true
 	}
 
-	if !hasSkillMd {
-		return "", fmt.Errorf("archive does not contain SKILL.md file")
+	// Extract into a hidden staging directory next to the skills, so the final rename is atomic
+	stagingRoot, err := os.MkdirTemp(skillsDir, ".import-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
 	}
-
-	// Check if skill already exists
-	existingSkillPath := filepath.Join(skillsDir, skillName)
-	if _, err := os.Stat(existingSkillPath); err == nil {
-		return "", fmt.Errorf("skill '%s' already exists", skillName)
+	defer os.RemoveAll(stagingRoot)
+	stagingDir := filepath.Join(stagingRoot, targetName)
+	if err := os.Mkdir(stagingDir, opts.DirMode.Perm()); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
 	}
 
-	// Reset reader for second pass
-	r = bytes.NewReader(archiveData)
-	gzr, err = gzip.NewReader(r)
+	// Second pass: extract files, counting what is actually written
+	gzr, err := gzip.NewReader(bytes.NewReader(archiveData))
 	if err != nil {
 		return "", fmt.Errorf("failed to create gzip reader: %w", err)
 	}
 	defer gzr.Close()
 
-	tarReader = tar.NewReader(gzr)
-
-	// Second pass: extract files
+	tarReader := tar.NewReader(gzr)
+	maxSize, _ := opts.limits()
+	var written int64
 	for {
 		header, err := tarReader.Next()
 		if err == io.EOF {
@@ -206,79 +314,240 @@ func ImportSkill(archiveData []byte, skillsDir string) (string, error) {
 		}
 
 		// Get relative path from skill name
-		parts := strings.Split(header.Name, "/")
-		if len(parts) < 2 {
+		_, relPath, err := splitArchivePath(header.Name)
+		if err != nil {
+			return "", err
+		}
+		if relPath == "" {
 			continue // Skip root directory entry
 		}
-		relPath := strings.Join(parts[1:], string(filepath.Separator))
-		targetPath := filepath.Join(skillsDir, skillName, relPath)
+		relPath = filepath.FromSlash(relPath)
+		targetPath := filepath.Join(stagingDir, relPath)
 
 		// Validate path to prevent directory traversal
 		if strings.Contains(relPath, "..") {
 			return "", fmt.Errorf("invalid path in archive: %s", header.Name)
 		}
 
-		// Ensure target is within skills directory
+		// Ensure target is within the skill directory being extracted
 		absTarget, err := filepath.Abs(targetPath)
 		if err != nil {
 			return "", fmt.Errorf("failed to get absolute path: %w", err)
 		}
-		absSkillsDir, err := filepath.Abs(skillsDir)
+		absStagingDir, err := filepath.Abs(stagingDir)
 		if err != nil {
 			return "", fmt.Errorf("failed to get absolute skills dir: %w", err)
 		}
-		if !strings.HasPrefix(absTarget, absSkillsDir) {
+		if absTarget != absStagingDir && !strings.HasPrefix(absTarget, absStagingDir+string(filepath.Separator)) {
 			return "", fmt.Errorf("invalid path: outside skills directory")
 		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
 			// Create directory
-			if err := os.MkdirAll(targetPath, os.FileMode(header.Mode)); err != nil {
+			if err := os.MkdirAll(targetPath, opts.DirMode.Perm()); err != nil {
 				return "", fmt.Errorf("failed to create directory: %w", err)
 			}
 		case tar.TypeReg:
 			// Create parent directories
-			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			if err := os.MkdirAll(filepath.Dir(targetPath), opts.DirMode.Perm()); err != nil {
 				return "", fmt.Errorf("failed to create parent directory: %w", err)
 			}
 
 			// Create file
-			outFile, err := os.OpenFile(targetPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode))
+			outFile, err := os.OpenFile(targetPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, opts.fileMode(header.Mode))
 			if err != nil {
 				return "", fmt.Errorf("failed to create file: %w", err)
 			}
 
-			// Copy file content
-			if _, err := io.Copy(outFile, tarReader); err != nil {
-				outFile.Close()
+			// Copy file content, stopping as soon as the limit is exceeded
+			n, err := io.Copy(outFile, io.LimitReader(tarReader, maxSize-written+1))
+			outFile.Close()
+			if err != nil {
 				return "", fmt.Errorf("failed to write file: %w", err)
 			}
-			outFile.Close()
+			written += n
+			if written > maxSize {
+				return "", fmt.Errorf("archive too large when extracted (max %d bytes)", maxSize)
+			}
 		}
 	}
 
 	// Validate the imported skill
-	skillMdPath := filepath.Join(skillDir, "SKILL.md")
+	skillMdPath := filepath.Join(stagingDir, "SKILL.md")
 	content, err := os.ReadFile(skillMdPath)
 	if err != nil {
-		os.RemoveAll(skillDir) // Clean up on error
 		return "", fmt.Errorf("failed to read SKILL.md: %w", err)
 	}
 
+	// A renamed import is a copy, so it gets the new name and no longer shares the original's stable ID
+	// An auto-fixed import keeps its frontmatter, which already has the name
+	if opts.Name != "" && targetName != skillName {
+		renamed, err := renameFrontmatter(string(content), targetName, false)
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(skillMdPath, []byte(renamed), opts.fileMode(0)); err != nil {
+			return "", fmt.Errorf("failed to rewrite SKILL.md: %w", err)
+		}
+		content = []byte(renamed)
+	}
+
 	metadata, _, err := ParseFrontmatter(string(content))
 	if err != nil {
-		os.RemoveAll(skillDir) // Clean up on error
 		return "", fmt.Errorf("failed to parse SKILL.md: %w", err)
 	}
 
 	// Validate that name in frontmatter matches directory name
-	if metadata.Name != skillName {
-		os.RemoveAll(skillDir) // Clean up on error
-		return "", fmt.Errorf("skill name in frontmatter (%s) does not match directory name (%s)", metadata.Name, skillName)
+	if metadata.Name != targetName {
+		return "", fmt.Errorf("%w: SKILL.md names the skill %q but it is imported as %q", ErrNameMismatch, metadata.Name, targetName)
+	}
+
+	if !exists {
+		if err := os.Rename(stagingDir, skillDir); err != nil {
+			return "", fmt.Errorf("failed to install skill: %w", err)
+		}
+		return targetName, nil
+	}
+
+	// Swap the existing skill out, restoring it if the new one cannot be moved in
+	previousDir := filepath.Join(stagingRoot, "previous")
+	if err := os.Rename(skillDir, previousDir); err != nil {
+		return "", fmt.Errorf("failed to replace skill: %w", err)
+	}
+	if err := os.Rename(stagingDir, skillDir); err != nil {
+		os.Rename(previousDir, skillDir)
+		return "", fmt.Errorf("failed to replace skill: %w", err)
 	}
 
-	return skillName, nil
+	return targetName, nil
+}
+
+// scanSkillArchive checks the structure of a skill archive without extracting it: a single top-level
+// directory with a SKILL.md, within the extraction limits and extension policy of opts
+// Returns the name of the directory and the content of its SKILL.md
+func scanSkillArchive(archiveData []byte, opts ImportOptions) (string, []byte, error) {
+	gzr, err := gzip.NewReader(bytes.NewReader(archiveData))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gzr.Close()
+
+	tarReader := tar.NewReader(gzr)
+
+	var skillName string
+	var skillMd []byte
+	var hasSkillMd bool
+
+	maxSize, maxEntries := opts.limits()
+	var entries int
+	var declaredSize int64
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read tar header: %w", err)
+		}
+
+		// Reject archive bombs before extracting anything
+		entries++
+		if entries > maxEntries {
+			return "", nil, fmt.Errorf("archive contains too many entries (max %d)", maxEntries)
+		}
+		if header.Typeflag == tar.TypeReg {
+			declaredSize += header.Size
+			if declaredSize > maxSize {
+				return "", nil, fmt.Errorf("archive too large when extracted (max %d bytes)", maxSize)
+			}
+		}
+
+		// Every entry must be in a single top-level directory, named after the skill
+		topDir, relPath, err := splitArchivePath(header.Name)
+		if err != nil {
+			return "", nil, err
+		}
+		if skillName == "" {
+			skillName = topDir
+		} else if topDir != skillName {
+			return "", nil, fmt.Errorf("archive must contain a single skill directory, found %s and %s", skillName, topDir)
+		}
+
+		// Check for SKILL.md at the top of the skill directory
+		if relPath == "SKILL.md" && header.Typeflag == tar.TypeReg {
+			hasSkillMd = true
+			skillMd, err = io.ReadAll(io.LimitReader(tarReader, header.Size))
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to read SKILL.md: %w", err)
+			}
+		}
+
+		// Reject archives containing disallowed file types
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) != "SKILL.md" {
+			if err := opts.Extensions.Check(header.Name); err != nil {
+				return "", nil, fmt.Errorf("invalid file in archive %s: %w", header.Name, err)
+			}
+		}
+	}
+
+	if skillName == "" {
+		return "", nil, fmt.Errorf("archive does not contain a skill directory")
+	}
+	if !hasSkillMd {
+		return "", nil, fmt.Errorf("archive does not contain SKILL.md file")
+	}
+	return skillName, skillMd, nil
+}
+
+// splitArchivePath splits the path of a skill archive entry into its top-level directory and the path
+// within it (empty for the directory itself), rejecting absolute paths, backslashes and empty, "." or ".." segments
+func splitArchivePath(name string) (topDir, relPath string, err error) {
+	segments := strings.Split(strings.TrimSuffix(name, "/"), "/")
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." || strings.Contains(segment, "\\") {
+			return "", "", fmt.Errorf("invalid path in archive: %s", name)
+		}
+	}
+	return segments[0], strings.Join(segments[1:], "/"), nil
+}
+
+// checkOverwritable refuses to overwrite anything but a local skill directory,
+// such as a git repository clone that shares the skill's name
+func checkOverwritable(skillDir string) error {
+	if _, err := os.Stat(filepath.Join(skillDir, ".git")); err == nil {
+		return ErrReadOnlySkill
+	}
+	if _, err := os.Stat(filepath.Join(skillDir, "SKILL.md")); err != nil {
+		return fmt.Errorf("cannot overwrite %s: not a skill directory", filepath.Base(skillDir))
+	}
+	return nil
+}
+
+// renameFrontmatter sets the name in the frontmatter of a SKILL.md, dropping its stable id unless keepID is set
+func renameFrontmatter(content, name string, keepID bool) (string, error) {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return "", fmt.Errorf("frontmatter is required (must start with ---)")
+	}
+
+	out := []string{lines[0]}
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "---" {
+			// End of frontmatter, keep the body untouched
+			return strings.Join(append(out, lines[i:]...), "\n"), nil
+		}
+		switch {
+		case strings.HasPrefix(line, "name:"):
+			line = "name: " + name
+		case strings.HasPrefix(line, "id:") && !keepID:
+			continue
+		}
+		out = append(out, line)
+	}
+	return "", fmt.Errorf("malformed frontmatter (missing closing ---)")
 }
 
 // findSkillDirByName recursively finds a skill directory by name within a base path
@@ -291,6 +560,9 @@ func findSkillDirByName(basePath, targetName string) (string, error) {
 		if !info.IsDir() {
 			return nil
 		}
+		if path != basePath && SkipSkillDiscovery(info.Name()) {
+			return filepath.SkipDir
+		}
 		// Check if this directory contains SKILL.md and matches the target name
 		skillMdPath := filepath.Join(path, "SKILL.md")
 		if _, err := os.Stat(skillMdPath); err == nil {
@@ -1,6 +1,10 @@
 package domain_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -57,6 +61,24 @@ Content here.
 			Expect(len(archiveData)).To(BeNumerically(">", 100)) // Should be a reasonable size
 		})
 
+		It("should report the file count and uncompressed size", func() {
+			skillDir := filepath.Join(tempDir, "stats-skill")
+			err := os.MkdirAll(filepath.Join(skillDir, "assets"), 0755)
+			Expect(err).NotTo(HaveOccurred())
+
+			skillMdContent := "---\nname: stats-skill\ndescription: Stats\n---\n"
+			err = os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(skillMdContent), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			err = os.WriteFile(filepath.Join(skillDir, "assets", "data.bin"), make([]byte, 4096), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			archiveData, stats, err := domain.ExportSkillWithStats("stats-skill", tempDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.Files).To(Equal(2))
+			Expect(stats.UncompressedSize).To(Equal(int64(len(skillMdContent) + 4096)))
+			Expect(int64(len(archiveData))).To(BeNumerically("<", stats.UncompressedSize))
+		})
+
 		It("should export git repo skills", func() {
 			// Create a git repo structure
 			repoDir := filepath.Join(tempDir, "test-repo")
@@ -136,6 +158,64 @@ Content here.
 			Expect(filepath.Join(importedSkillDir, "scripts", "test.sh")).To(BeAnExistingFile())
 		})
 
+		It("should normalize file modes but keep scripts executable", func() {
+			skillDir := filepath.Join(tempDir, "mode-skill")
+			scriptsDir := filepath.Join(skillDir, "scripts")
+			err := os.MkdirAll(scriptsDir, 0755)
+			Expect(err).NotTo(HaveOccurred())
+
+			skillMdPath := filepath.Join(skillDir, "SKILL.md")
+			err = os.WriteFile(skillMdPath, []byte("---\nname: mode-skill\ndescription: Modes\n---\n# Modes\n"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Chmod(skillMdPath, 0666)).To(Succeed())
+			scriptPath := filepath.Join(scriptsDir, "run.sh")
+			err = os.WriteFile(scriptPath, []byte("#!/bin/sh"), 0755)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Chmod(scriptPath, 0777)).To(Succeed())
+
+			archiveData, err := domain.ExportSkill("mode-skill", tempDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			importDir := filepath.Join(tempDir, "imported")
+			Expect(os.MkdirAll(importDir, 0755)).To(Succeed())
+			_, err = domain.ImportSkillWithOptions(archiveData, importDir, domain.ImportOptions{DirMode: 0750, FileMode: 0640})
+			Expect(err).NotTo(HaveOccurred())
+
+			info, err := os.Stat(filepath.Join(importDir, "mode-skill", "SKILL.md"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0640)))
+
+			info, err = os.Stat(filepath.Join(importDir, "mode-skill", "scripts", "run.sh"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+
+			info, err = os.Stat(filepath.Join(importDir, "mode-skill", "scripts"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+		})
+
+		It("should reject archives containing disallowed file types", func() {
+			skillDir := filepath.Join(tempDir, "exe-skill")
+			err := os.MkdirAll(filepath.Join(skillDir, "assets"), 0755)
+			Expect(err).NotTo(HaveOccurred())
+			err = os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: exe-skill\ndescription: Exe\n---\n# Exe\n"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			err = os.WriteFile(filepath.Join(skillDir, "assets", "tool.exe"), []byte("MZ"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			archiveData, err := domain.ExportSkill("exe-skill", tempDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			importDir := filepath.Join(tempDir, "imported")
+			Expect(os.MkdirAll(importDir, 0755)).To(Succeed())
+			opts := domain.DefaultImportOptions
+			opts.Extensions = domain.ExtensionPolicy{Allowed: []string{".md"}}
+			_, err = domain.ImportSkillWithOptions(archiveData, importDir, opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("tool.exe"))
+			Expect(filepath.Join(importDir, "exe-skill")).NotTo(BeADirectory())
+		})
+
 		It("should validate skill structure on import", func() {
 			// Create invalid archive (missing SKILL.md)
 			skillDir := filepath.Join(tempDir, "invalid-skill")
@@ -197,5 +277,250 @@ description: A duplicate skill
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("already exists"))
 		})
+
+		It("should import the same archive under different names", func() {
+			skillDir := filepath.Join(tempDir, "template-skill")
+			err := os.MkdirAll(skillDir, 0755)
+			Expect(err).NotTo(HaveOccurred())
+
+			skillMdContent := `---
+name: template-skill
+id: template-id
+description: A template skill
+---
+# Template
+name: not frontmatter
+`
+			err = os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(skillMdContent), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			archiveData, err := domain.ExportSkill("template-skill", tempDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, name := range []string{"copy-one", "copy-two"} {
+				opts := domain.DefaultImportOptions
+				opts.Name = name
+				skillName, err := domain.ImportSkillWithOptions(archiveData, tempDir, opts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(skillName).To(Equal(name))
+
+				content, err := os.ReadFile(filepath.Join(tempDir, name, "SKILL.md"))
+				Expect(err).NotTo(HaveOccurred())
+				metadata, body, err := domain.ParseFrontmatter(string(content))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metadata.Name).To(Equal(name))
+				Expect(metadata.ID).To(BeEmpty())
+				Expect(body).To(ContainSubstring("name: not frontmatter"))
+			}
+
+			// The original is untouched
+			Expect(filepath.Join(tempDir, "template-skill", "SKILL.md")).To(BeAnExistingFile())
+		})
+
+		It("should reject an invalid target name", func() {
+			skillDir := filepath.Join(tempDir, "rename-skill")
+			err := os.MkdirAll(skillDir, 0755)
+			Expect(err).NotTo(HaveOccurred())
+			err = os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: rename-skill\ndescription: d\n---\n"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			archiveData, err := domain.ExportSkill("rename-skill", tempDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			opts := domain.DefaultImportOptions
+			opts.Name = "Invalid_Name"
+			_, err = domain.ImportSkillWithOptions(archiveData, tempDir, opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid target name"))
+		})
+
+		Context("with overwrite", func() {
+			var (
+				skillDir  string
+				sourceDir string
+			)
+
+			// exportVersion exports a copy of overwrite-skill with the given frontmatter name and body
+			exportVersion := func(name, body string) []byte {
+				dir := filepath.Join(sourceDir, "overwrite-skill")
+				Expect(os.RemoveAll(dir)).To(Succeed())
+				Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: "+name+"\ndescription: d\n---\n"+body), 0644)).To(Succeed())
+				archiveData, err := domain.ExportSkill("overwrite-skill", sourceDir)
+				Expect(err).NotTo(HaveOccurred())
+				return archiveData
+			}
+
+			BeforeEach(func() {
+				sourceDir = filepath.Join(tempDir, "source")
+				skillDir = filepath.Join(tempDir, "overwrite-skill")
+				Expect(os.MkdirAll(filepath.Join(skillDir, "scripts"), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: overwrite-skill\ndescription: d\n---\n# Old"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(skillDir, "scripts", "old.sh"), []byte("#!/bin/sh"), 0755)).To(Succeed())
+			})
+
+			It("should replace the existing skill", func() {
+				opts := domain.DefaultImportOptions
+				opts.Overwrite = true
+				skillName, err := domain.ImportSkillWithOptions(exportVersion("overwrite-skill", "# New"), tempDir, opts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(skillName).To(Equal("overwrite-skill"))
+
+				content, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("# New"))
+				Expect(filepath.Join(skillDir, "scripts", "old.sh")).NotTo(BeAnExistingFile())
+
+				// No staging directories are left behind
+				matches, err := filepath.Glob(filepath.Join(tempDir, ".import-*"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(BeEmpty())
+			})
+
+			It("should leave the existing skill untouched if the new one is invalid", func() {
+				opts := domain.DefaultImportOptions
+				opts.Overwrite = true
+				_, err := domain.ImportSkillWithOptions(exportVersion("other-name", "# New"), tempDir, opts)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("does not match"))
+
+				content, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("# Old"))
+				Expect(filepath.Join(skillDir, "scripts", "old.sh")).To(BeAnExistingFile())
+			})
+
+			It("should refuse to overwrite a git repository", func() {
+				Expect(os.Mkdir(filepath.Join(skillDir, ".git"), 0755)).To(Succeed())
+
+				opts := domain.DefaultImportOptions
+				opts.Overwrite = true
+				_, err := domain.ImportSkillWithOptions(exportVersion("overwrite-skill", "# New"), tempDir, opts)
+				Expect(err).To(MatchError(domain.ErrReadOnlySkill))
+
+				content, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("# Old"))
+			})
+		})
+
+		Context("with an unexpected layout", func() {
+			// tarGz creates a tar.gz with the given files, in order
+			tarGz := func(files ...string) []byte {
+				var buf bytes.Buffer
+				gzw := gzip.NewWriter(&buf)
+				tw := tar.NewWriter(gzw)
+				for i := 0; i < len(files); i += 2 {
+					content := []byte(files[i+1])
+					Expect(tw.WriteHeader(&tar.Header{Name: files[i], Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+					_, err := tw.Write(content)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(tw.Close()).To(Succeed())
+				Expect(gzw.Close()).To(Succeed())
+				return buf.Bytes()
+			}
+			skillMd := "---\nname: docker\ndescription: Docker\n---\n# Docker\n"
+
+			It("should reject entries outside the skill directory", func() {
+				_, err := domain.ImportSkill(tarGz("docker/SKILL.md", skillMd, "other/notes.md", "x"), tempDir)
+				Expect(err).To(MatchError(ContainSubstring("single skill directory")))
+				Expect(filepath.Join(tempDir, "docker")).NotTo(BeADirectory())
+				Expect(filepath.Join(tempDir, "other")).NotTo(BeADirectory())
+			})
+
+			It("should reject unclean top-level paths", func() {
+				for _, name := range []string{"./docker/SKILL.md", "/docker/SKILL.md", "docker//SKILL.md", `docker\SKILL.md`} {
+					_, err := domain.ImportSkill(tarGz(name, skillMd), tempDir)
+					Expect(err).To(MatchError(ContainSubstring("invalid path")), "path %q should be rejected", name)
+				}
+
+				// Even when importing under another name
+				opts := domain.DefaultImportOptions
+				opts.Name = "renamed"
+				_, err := domain.ImportSkillWithOptions(tarGz("../docker/SKILL.md", skillMd), tempDir, opts)
+				Expect(err).To(MatchError(ContainSubstring("invalid path")))
+			})
+
+			It("should require SKILL.md at the top of the skill directory", func() {
+				_, err := domain.ImportSkill(tarGz("wrapper/docker/SKILL.md", skillMd), tempDir)
+				Expect(err).To(MatchError(ContainSubstring("does not contain SKILL.md")))
+			})
+
+			It("should reject a frontmatter name that differs from the directory before extracting", func() {
+				_, err := domain.ImportSkill(tarGz("kubernetes/SKILL.md", skillMd), tempDir)
+				Expect(err).To(MatchError(domain.ErrNameMismatch))
+				Expect(err.Error()).To(ContainSubstring(`SKILL.md names the skill "docker" but the archive's directory is "kubernetes"`))
+				Expect(err.Error()).To(ContainSubstring(`rename the directory to "docker"`))
+
+				matches, err := filepath.Glob(filepath.Join(tempDir, ".import-*"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(BeEmpty())
+			})
+
+			It("should import under the frontmatter name with AutoFix", func() {
+				opts := domain.DefaultImportOptions
+				opts.AutoFix = true
+				name, err := domain.ImportSkillWithOptions(tarGz("Renamed Dir/SKILL.md", skillMd, "Renamed Dir/notes.md", "x"), tempDir, opts)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(name).To(Equal("docker"))
+
+				content, err := os.ReadFile(filepath.Join(tempDir, "docker", "SKILL.md"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal(skillMd))
+				Expect(filepath.Join(tempDir, "docker", "notes.md")).To(BeARegularFile())
+				Expect(filepath.Join(tempDir, "Renamed Dir")).NotTo(BeADirectory())
+			})
+		})
+
+		Context("with extraction limits", func() {
+			// buildArchive creates a tar.gz of bomb-skill with a SKILL.md and the given extra files
+			buildArchive := func(files map[string][]byte) []byte {
+				var buf bytes.Buffer
+				gzw := gzip.NewWriter(&buf)
+				tw := tar.NewWriter(gzw)
+				files["bomb-skill/SKILL.md"] = []byte("---\nname: bomb-skill\ndescription: d\n---\n")
+				for name, content := range files {
+					Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+					_, err := tw.Write(content)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(tw.Close()).To(Succeed())
+				Expect(gzw.Close()).To(Succeed())
+				return buf.Bytes()
+			}
+
+			It("should reject an archive that decompresses beyond the limit", func() {
+				archiveData := buildArchive(map[string][]byte{
+					"bomb-skill/assets/zeros.bin": make([]byte, 4*1024*1024),
+				})
+				Expect(len(archiveData)).To(BeNumerically("<", 16*1024))
+
+				opts := domain.DefaultImportOptions
+				opts.MaxExtractedSize = 1024 * 1024
+				_, err := domain.ImportSkillWithOptions(archiveData, tempDir, opts)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("too large when extracted"))
+				Expect(filepath.Join(tempDir, "bomb-skill")).NotTo(BeADirectory())
+
+				matches, err := filepath.Glob(filepath.Join(tempDir, ".import-*"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(matches).To(BeEmpty())
+			})
+
+			It("should reject an archive with too many entries", func() {
+				files := map[string][]byte{}
+				for i := 0; i < 20; i++ {
+					files[fmt.Sprintf("bomb-skill/references/%d.md", i)] = []byte("x")
+				}
+
+				opts := domain.DefaultImportOptions
+				opts.MaxEntries = 10
+				_, err := domain.ImportSkillWithOptions(buildArchive(files), tempDir, opts)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("too many entries"))
+				Expect(filepath.Join(tempDir, "bomb-skill")).NotTo(BeADirectory())
+			})
+		})
 	})
 })
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrSkillExists is returned when a skill would be created over an existing one
+var ErrSkillExists = errors.New("skill already exists")
+
+// CopySkill copies a skill and its files to a new local skill named newName, e.g. to start a new skill
+// from an existing one, and returns the copy
+// Git repository skills are copied with their local overlay applied, and the copy is editable.
+// The copy's frontmatter name is rewritten to newName and its stable ID dropped, as IDs must be unique
+func (m *FileSystemManager) CopySkill(skillID, newName string) (*Skill, error) {
+	if err := ValidateSkillName(newName); err != nil {
+		return nil, err
+	}
+	skill, err := m.ReadSkill(skillID)
+	if err != nil {
+		return nil, err
+	}
+
+	targetDir := filepath.Join(m.skillsDir, newName)
+	if _, err := os.Lstat(targetDir); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSkillExists, newName)
+	}
+
+	// Copy into a hidden staging directory next to the skills, so the copy appears at once
+	stagingRoot, err := os.MkdirTemp(m.skillsDir, ".copy-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(stagingRoot)
+	stagingDir := filepath.Join(stagingRoot, newName)
+
+	if err := copySkillFiles(skill.SourcePath, stagingDir); err != nil {
+		return nil, fmt.Errorf("failed to copy skill: %w", err)
+	}
+	if overlayDir := m.overlaySkillDir(skill.SourcePath); overlayDir != "" {
+		if _, err := os.Stat(overlayDir); err == nil {
+			if err := copySkillFiles(overlayDir, stagingDir); err != nil {
+				return nil, fmt.Errorf("failed to copy skill overlay: %w", err)
+			}
+		}
+	}
+
+	skillMdPath := filepath.Join(stagingDir, "SKILL.md")
+	content, err := os.ReadFile(skillMdPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SKILL.md: %w", err)
+	}
+	renamed, err := renameFrontmatter(string(content), newName, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(skillMdPath, []byte(renamed), 0644); err != nil {
+		return nil, fmt.Errorf("failed to rewrite SKILL.md: %w", err)
+	}
+
+	if err := os.Rename(stagingDir, targetDir); err != nil {
+		return nil, fmt.Errorf("failed to install skill copy: %w", err)
+	}
+	return m.ReadSkill(newName)
+}
+
+// copySkillFiles copies the files of a skill directory into dst, preserving file modes and symlinks
+// Hidden directories (such as a repository's .git) and nested skills are left out
+func copySkillFiles(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relPath)
+
+		if d.IsDir() {
+			if relPath != "." {
+				if strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				if _, err := os.Stat(filepath.Join(path, "SKILL.md")); err == nil {
+					return filepath.SkipDir
+				}
+			}
+			return os.MkdirAll(target, 0755)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			os.Remove(target) // An overlay file replaces the copied one
+			return os.Symlink(link, target)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		os.Remove(target) // An overlay file replaces the copied one, even a symlink
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
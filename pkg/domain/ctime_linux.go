@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// changeTime returns the inode change time of a file, or its modification time if unavailable
+func changeTime(info os.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(stat.Ctim.Sec, stat.Ctim.Nsec)
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package domain
+
+import (
+	"os"
+	"time"
+)
+
+// changeTime returns the modification time of a file, as the inode change time is only read on Linux
+func changeTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
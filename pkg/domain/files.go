@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ValidateSkillFilePath validates the path of a file within a skill directory: it must be relative,
+// stay within the skill and not be hidden, like .git or the .skillmeta.json sidecar
+func ValidateSkillFilePath(path string) error {
+	if path == "" {
+		return fmt.Errorf("file path is required")
+	}
+	if filepath.IsAbs(path) {
+		return fmt.Errorf("file path must be relative")
+	}
+	if strings.Contains(path, "..") {
+		return fmt.Errorf("file path cannot contain '..'")
+	}
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if strings.HasPrefix(part, ".") {
+			return fmt.Errorf("file path cannot contain hidden files or directories")
+		}
+	}
+	return nil
+}
+
+// ReadSkillFile reads a file of a skill by its path relative to the skill root, e.g. a USAGE.md shipped next
+// to SKILL.md, unlike ReadSkillResource which only reads files in the resource directories
+// Files of git repository skills are read from the local overlay when it has a copy
+func (m *FileSystemManager) ReadSkillFile(skillID, filePath string) (*ResourceContent, error) {
+	if err := ValidateSkillFilePath(filePath); err != nil {
+		return nil, err
+	}
+
+	skillPath, err := m.getSkillPath(skillID)
+	if err != nil {
+		return nil, err
+	}
+
+	fullPath, err := m.resourceFile(skillPath, filePath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("failed to read file: %s is not a file: %w", filePath, os.ErrNotExist)
+	}
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return encodeResourceContent(skillPath, filePath, content)
+}
+
+// ListSkillFiles lists every file of a skill but SKILL.md: its resources, and the files at the skill root or
+// in other directories, typed ResourceTypeOther
+// Hidden files, nested skills and symlinks leading outside the skill are left out
+func (m *FileSystemManager) ListSkillFiles(skillID string) ([]SkillResource, error) {
+	skillPath, err := m.getSkillPath(skillID)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := m.listFiles(skillPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Files in the local overlay shadow the git ones with the same path
+	if overlayDir := m.overlaySkillDir(skillPath); overlayDir != "" {
+		if _, err := os.Stat(overlayDir); err == nil {
+			overlaid, err := m.listFiles(overlayDir)
+			if err != nil {
+				return nil, err
+			}
+			files = shadowResources(files, overlaid)
+		}
+	}
+
+	applySkillMeta(skillPath, files)
+	return files, nil
+}
+
+// listFiles lists the files of a skill directory but SKILL.md
+func (m *FileSystemManager) listFiles(skillPath string) ([]SkillResource, error) {
+	resourceDirs := m.ResourceDirs()
+	files := []SkillResource{}
+
+	err := filepath.WalkDir(skillPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == skillPath {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			// Nested skills are listed on their own
+			if _, err := os.Stat(filepath.Join(path, "SKILL.md")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(skillPath, path)
+		if err != nil || relPath == "SKILL.md" {
+			return nil
+		}
+		fullPath, err := ResolveResourcePath(skillPath, relPath)
+		if err != nil {
+			// Skip symlinks leading outside the skill
+			return nil
+		}
+		info, err := os.Stat(fullPath)
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+
+		// Read file to detect MIME type
+		content, err := os.ReadFile(fullPath)
+		if err != nil {
+			return nil
+		}
+		mimeType := DetectMimeType(entry.Name(), content)
+		readable := IsTextFile(mimeType)
+		lfsPointer := IsLFSPointer(info.Size(), content)
+		if lfsPointer {
+			readable = false
+		}
+
+		fileType := ResourceTypeOther
+		if dir, ok := resourceDirs.dirOf(relPath); ok {
+			fileType = dir.Type
+		}
+		files = append(files, SkillResource{
+			Type:       fileType,
+			Path:       filepath.ToSlash(relPath),
+			Name:       entry.Name(),
+			Size:       info.Size(),
+			MimeType:   mimeType,
+			Readable:   readable,
+			Modified:   info.ModTime(),
+			LFSPointer: lfsPointer,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list skill files: %w", err)
+	}
+	return files, nil
+}
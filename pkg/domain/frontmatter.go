@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FrontmatterField is a frontmatter key to set by UpdateFrontmatter
+type FrontmatterField struct {
+	Key   string
+	Value any // Encoded as YAML; nil removes the key
+}
+
+// FormatFrontmatter renders metadata as a YAML frontmatter block between --- fences, followed by a blank line
+// Values are quoted and escaped as needed, so any description or other string parses back unchanged
+func FormatFrontmatter(metadata *SkillMetadata) (string, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(metadata); err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	return "---\n" + out.String() + "---\n\n", nil
+}
+
+// splitFrontmatter splits SKILL.md content into the YAML between the --- fences and what follows the closing fence
+func splitFrontmatter(content string) (frontmatter, rest string, err error) {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "---") {
+		return "", "", fmt.Errorf("frontmatter is required (must start with ---)")
+	}
+
+	// Find the end of frontmatter
+	endIdx := strings.Index(content[3:], "---")
+	if endIdx == -1 {
+		return "", "", fmt.Errorf("malformed frontmatter (missing closing ---)")
+	}
+	return content[3 : endIdx+3], content[endIdx+6:], nil
+}
+
+// UpdateFrontmatter rewrites SKILL.md content with the given frontmatter fields set and body as its content
+// The frontmatter is edited in place: other keys, their order and comments are kept, fields that are not
+// present yet are appended. The body is written exactly as given, after the same blank lines as before
+func UpdateFrontmatter(content, body string, fields []FrontmatterField) (string, error) {
+	frontmatter, rest, err := splitFrontmatter(content)
+	if err != nil {
+		return "", err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(frontmatter), &doc); err != nil {
+		return "", fmt.Errorf("failed to parse frontmatter: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return "", fmt.Errorf("frontmatter must be a mapping")
+	}
+
+	for _, field := range fields {
+		idx := -1
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value == field.Key {
+				idx = i
+				break
+			}
+		}
+
+		if field.Value == nil {
+			if idx >= 0 {
+				root.Content = append(root.Content[:idx], root.Content[idx+2:]...)
+			}
+			continue
+		}
+		var value yaml.Node
+		if err := value.Encode(field.Value); err != nil {
+			return "", fmt.Errorf("failed to encode frontmatter %s: %w", field.Key, err)
+		}
+		if idx >= 0 {
+			// Keep the formatting of values that did not change
+			if !sameYAMLValue(root.Content[idx+1], &value) {
+				root.Content[idx+1] = &value
+			}
+		} else {
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: field.Key}, &value)
+		}
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to encode frontmatter: %w", err)
+	}
+
+	// Keep the separator between the closing fence and the body
+	separator := rest[:len(rest)-len(strings.TrimLeft(rest, " \t\r\n"))]
+	if separator == "" {
+		separator = "\n\n"
+	}
+	return "---\n" + out.String() + "---" + separator + body, nil
+}
+
+// sameYAMLValue reports whether two YAML nodes hold the same value, whatever their style
+func sameYAMLValue(a, b *yaml.Node) bool {
+	var va, vb any
+	if a.Decode(&va) != nil || b.Decode(&vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"log/slog"
+	"path/filepath"
+)
+
+// SkillIssue is a skill left out of listings because it cannot be read, such as one whose frontmatter
+// is invalid or names the skill differently from its directory, or a git repository skills manifest
+// that cannot be used
+type SkillIssue struct {
+	Path  string // Skill directory, relative to the skills directory, with forward slashes
+	Name  string // Name the skill would have been listed under
+	Error string // Why the skill was skipped
+}
+
+// recordIssues keeps the skills skipped by the last scan, logging those that were not skipped by the previous one
+// so a broken skill is reported once rather than on every listing
+func (m *FileSystemManager) recordIssues(issues []SkillIssue) {
+	m.mu.Lock()
+	previous := make(map[SkillIssue]bool, len(m.issues))
+	for _, issue := range m.issues {
+		previous[issue] = true
+	}
+	m.issues = issues
+	m.mu.Unlock()
+
+	for _, issue := range issues {
+		if !previous[issue] {
+			m.logf(slog.LevelWarn, "skipping skill %s: %s", issue.Name, issue.Error)
+		}
+	}
+}
+
+// SkillIssues rescans the skills and returns those left out of listings, with the reason, so authors can
+// find out why a skill is missing
+func (m *FileSystemManager) SkillIssues() ([]SkillIssue, error) {
+	if _, err := m.ListSkills(); err != nil {
+		return nil, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]SkillIssue{}, m.issues...), nil
+}
+
+// skillIssue describes why the skill at skillPath, to be listed as skillName, was skipped
+func (m *FileSystemManager) skillIssue(skillPath, skillName string, err error) SkillIssue {
+	relPath, relErr := filepath.Rel(m.skillsDir, skillPath)
+	if relErr != nil {
+		relPath = skillPath
+	}
+	return SkillIssue{Path: filepath.ToSlash(relPath), Name: skillName, Error: err.Error()}
+}
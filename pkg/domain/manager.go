@@ -1,11 +1,19 @@
 package domain
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 )
 
 // SkillManager defines the interface for managing skills
@@ -21,28 +29,96 @@ type SkillManager interface {
 	GetSkillResourceInfo(skillID, resourcePath string) (*SkillResource, error)
 }
 
+// CacheInvalidator is implemented by SkillManagers that cache skill reads
+// InvalidateCache drops the cached entry for skillID, or every entry if skillID is empty,
+// so that the next read goes to disk
+type CacheInvalidator interface {
+	InvalidateCache(skillID string)
+}
+
+// SkillExporter is implemented by SkillManagers that can export a skill directory
+// ExportSkill returns a tar.gz archive of the skill, as created by the package-level ExportSkill
+type SkillExporter interface {
+	ExportSkill(skillID string) ([]byte, error)
+}
+
+// SkillFileReader is implemented by SkillManagers that can read any file of a skill directory
+// ListSkillFiles lists every file of the skill but SKILL.md, including those outside the resource
+// directories (typed ResourceTypeOther); ReadSkillFile reads a file by its path relative to the skill root,
+// such as a USAGE.md next to SKILL.md, encoded like ReadSkillResource
+type SkillFileReader interface {
+	ListSkillFiles(skillID string) ([]SkillResource, error)
+	ReadSkillFile(skillID, filePath string) (*ResourceContent, error)
+}
+
+// ResourceOpener is implemented by SkillManagers that can stream resource content
+// OpenSkillResource opens a resource for reading without loading it into memory; the caller closes it
+type ResourceOpener interface {
+	OpenSkillResource(skillID, resourcePath string) (io.ReadSeekCloser, error)
+}
+
+// SkillSearcher is implemented by SkillManagers that can narrow a search with SearchOptions
+type SkillSearcher interface {
+	SearchSkillsWithOptions(query string, opts SearchOptions) ([]Skill, error)
+}
+
 // FileSystemManager implements SkillManager using the file system
 type FileSystemManager struct {
 	skillsDir string
 	searcher  *Searcher
 	gitRepos  []string // List of git repo directory names (for read-only detection)
+
+	indexMu   sync.Mutex   // Serializes rebuilds, so the last one to finish indexes the latest skills
+	reindexMu sync.Mutex   // Guards reindex
+	reindex   reindexState // Rebuild requested with RequestReindex
+
+	mu               sync.RWMutex               // Guards stableIDs, stableIDRescan, issues, manifests, resourceDirs, overlayDir, maxContentLength, strictNames and logger
+	stableIDs        map[string]string          // Frontmatter id -> skill name (location), refreshed by ListSkills and kept up to date on writes
+	stableIDRescan   time.Time                  // When a stable ID lookup last rescanned the skills
+	issues           []SkillIssue               // Skills skipped by the last ListSkills, with the reason
+	manifests        map[string]*SkillsManifest // Skills manifests of the git repositories, by directory name, read by the last ListSkills
+	resourceDirs     ResourceDirs               // Subdirectories holding resources
+	overlayDir       string                     // Local overlay for git repository skills (empty = disabled)
+	maxContentLength int                        // Longest SKILL.md body read, in bytes
+	strictNames      bool                       // Skip skills whose frontmatter name differs from their directory name
+	logger           *slog.Logger               // Warnings about skipped skills (nil = discarded)
+
+	thumbnails *thumbnailCache // Generated resource thumbnails
 }
 
-// NewFileSystemManager creates a new FileSystemManager
+// NewFileSystemManager creates a new FileSystemManager keeping its search index in skillsDir/.index
 func NewFileSystemManager(skillsDir string, gitRepos []string) (*FileSystemManager, error) {
+	return NewFileSystemManagerWithIndexDir(skillsDir, filepath.Join(skillsDir, LegacyIndexDirName), gitRepos)
+}
+
+// NewFileSystemManagerWithIndexDir creates a new FileSystemManager keeping its search index in indexDir,
+// e.g. DefaultIndexDir(skillsDir)
+// An index left in skillsDir/.index by earlier versions is removed when indexDir is elsewhere
+func NewFileSystemManagerWithIndexDir(skillsDir, indexDir string, gitRepos []string) (*FileSystemManager, error) {
 	if err := os.MkdirAll(skillsDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create skills directory: %w", err)
 	}
 
-	searcher, err := NewSearcher(skillsDir)
+	legacyIndexDir := filepath.Join(skillsDir, LegacyIndexDirName)
+	if filepath.Clean(indexDir) != legacyIndexDir {
+		if err := os.RemoveAll(legacyIndexDir); err != nil {
+			return nil, fmt.Errorf("failed to remove old search index: %w", err)
+		}
+	}
+
+	searcher, err := NewSearcher(indexDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create searcher: %w", err)
 	}
 
 	manager := &FileSystemManager{
-		skillsDir: skillsDir,
-		searcher:  searcher,
-		gitRepos:  gitRepos,
+		skillsDir:        skillsDir,
+		searcher:         searcher,
+		gitRepos:         gitRepos,
+		resourceDirs:     DefaultResourceDirs,
+		maxContentLength: DefaultMaxContentLength,
+		reindex:          reindexState{delay: DefaultReindexDelay},
+		thumbnails:       newThumbnailCache(),
 	}
 
 	// Initial index build
@@ -72,8 +148,22 @@ func (m *FileSystemManager) isGitRepoPath(path string) bool {
 	return false
 }
 
+// noiseDirs are directories that never hold skills but can be huge, such as installed dependencies
+var noiseDirs = map[string]bool{
+	"node_modules": true,
+	"__pycache__":  true,
+}
+
+// SkipSkillDiscovery reports whether a directory is left out when looking for skills:
+// hidden directories such as .git or the search index, and noiseDirs
+// Everything looking for skills (listing, watching, dry runs of git repositories) skips the same directories
+func SkipSkillDiscovery(name string) bool {
+	return strings.HasPrefix(name, ".") || noiseDirs[name]
+}
+
 // findSkillDirs recursively finds all directories containing SKILL.md files
-func (m *FileSystemManager) findSkillDirs(root string, basePath string) ([]string, error) {
+// The git repositories in manifests, keyed by directory name within root, list their skills instead
+func (m *FileSystemManager) findSkillDirs(root string, basePath string, manifests map[string]*SkillsManifest) ([]string, error) {
 	var skillDirs []string
 
 	entries, err := os.ReadDir(root)
@@ -86,7 +176,24 @@ func (m *FileSystemManager) findSkillDirs(root string, basePath string) ([]strin
 			continue
 		}
 
+		if SkipSkillDiscovery(entry.Name()) {
+			continue
+		}
+
 		entryPath := filepath.Join(root, entry.Name())
+		// The search index may be configured to live among the skills
+		if m.searcher.ownsPath(entryPath) {
+			continue
+		}
+
+		var listed []string
+		if manifest := manifests[entry.Name()]; manifest != nil {
+			listed = manifestSkillDirs(manifest, entryPath, basePath)
+			skillDirs = append(skillDirs, listed...)
+			if !manifest.Discover {
+				continue
+			}
+		}
 
 		// Check if this directory contains SKILL.md
 		skillMdPath := filepath.Join(entryPath, "SKILL.md")
@@ -97,21 +204,41 @@ func (m *FileSystemManager) findSkillDirs(root string, basePath string) ([]strin
 		}
 
 		// Recursively search subdirectories (for git repos)
-		subDirs, err := m.findSkillDirs(entryPath, basePath)
+		subDirs, err := m.findSkillDirs(entryPath, basePath, nil)
 		if err == nil {
-			skillDirs = append(skillDirs, subDirs...)
+			// Skills both listed in a manifest and discovered are only listed once
+			for _, subDir := range subDirs {
+				if !slices.Contains(listed, subDir) {
+					skillDirs = append(skillDirs, subDir)
+				}
+			}
 		}
 	}
 
 	return skillDirs, nil
 }
 
+// manifestSkillDirs returns the directories of the skills listed in the manifest of the repository at
+// repoDir, relative to basePath
+func manifestSkillDirs(manifest *SkillsManifest, repoDir, basePath string) []string {
+	var skillDirs []string
+	for _, skill := range manifest.Skills {
+		relPath, _ := filepath.Rel(basePath, filepath.Join(repoDir, filepath.FromSlash(skill.Path)))
+		skillDirs = append(skillDirs, relPath)
+	}
+	return skillDirs
+}
+
 // ListSkills returns all skills (local and from git repos)
 func (m *FileSystemManager) ListSkills() ([]Skill, error) {
 	var skills []Skill
 
-	// Find all directories containing SKILL.md
-	skillDirs, err := m.findSkillDirs(m.skillsDir, m.skillsDir)
+	// Find all directories containing SKILL.md, or listed in the manifest of their git repository
+	manifests, issues := m.repoManifests()
+	m.mu.Lock()
+	m.manifests = manifests
+	m.mu.Unlock()
+	skillDirs, err := m.findSkillDirs(m.skillsDir, m.skillsDir, manifests)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find skill directories: %w", err)
 	}
@@ -126,7 +253,7 @@ func (m *FileSystemManager) ListSkills() ([]Skill, error) {
 			continue
 		}
 		parts := strings.Split(relPath, string(filepath.Separator))
-		
+
 		// Check if this skill is from a git repo (path has multiple parts and first part is a repo name)
 		if len(parts) > 1 {
 			repoName := parts[0]
@@ -147,15 +274,9 @@ func (m *FileSystemManager) ListSkills() ([]Skill, error) {
 
 		var skillName string
 		if isReadOnly {
-			// For git repo skills, use repoName/directoryName format
-			if len(parts) >= 2 {
-				// Extract repo name and skill directory name
-				repoName := parts[0]
-				skillDirName := parts[len(parts)-1]
-				skillName = fmt.Sprintf("%s/%s", repoName, skillDirName)
-			} else {
-				skillName = skillDir
-			}
+			// For git repo skills, use the path within the skills directory (repoName/category/skillName),
+			// so skills sharing a directory name in different folders of a repo stay distinct
+			skillName = strings.Join(parts, "/")
 		} else {
 			// For local skills, use directory name
 			skillName = filepath.Base(skillDir)
@@ -163,133 +284,431 @@ func (m *FileSystemManager) ListSkills() ([]Skill, error) {
 
 		skill, err := m.readSkillFromPath(skillPath, skillName, isReadOnly)
 		if err != nil {
-			// Skip skills that can't be read
+			// Skip skills that can't be read, keeping the reason for SkillIssues
+			issues = append(issues, m.skillIssue(skillPath, skillName, err))
 			continue
 		}
 		skills = append(skills, *skill)
 	}
 
+	m.recordIssues(issues)
+	flagAmbiguousSkills(skills)
+	m.indexStableIDs(skills)
 	return skills, nil
 }
 
+// flagAmbiguousSkills marks skills whose directory name is shared with another skill, such as a local
+// skill and a git repo skill both named docker, or two git repo skills with the same name
+// Such skills should be referred to by their full name (repoName/skillName), never by directory name alone
+// Skills whose ID is also the ID of another skill are marked too, as their ID may resolve to the other skill
+func flagAmbiguousSkills(skills []Skill) {
+	counts := make(map[string]int, len(skills))
+	ids := make(map[string]int, len(skills))
+	for _, skill := range skills {
+		counts[filepath.Base(skill.SourcePath)]++
+		ids[skill.ID]++
+	}
+	for i := range skills {
+		skills[i].Ambiguous = counts[filepath.Base(skills[i].SourcePath)] > 1 || ids[skills[i].ID] > 1
+	}
+}
+
+// indexStableIDs rebuilds the lookup from stable IDs to skill locations
+// If several skills share an ID, the first one found wins
+func (m *FileSystemManager) indexStableIDs(skills []Skill) {
+	stableIDs := make(map[string]string)
+	for _, skill := range skills {
+		if skill.ID == skill.Name {
+			continue
+		}
+		if _, exists := stableIDs[skill.ID]; !exists {
+			stableIDs[skill.ID] = skill.Name
+		}
+	}
+
+	m.mu.Lock()
+	m.stableIDs = stableIDs
+	m.mu.Unlock()
+}
+
+// trackStableID records the stable ID of a skill written since the last scan, replacing the one it had
+func (m *FileSystemManager) trackStableID(skill Skill) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forgetStableIDLocked(skill.Name)
+	if skill.ID == skill.Name {
+		return
+	}
+	if m.stableIDs == nil {
+		m.stableIDs = make(map[string]string)
+	}
+	if _, exists := m.stableIDs[skill.ID]; !exists {
+		m.stableIDs[skill.ID] = skill.Name
+	}
+}
+
+// forgetStableID drops the stable ID of a deleted skill
+func (m *FileSystemManager) forgetStableID(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forgetStableIDLocked(name)
+}
+
+// forgetStableIDLocked drops the stable ID of the skill at name; m.mu must be held
+func (m *FileSystemManager) forgetStableIDLocked(name string) {
+	for id, location := range m.stableIDs {
+		if location == name {
+			delete(m.stableIDs, id)
+		}
+	}
+}
+
+// lookupStableID returns the location of the skill with the given stable ID, if known
+func (m *FileSystemManager) lookupStableID(id string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	name, ok := m.stableIDs[id]
+	return name, ok
+}
+
+// stableIDRescanInterval is how often at most a miss rescans the skills for stable IDs
+const stableIDRescanInterval = 2 * time.Second
+
+// rescanStableID rescans the skills and looks up a stable ID again
+// Used when an ID is neither a known stable ID nor a location, as the skill may have been written to disk
+// behind the manager's back since the last scan. Writes through the manager, rebuilds and the watcher keep
+// the stable IDs up to date otherwise, so only strings shaped like an ID rescan, at most once per interval
+func (m *FileSystemManager) rescanStableID(id string) (string, bool) {
+	if !couldBeStableID(id) {
+		return "", false
+	}
+	m.mu.Lock()
+	recent := time.Since(m.stableIDRescan) < stableIDRescanInterval
+	if !recent {
+		m.stableIDRescan = time.Now()
+	}
+	m.mu.Unlock()
+	if recent {
+		return "", false
+	}
+	if _, err := m.ListSkills(); err != nil {
+		return "", false
+	}
+	return m.lookupStableID(id)
+}
+
+// couldBeStableID reports whether id has the shape of a stable ID: a frontmatter id, or the ID of a skill
+// named differently from its directory (repoName/.../skillName)
+func couldBeStableID(id string) bool {
+	for _, part := range strings.Split(id, "/") {
+		if part == "" || SkipSkillDiscovery(part) || !skillIDPattern.MatchString(part) {
+			return false
+		}
+	}
+	return true
+}
+
 // readSkillFromPath reads a skill from a directory path
 func (m *FileSystemManager) readSkillFromPath(skillPath, skillName string, isReadOnly bool) (*Skill, error) {
-	skillMdPath := filepath.Join(skillPath, "SKILL.md")
+	skillMdPath := m.skillMdPath(skillPath)
+	overlaid := false
+	if overlayDir := m.overlaySkillDir(skillPath); overlayDir != "" {
+		_, err := os.Stat(overlayDir)
+		overlaid = err == nil
+	}
 	content, err := os.ReadFile(skillMdPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read SKILL.md: %w", err)
 	}
+	info, err := os.Stat(skillMdPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat SKILL.md: %w", err)
+	}
+	dirInfo, err := os.Stat(skillPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat skill directory: %w", err)
+	}
 
-	metadata, contentStr, err := ParseFrontmatter(string(content))
+	metadata, contentStr, err := ParseFrontmatterWithLimit(string(content), m.MaxContentLength())
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
-	// Validate that name in frontmatter matches directory name
-	dirName := filepath.Base(skillPath)
-	if metadata.Name != dirName {
-		return nil, fmt.Errorf("skill name in frontmatter (%s) does not match directory name (%s)", metadata.Name, dirName)
+	// The manifest of a git repository decides which of its skills are served, and can override their frontmatter
+	if isReadOnly {
+		entry, err := m.manifestSkill(skillPath)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil {
+			entry.apply(metadata)
+		}
+	}
+
+	// The stable ID from the frontmatter, if any, takes precedence over the location
+	id := skillName
+	if metadata.ID != "" {
+		id = metadata.ID
+	}
+
+	// The spec requires the name in the frontmatter to match the directory name. Unless names are strict,
+	// a skill whose directory is named differently (e.g. my-skill-v2 for my-skill) is still served at its
+	// location, with the frontmatter name in place of the directory name as its ID (repo/my-skill for
+	// repo/my-skill-v2), so the ID of a git repo skill never names a local skill
+	if dirName := filepath.Base(skillPath); metadata.Name != dirName {
+		if m.StrictNames() {
+			return nil, fmt.Errorf("skill name in frontmatter (%s) does not match directory name (%s)", metadata.Name, dirName)
+		}
+		if metadata.ID == "" {
+			id = path.Join(path.Dir(skillName), metadata.Name)
+		}
 	}
 
 	return &Skill{
 		Name:       skillName,
-		ID:         skillName, // ID is the same as Name - the identifier to use when reading
+		ID:         id,
 		Content:    contentStr,
 		Metadata:   metadata,
 		SourcePath: skillPath,
 		ReadOnly:   isReadOnly,
+		Overlaid:   overlaid,
+		Created:    changeTime(dirInfo),
+		Modified:   info.ModTime(),
+
+		ContentLength: len(contentStr),
+		WordCount:     len(strings.Fields(contentStr)),
+		TokenEstimate: EstimateTokens(contentStr),
 	}, nil
 }
 
 // findSkillDirByName recursively finds a skill directory by name within a base path
 func (m *FileSystemManager) findSkillDirByName(basePath, targetName string) (string, error) {
-	var foundPath string
-	err := filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip errors, continue walking
-		}
-		if !info.IsDir() {
-			return nil
-		}
-		// Check if this directory contains SKILL.md and matches the target name
-		skillMdPath := filepath.Join(path, "SKILL.md")
-		if _, err := os.Stat(skillMdPath); err == nil {
-			dirName := filepath.Base(path)
-			if dirName == targetName {
-				foundPath = path
-				return filepath.SkipAll // Found it, stop walking
-			}
-		}
-		return nil
-	})
-	if err != nil {
-		return "", err
-	}
-	if foundPath == "" {
-		return "", fmt.Errorf("skill directory not found: %s", targetName)
-	}
-	return foundPath, nil
+	return findSkillDirByName(basePath, targetName)
 }
 
-// ReadSkill reads a skill by name (supports both local skills and git repo skills with repoName/skillName format)
-func (m *FileSystemManager) ReadSkill(name string) (*Skill, error) {
-	// Check if this is a git repo skill (format: repoName/skillName)
-	if strings.Contains(name, "/") {
-		parts := strings.Split(name, "/")
-		if len(parts) == 2 {
-			repoName := parts[0]
-			skillDirName := parts[1]
-			repoPath := filepath.Join(m.skillsDir, repoName)
-
-			// Check if repo directory exists
-			if _, err := os.Stat(repoPath); err != nil {
-				return nil, fmt.Errorf("skill not found: %s", name)
-			}
-
-			// Recursively search for the skill directory within the repo
-			skillPath, err := m.findSkillDirByName(repoPath, skillDirName)
-			if err != nil {
-				return nil, fmt.Errorf("skill not found: %s", name)
-			}
-
-			return m.readSkillFromPath(skillPath, name, true)
-		}
+// ReadSkill reads a skill by name or by stable ID (supports both local skills and git repo skills with repoName/skillName format)
+// Names take precedence, so a stable ID never shadows the local or git repo skill with that name
+func (m *FileSystemManager) ReadSkill(id string) (*Skill, error) {
+	skill, err := m.readSkillByName(id)
+	if err == nil {
+		return skill, nil
+	}
+	if name, ok := m.lookupStableID(id); ok {
+		return m.readSkillByName(name)
 	}
 
-	// Local skill - look for directory with this name
-	skillPath := filepath.Join(m.skillsDir, name)
-	skillMdPath := filepath.Join(skillPath, "SKILL.md")
+	// Not a known location: it may be the stable ID of a skill added since the last scan
+	if name, ok := m.rescanStableID(id); ok {
+		return m.readSkillByName(name)
+	}
+	return nil, err
+}
 
-	if _, err := os.Stat(skillMdPath); err != nil {
+// readSkillByName reads a skill by its name (location)
+// The skill is named after its actual location, which differs from name for a short repoName/skillName
+func (m *FileSystemManager) readSkillByName(name string) (*Skill, error) {
+	skillPath, err := m.getSkillPathByName(name)
+	if err != nil {
+		return nil, err
+	}
+	relPath, err := filepath.Rel(m.skillsDir, skillPath)
+	if err != nil {
 		return nil, fmt.Errorf("skill not found: %s", name)
 	}
+	return m.readSkillFromPath(skillPath, filepath.ToSlash(relPath), strings.Contains(name, "/"))
+}
 
-	return m.readSkillFromPath(skillPath, name, false)
+// ExportSkill creates a tar.gz archive of a skill, resolving stable IDs like ReadSkill
+func (m *FileSystemManager) ExportSkill(skillID string) ([]byte, error) {
+	skill, err := m.ReadSkill(skillID)
+	if err != nil {
+		return nil, err
+	}
+	return ExportSkill(skill.Name, m.skillsDir)
 }
 
 // SearchSkills searches for skills matching the query
+// If the search index is unavailable, it falls back to a case-insensitive scan of all skills
 func (m *FileSystemManager) SearchSkills(query string) ([]Skill, error) {
-	results, err := m.searcher.Search(query)
+	return m.SearchSkillsWithOptions(query, SearchOptions{})
+}
+
+// SearchSkillsWithOptions searches for skills matching the query, narrowed by opts
+func (m *FileSystemManager) SearchSkillsWithOptions(query string, opts SearchOptions) ([]Skill, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	results, err := m.searcher.SearchWithOptions(query, opts)
+	if errors.Is(err, ErrSearchUnavailable) {
+		return m.scanSkills(query, opts)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Read full skill content for each result
+	// Read full skill content for each result from the directory it was indexed from,
+	// so skills sharing a name or id are never confused with one another
 	var skills []Skill
 	for _, result := range results {
-		skill, err := m.ReadSkill(result.Name)
+		var skill *Skill
+		var err error
+		if result.SourcePath != "" {
+			skill, err = m.readSkillFromPath(result.SourcePath, result.Name, m.isGitRepoPath(result.SourcePath))
+		} else {
+			skill, err = m.ReadSkill(result.Name)
+		}
 		if err != nil {
 			// Skip skills that can't be read
 			continue
 		}
-		skills = append(skills, *skill)
+		found := *skill
+		found.Score = result.Score
+		found.Snippet = result.Snippet
+		skills = append(skills, found)
 	}
 
 	return skills, nil
 }
 
+// scanSkills returns the skills matching opts whose searched fields contain every term of the query
+func (m *FileSystemManager) scanSkills(query string, opts SearchOptions) ([]Skill, error) {
+	skills, err := m.ListSkills()
+	if err != nil {
+		return nil, err
+	}
+	skills = FilterModified(skills, opts.Modified)
+	skills = FilterTags(skills, opts.Tags)
+
+	terms := strings.Fields(strings.ToLower(query))
+	var matches []Skill
+	for _, skill := range skills {
+		var texts []string
+		for _, field := range opts.searchFields() {
+			texts = append(texts, skillFieldText(skill, field))
+		}
+		text := strings.ToLower(strings.Join(texts, "\n"))
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, skill)
+		}
+	}
+	return matches, nil
+}
+
+// SetSearchFuzziness sets the edit distance within which search terms match, from 0 (exact) to MaxSearchFuzziness
+// The fallback scan used while the index is unavailable always matches substrings
+func (m *FileSystemManager) SetSearchFuzziness(fuzziness int) error {
+	return m.searcher.SetFuzziness(fuzziness)
+}
+
+// SearchFuzziness returns the edit distance within which search terms match
+func (m *FileSystemManager) SearchFuzziness() int {
+	return m.searcher.Fuzziness()
+}
+
+// SetResourceDirs sets the skill subdirectories holding resources, DefaultResourceDirs by default
+func (m *FileSystemManager) SetResourceDirs(dirs ResourceDirs) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resourceDirs = dirs
+}
+
+// ResourceDirs returns the skill subdirectories holding resources
+func (m *FileSystemManager) ResourceDirs() ResourceDirs {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.resourceDirs
+}
+
+// SetMaxContentLength sets the longest SKILL.md body read, in bytes, DefaultMaxContentLength by default
+// Skills with a longer body are skipped with a warning (0 = no limit)
+func (m *FileSystemManager) SetMaxContentLength(length int) error {
+	m.mu.Lock()
+	changed := m.maxContentLength != length
+	m.maxContentLength = length
+	m.mu.Unlock()
+
+	// Index the skills the new limit lets in or leaves out
+	if !changed {
+		return nil
+	}
+	return m.RebuildIndex()
+}
+
+// MaxContentLength returns the longest SKILL.md body read, in bytes
+func (m *FileSystemManager) MaxContentLength() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.maxContentLength
+}
+
+// SetStrictNames sets whether skills whose frontmatter name differs from their directory name are skipped,
+// as the Agent Skills specification requires, instead of being served with the frontmatter name as their ID
+func (m *FileSystemManager) SetStrictNames(strict bool) error {
+	m.mu.Lock()
+	changed := m.strictNames != strict
+	m.strictNames = strict
+	m.mu.Unlock()
+
+	// Index the skills the new mode lets in or leaves out
+	if !changed {
+		return nil
+	}
+	return m.RebuildIndex()
+}
+
+// StrictNames reports whether skills whose frontmatter name differs from their directory name are skipped
+func (m *FileSystemManager) StrictNames() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.strictNames
+}
+
+// SetLogger sets the logger for warnings about skipped skills (nil = discarded)
+func (m *FileSystemManager) SetLogger(logger *slog.Logger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logger = logger
+	// Report the skills skipped so far to the new logger on the next scan
+	m.issues = nil
+}
+
+// logf logs a message at level to the configured logger, if any
+func (m *FileSystemManager) logf(level slog.Level, format string, args ...any) {
+	m.mu.RLock()
+	logger := m.logger
+	m.mu.RUnlock()
+	if logger == nil || !logger.Enabled(context.Background(), level) {
+		return
+	}
+	logger.Log(context.Background(), level, fmt.Sprintf(format, args...))
+}
+
+// RecoveredIndex returns why the search index found on disk could not be opened and was rebuilt from scratch
+// when the manager was created, or nil if it was opened normally
+func (m *FileSystemManager) RecoveredIndex() error {
+	return m.searcher.Recovered()
+}
+
+// SearchStatus reports whether search is served by the index or by the fallback scan
+func (m *FileSystemManager) SearchStatus() SearchStatusInfo {
+	return m.searcher.Status()
+}
+
 // RebuildIndex rebuilds the search index
 func (m *FileSystemManager) RebuildIndex() error {
+	// Listing and indexing happen under one lock: otherwise a rebuild that listed the skills before
+	// a change could finish after one that listed them after it, leaving the change out of the index
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+
 	skills, err := m.ListSkills()
 	if err != nil {
 		return err
@@ -298,55 +717,177 @@ func (m *FileSystemManager) RebuildIndex() error {
 	return m.searcher.IndexSkills(skills)
 }
 
+// Close cancels a pending requested rebuild, waits for a running one and closes the search index,
+// so it is reopened cleanly on the next start
+// Searches fall back to scanning the skills directory afterwards, and re-indexing returns ErrIndexClosed
+func (m *FileSystemManager) Close() error {
+	m.reindexMu.Lock()
+	if m.reindex.timer != nil {
+		m.reindex.timer.Stop()
+		m.reindex.timer = nil
+	}
+	m.reindexMu.Unlock()
+
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+	return m.searcher.Close()
+}
+
+// UpdateSkillIndex indexes a single created or updated skill without re-indexing the others
+// Falls back to a full rebuild if the index cannot be updated in place
+func (m *FileSystemManager) UpdateSkillIndex(skillID string) error {
+	// Like rebuilds, reading and indexing happen under the rebuild lock: otherwise a rebuild that listed the
+	// skills before the write could finish after it, indexing the old version again
+	m.indexMu.Lock()
+	skill, err := m.ReadSkill(skillID)
+	if err != nil {
+		m.indexMu.Unlock()
+		return err
+	}
+	m.trackStableID(*skill)
+	err = m.searcher.IndexSkill(*skill)
+	m.indexMu.Unlock()
+
+	if err != nil {
+		return m.RebuildIndex()
+	}
+	return nil
+}
+
+// RemoveSkillIndex removes a deleted skill from the index without re-indexing the others
+// Falls back to a full rebuild if the index cannot be updated in place
+func (m *FileSystemManager) RemoveSkillIndex(skill Skill) error {
+	m.indexMu.Lock()
+	m.forgetStableID(skill.Name)
+	err := m.searcher.RemoveFromIndex(documentID(skill))
+	m.indexMu.Unlock()
+
+	if err != nil {
+		return m.RebuildIndex()
+	}
+	return nil
+}
+
 // GetSkillsDir returns the skills directory path
 func (m *FileSystemManager) GetSkillsDir() string {
 	return m.skillsDir
 }
 
-// UpdateGitRepos updates the list of git repository names for read-only detection
+// GetIndexDir returns the directory the search index is kept in
+func (m *FileSystemManager) GetIndexDir() string {
+	return m.searcher.indexPath
+}
+
+// UpdateGitRepos updates the list of git repository directory names for read-only detection
 func (m *FileSystemManager) UpdateGitRepos(gitRepoNames []string) {
 	m.gitRepos = gitRepoNames
 }
 
 // getSkillPath returns the full path to a skill directory given its ID
+// skillID may be a name or a stable ID, resolved like ReadSkill
 func (m *FileSystemManager) getSkillPath(skillID string) (string, error) {
-	// Check if this is a git repo skill (format: repoName/skillName)
-	if strings.Contains(skillID, "/") {
-		parts := strings.Split(skillID, "/")
-		if len(parts) == 2 {
-			repoName := parts[0]
-			skillDirName := parts[1]
-			repoPath := filepath.Join(m.skillsDir, repoName)
+	skillPath, err := m.getSkillPathByName(skillID)
+	if err == nil {
+		return skillPath, nil
+	}
+	if name, ok := m.lookupStableID(skillID); ok {
+		return m.getSkillPathByName(name)
+	}
+	if name, ok := m.rescanStableID(skillID); ok {
+		return m.getSkillPathByName(name)
+	}
+	return "", err
+}
 
-			// Recursively search for the skill directory within the repo
-			skillPath, err := m.findSkillDirByName(repoPath, skillDirName)
-			if err != nil {
-				return "", fmt.Errorf("skill not found: %s", skillID)
-			}
-			return skillPath, nil
+// getSkillPathByName returns the full path to a skill directory given its name (location)
+func (m *FileSystemManager) getSkillPathByName(skillID string) (string, error) {
+	return skillPathByName(m.skillsDir, skillID)
+}
+
+// skillPathByName resolves a skill name to its directory within skillsDir
+// Git repo skills are named after their path (repoName/category/skillName); the short repoName/skillName
+// form of a nested skill resolves to the first skill directory named skillName found within the repo
+func skillPathByName(skillsDir, name string) (string, error) {
+	parts := strings.Split(name, "/")
+	for _, part := range parts {
+		if part == "" || part == ".." || SkipSkillDiscovery(part) {
+			return "", fmt.Errorf("skill not found: %s", name)
 		}
 	}
 
-	// Local skill
-	skillPath := filepath.Join(m.skillsDir, skillID)
-	skillMdPath := filepath.Join(skillPath, "SKILL.md")
-	if _, err := os.Stat(skillMdPath); err != nil {
-		return "", fmt.Errorf("skill not found: %s", skillID)
+	skillPath := filepath.Join(append([]string{skillsDir}, parts...)...)
+	if _, err := os.Stat(filepath.Join(skillPath, "SKILL.md")); err == nil {
+		return skillPath, nil
 	}
-	return skillPath, nil
+
+	// Short name of a skill nested within a git repo (format: repoName/skillName)
+	if len(parts) == 2 {
+		skillPath, err := findSkillDirByName(filepath.Join(skillsDir, parts[0]), parts[1])
+		if err == nil {
+			return skillPath, nil
+		}
+	}
+	return "", fmt.Errorf("skill not found: %s", name)
 }
 
-// ListSkillResources lists all resources in a skill's optional directories
+// ListSkillResources lists all resources in a skill's resource directories (see SetResourceDirs)
 func (m *FileSystemManager) ListSkillResources(skillID string) ([]SkillResource, error) {
 	skillPath, err := m.getSkillPath(skillID)
 	if err != nil {
 		return nil, err
 	}
 
+	resources := m.listResources(skillPath)
+
+	// Resources in the local overlay shadow the git ones with the same path
+	if overlayDir := m.overlaySkillDir(skillPath); overlayDir != "" {
+		resources = shadowResources(resources, m.listResources(overlayDir))
+	}
+
+	applySkillMeta(skillPath, resources)
+	return resources, nil
+}
+
+// shadowResources replaces the resources with the overlaid ones of the same path, and appends the
+// overlaid resources that only exist in the overlay
+func shadowResources(resources, overlaid []SkillResource) []SkillResource {
+	shadows := make(map[string]SkillResource, len(overlaid))
+	for _, res := range overlaid {
+		shadows[res.Path] = res
+	}
+	for i, res := range resources {
+		if shadow, ok := shadows[res.Path]; ok {
+			resources[i] = shadow
+			delete(shadows, res.Path)
+		}
+	}
+	// Resources that only exist in the overlay come last
+	for _, res := range overlaid {
+		if _, ok := shadows[res.Path]; ok {
+			resources = append(resources, res)
+		}
+	}
+	return resources
+}
+
+// applySkillMeta applies the author-provided overrides from the sidecar file of a skill to its resources
+func applySkillMeta(skillPath string, resources []SkillResource) {
+	meta := loadSkillMetaOrEmpty(skillPath)
+	for i := range resources {
+		if resources[i].LFSPointer {
+			continue
+		}
+		resources[i].MimeType, resources[i].Readable = meta.override(resources[i].Path, resources[i].MimeType, resources[i].Readable)
+	}
+}
+
+// listResources lists the resources in the resource directories of a skill directory
+func (m *FileSystemManager) listResources(skillPath string) []SkillResource {
 	var resources []SkillResource
-	resourceDirs := []string{"scripts", "references", "assets"}
+	resourceDirs := m.ResourceDirs()
 
-	for _, dir := range resourceDirs {
+	for _, resourceDir := range resourceDirs {
+		dir := resourceDir.Name
 		dirPath := filepath.Join(skillPath, dir)
 		entries, err := os.ReadDir(dirPath)
 		if err != nil {
@@ -357,7 +898,7 @@ func (m *FileSystemManager) ListSkillResources(skillID string) ([]SkillResource,
 		for _, entry := range entries {
 			if entry.IsDir() {
 				// Recursively list subdirectories
-				subResources, err := m.listResourcesInDir(skillPath, filepath.Join(dir, entry.Name()))
+				subResources, err := m.listResourcesInDir(skillPath, filepath.Join(dir, entry.Name()), resourceDir.Type)
 				if err == nil {
 					resources = append(resources, subResources...)
 				}
@@ -365,7 +906,11 @@ func (m *FileSystemManager) ListSkillResources(skillID string) ([]SkillResource,
 			}
 
 			resourcePath := filepath.Join(dir, entry.Name())
-			fullPath := filepath.Join(skillPath, resourcePath)
+			fullPath, err := ResolveResourcePath(skillPath, resourcePath)
+			if err != nil {
+				// Skip symlinks leading outside the skill
+				continue
+			}
 
 			info, err := entry.Info()
 			if err != nil {
@@ -380,24 +925,29 @@ func (m *FileSystemManager) ListSkillResources(skillID string) ([]SkillResource,
 
 			mimeType := DetectMimeType(entry.Name(), content)
 			readable := IsTextFile(mimeType)
+			lfsPointer := IsLFSPointer(info.Size(), content)
+			if lfsPointer {
+				readable = false
+			}
 
 			resources = append(resources, SkillResource{
-				Type:     GetResourceType(resourcePath),
-				Path:     filepath.ToSlash(resourcePath), // Use forward slashes for consistency
-				Name:     entry.Name(),
-				Size:     info.Size(),
-				MimeType: mimeType,
-				Readable: readable,
-				Modified: info.ModTime(),
+				Type:       resourceDir.Type,
+				Path:       filepath.ToSlash(resourcePath), // Use forward slashes for consistency
+				Name:       entry.Name(),
+				Size:       info.Size(),
+				MimeType:   mimeType,
+				Readable:   readable,
+				Modified:   info.ModTime(),
+				LFSPointer: lfsPointer,
 			})
 		}
 	}
 
-	return resources, nil
+	return resources
 }
 
 // listResourcesInDir recursively lists resources in a subdirectory
-func (m *FileSystemManager) listResourcesInDir(skillPath, relPath string) ([]SkillResource, error) {
+func (m *FileSystemManager) listResourcesInDir(skillPath, relPath string, resourceType ResourceType) ([]SkillResource, error) {
 	var resources []SkillResource
 	fullPath := filepath.Join(skillPath, relPath)
 
@@ -409,7 +959,7 @@ func (m *FileSystemManager) listResourcesInDir(skillPath, relPath string) ([]Ski
 	for _, entry := range entries {
 		if entry.IsDir() {
 			// Recursively list subdirectories
-			subResources, err := m.listResourcesInDir(skillPath, filepath.Join(relPath, entry.Name()))
+			subResources, err := m.listResourcesInDir(skillPath, filepath.Join(relPath, entry.Name()), resourceType)
 			if err == nil {
 				resources = append(resources, subResources...)
 			}
@@ -417,7 +967,11 @@ func (m *FileSystemManager) listResourcesInDir(skillPath, relPath string) ([]Ski
 		}
 
 		resourcePath := filepath.Join(relPath, entry.Name())
-		fullResourcePath := filepath.Join(skillPath, resourcePath)
+		fullResourcePath, err := ResolveResourcePath(skillPath, resourcePath)
+		if err != nil {
+			// Skip symlinks leading outside the skill
+			continue
+		}
 
 		info, err := entry.Info()
 		if err != nil {
@@ -432,15 +986,20 @@ func (m *FileSystemManager) listResourcesInDir(skillPath, relPath string) ([]Ski
 
 		mimeType := DetectMimeType(entry.Name(), content)
 		readable := IsTextFile(mimeType)
+		lfsPointer := IsLFSPointer(info.Size(), content)
+		if lfsPointer {
+			readable = false
+		}
 
 		resources = append(resources, SkillResource{
-			Type:     GetResourceType(resourcePath),
-			Path:     filepath.ToSlash(resourcePath),
-			Name:     entry.Name(),
-			Size:     info.Size(),
-			MimeType: mimeType,
-			Readable: readable,
-			Modified: info.ModTime(),
+			Type:       resourceType,
+			Path:       filepath.ToSlash(resourcePath),
+			Name:       entry.Name(),
+			Size:       info.Size(),
+			MimeType:   mimeType,
+			Readable:   readable,
+			Modified:   info.ModTime(),
+			LFSPointer: lfsPointer,
 		})
 	}
 
@@ -450,7 +1009,7 @@ func (m *FileSystemManager) listResourcesInDir(skillPath, relPath string) ([]Ski
 // ReadSkillResource reads the content of a skill resource file
 func (m *FileSystemManager) ReadSkillResource(skillID, resourcePath string) (*ResourceContent, error) {
 	// Validate path
-	if err := ValidateResourcePath(resourcePath); err != nil {
+	if err := m.ResourceDirs().ValidatePath(resourcePath); err != nil {
 		return nil, err
 	}
 
@@ -459,14 +1018,27 @@ func (m *FileSystemManager) ReadSkillResource(skillID, resourcePath string) (*Re
 		return nil, err
 	}
 
-	fullPath := filepath.Join(skillPath, resourcePath)
+	fullPath, err := m.resourceFile(skillPath, resourcePath)
+	if err != nil {
+		return nil, err
+	}
 	content, err := os.ReadFile(fullPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read resource: %w", err)
 	}
+	return encodeResourceContent(skillPath, resourcePath, content)
+}
+
+// encodeResourceContent returns the content of a file of a skill, as UTF-8 for text and base64 otherwise
+func encodeResourceContent(skillPath, resourcePath string, content []byte) (*ResourceContent, error) {
+	// Refuse to serve Git LFS pointer files as if they were the real content
+	if IsLFSPointer(int64(len(content)), content) {
+		return nil, ErrLFSPointer
+	}
 
 	mimeType := DetectMimeType(filepath.Base(resourcePath), content)
 	readable := IsTextFile(mimeType)
+	mimeType, readable = loadSkillMetaOrEmpty(skillPath).override(resourcePath, mimeType, readable)
 
 	var encoding string
 	var contentStr string
@@ -489,10 +1061,42 @@ func (m *FileSystemManager) ReadSkillResource(skillID, resourcePath string) (*Re
 	}, nil
 }
 
+// OpenSkillResource opens a skill resource file for streaming
+func (m *FileSystemManager) OpenSkillResource(skillID, resourcePath string) (io.ReadSeekCloser, error) {
+	// Validate path
+	if err := m.ResourceDirs().ValidatePath(resourcePath); err != nil {
+		return nil, err
+	}
+
+	skillPath, err := m.getSkillPath(skillID)
+	if err != nil {
+		return nil, err
+	}
+
+	fullPath, err := m.resourceFile(skillPath, resourcePath)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open resource: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open resource: %w", err)
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, fmt.Errorf("resource path points to a directory, not a file")
+	}
+	return file, nil
+}
+
 // GetSkillResourceInfo gets metadata about a specific resource without reading content
 func (m *FileSystemManager) GetSkillResourceInfo(skillID, resourcePath string) (*SkillResource, error) {
 	// Validate path
-	if err := ValidateResourcePath(resourcePath); err != nil {
+	if err := m.ResourceDirs().ValidatePath(resourcePath); err != nil {
 		return nil, err
 	}
 
@@ -501,7 +1105,10 @@ func (m *FileSystemManager) GetSkillResourceInfo(skillID, resourcePath string) (
 		return nil, err
 	}
 
-	fullPath := filepath.Join(skillPath, resourcePath)
+	fullPath, err := m.resourceFile(skillPath, resourcePath)
+	if err != nil {
+		return nil, err
+	}
 	info, err := os.Stat(fullPath)
 	if err != nil {
 		return nil, fmt.Errorf("resource not found: %w", err)
@@ -522,14 +1129,21 @@ func (m *FileSystemManager) GetSkillResourceInfo(skillID, resourcePath string) (
 	n, _ := file.Read(buffer)
 	mimeType := DetectMimeType(filepath.Base(resourcePath), buffer[:n])
 	readable := IsTextFile(mimeType)
+	lfsPointer := IsLFSPointer(info.Size(), buffer[:n])
+	if lfsPointer {
+		readable = false
+	} else {
+		mimeType, readable = loadSkillMetaOrEmpty(skillPath).override(resourcePath, mimeType, readable)
+	}
 
 	return &SkillResource{
-		Type:     GetResourceType(resourcePath),
-		Path:     filepath.ToSlash(resourcePath),
-		Name:     filepath.Base(resourcePath),
-		Size:     info.Size(),
-		MimeType: mimeType,
-		Readable: readable,
-		Modified: info.ModTime(),
+		Type:       m.ResourceDirs().TypeOf(resourcePath),
+		Path:       filepath.ToSlash(resourcePath),
+		Name:       filepath.Base(resourcePath),
+		Size:       info.Size(),
+		MimeType:   mimeType,
+		Readable:   readable,
+		Modified:   info.ModTime(),
+		LFSPointer: lfsPointer,
 	}, nil
 }
@@ -1,8 +1,13 @@
 package domain_test
 
 import (
+	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -87,6 +92,103 @@ Content.
 			Expect(skills).To(HaveLen(1))
 			Expect(skills[0].Name).To(Equal("valid-skill"))
 		})
+
+		It("should report skipped skills as issues and log them once", func() {
+			var logs strings.Builder
+			manager.SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+			Expect(manager.SetStrictNames(true)).To(Succeed())
+
+			for dir, name := range map[string]string{"good-skill": "good-skill", "broken-skill": "other-name"} {
+				skillDir := filepath.Join(tempDir, dir)
+				Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+				content := "---\nname: " + name + "\ndescription: A skill\n---\n# Skill\n"
+				Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0644)).To(Succeed())
+			}
+
+			skills, err := manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skills).To(HaveLen(1))
+			Expect(skills[0].Name).To(Equal("good-skill"))
+
+			issues, err := manager.SkillIssues()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issues).To(HaveLen(1))
+			Expect(issues[0].Path).To(Equal("broken-skill"))
+			Expect(issues[0].Name).To(Equal("broken-skill"))
+			Expect(issues[0].Error).To(ContainSubstring("does not match directory name"))
+			Expect(strings.Count(logs.String(), "skipping skill broken-skill")).To(Equal(1))
+
+			// Fixed skills are no longer reported
+			Expect(os.WriteFile(filepath.Join(tempDir, "broken-skill", "SKILL.md"), []byte("---\nname: broken-skill\ndescription: A skill\n---\n"), 0644)).To(Succeed())
+			issues, err = manager.SkillIssues()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issues).To(BeEmpty())
+		})
+
+		It("should serve skills named differently from their directory under the frontmatter name unless names are strict", func() {
+			skillDir := filepath.Join(tempDir, "my-skill-v2")
+			Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# Skill\n"), 0644)).To(Succeed())
+
+			skills, err := manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skills).To(HaveLen(1))
+			Expect(skills[0].Name).To(Equal("my-skill-v2"))
+			Expect(skills[0].ID).To(Equal("my-skill"))
+			Expect(skills[0].SourcePath).To(Equal(skillDir))
+
+			skill, err := manager.ReadSkill("my-skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Name).To(Equal("my-skill-v2"))
+			Expect(manager.RebuildIndex()).To(Succeed())
+			results, err := manager.SearchSkills("skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+
+			Expect(manager.SetStrictNames(true)).To(Succeed())
+			skills, err = manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skills).To(BeEmpty())
+			_, err = manager.ReadSkill("my-skill-v2")
+			Expect(err).To(HaveOccurred())
+			results, err = manager.SearchSkills("skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(BeEmpty())
+		})
+
+		It("should keep the ID of a git repo skill named differently from its directory within its repo", func() {
+			for dir, name := range map[string]string{"my-skill": "my-skill", "repo/my-skill-v2": "my-skill", "repo/nested/deep": "deep", "foo": "foo", "foo-v2": "foo"} {
+				Expect(os.MkdirAll(filepath.Join(tempDir, dir), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(tempDir, dir, "SKILL.md"), []byte("---\nname: "+name+"\ndescription: A skill\n---\n"+dir), 0644)).To(Succeed())
+			}
+			manager.UpdateGitRepos([]string{"repo"})
+
+			skills, err := manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			ids := map[string]string{}
+			ambiguous := map[string]bool{}
+			for _, skill := range skills {
+				ids[skill.Name] = skill.ID
+				ambiguous[skill.Name] = skill.Ambiguous
+			}
+			Expect(ids).To(HaveKeyWithValue("my-skill", "my-skill"))
+			Expect(ids).To(HaveKeyWithValue("repo/my-skill-v2", "repo/my-skill"))
+			Expect(ids).To(HaveKeyWithValue("repo/nested/deep", "repo/nested/deep"))
+
+			skill, err := manager.ReadSkill("my-skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.SourcePath).To(Equal(filepath.Join(tempDir, "my-skill")))
+			skill, err = manager.ReadSkill("repo/my-skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Name).To(Equal("repo/my-skill-v2"))
+			Expect(ambiguous["my-skill"]).To(BeFalse())
+			Expect(ambiguous["repo/my-skill-v2"]).To(BeFalse())
+
+			// A local skill whose ID is the name of another local skill cannot be read by its ID
+			Expect(ids).To(HaveKeyWithValue("foo-v2", "foo"))
+			Expect(ambiguous["foo-v2"]).To(BeTrue())
+			Expect(ambiguous["foo"]).To(BeTrue())
+		})
 	})
 
 	Context("Reading Skills", func() {
@@ -112,6 +214,21 @@ This is a guide about Docker.
 			Expect(skill.Content).To(ContainSubstring("Docker Guide"))
 		})
 
+		It("should report the size of a skill's content", func() {
+			skillDir := filepath.Join(tempDir, "sized")
+			err := os.MkdirAll(skillDir, 0755)
+			Expect(err).NotTo(HaveOccurred())
+			err = os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: sized\ndescription: Sized\n---\none two  three\nfour"), 0644)
+			Expect(err).NotTo(HaveOccurred())
+
+			skill, err := manager.ReadSkill("sized")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.ContentLength).To(Equal(len(skill.Content)))
+			Expect(skill.WordCount).To(Equal(4))
+			Expect(skill.TokenEstimate).To(Equal(domain.EstimateTokens(skill.Content)))
+			Expect(skill.TokenEstimate).To(BeNumerically(">", 0))
+		})
+
 		It("should return an error for non-existent skill", func() {
 			_, err := manager.ReadSkill("nonexistent")
 			Expect(err).To(HaveOccurred())
@@ -160,6 +277,233 @@ description: ` + description + `
 			Expect(err).NotTo(HaveOccurred())
 			Expect(results).To(BeEmpty())
 		})
+
+		It("should report the search index as ok after a rebuild", func() {
+			status := manager.SearchStatus()
+			Expect(status.Status).To(Equal(domain.SearchStatusOK))
+			Expect(status.Documents).To(Equal(3))
+			Expect(status.LastIndexed).NotTo(BeNil())
+			Expect(status.Error).To(BeEmpty())
+		})
+
+		It("should update the index for a single skill", func() {
+			skillDir := filepath.Join(tempDir, "helm")
+			Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: helm\ndescription: Helm guide\n---\n# Helm\n\nHelm is a package manager."), 0644)).To(Succeed())
+
+			Expect(manager.UpdateSkillIndex("helm")).To(Succeed())
+			Expect(manager.SearchStatus().Documents).To(Equal(4))
+			results, err := manager.SearchSkills("package")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Name).To(Equal("helm"))
+			results, err = manager.SearchSkills("platform")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+
+			skill, err := manager.ReadSkill("helm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.RemoveAll(skillDir)).To(Succeed())
+			Expect(manager.RemoveSkillIndex(*skill)).To(Succeed())
+			Expect(manager.SearchStatus().Documents).To(Equal(3))
+			results, err = manager.SearchSkills("package")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(BeEmpty())
+		})
+
+		It("should filter search results by modification time", func() {
+			old := time.Now().Add(-30 * 24 * time.Hour)
+			err := os.Chtimes(filepath.Join(tempDir, "docker", "SKILL.md"), old, old)
+			Expect(err).NotTo(HaveOccurred())
+			err = manager.RebuildIndex()
+			Expect(err).NotTo(HaveOccurred())
+
+			after, err := domain.ParseTimeFilter("7d", time.Now())
+			Expect(err).NotTo(HaveOccurred())
+			results, err := manager.SearchSkillsWithOptions("platform", domain.SearchOptions{Modified: domain.ModifiedRange{After: after}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Name).To(Equal("kubernetes"))
+
+			results, err = manager.SearchSkillsWithOptions("platform", domain.SearchOptions{Modified: domain.ModifiedRange{Before: after}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Name).To(Equal("docker"))
+		})
+
+		It("should not confuse skills sharing a name or id", func() {
+			// A local skill named deploy and another skill whose stable id is deploy
+			writeSkill(filepath.Join(tempDir, "deploy"), "name: deploy\ndescription: Deploy\n", "alpha rollout")
+			writeSkill(filepath.Join(tempDir, "other"), "name: other\nid: deploy\ndescription: Other\n", "beta rollout")
+			// Two git skills in different folders of the same repo end up with the same name
+			writeSkill(filepath.Join(tempDir, "repo", "a", "release"), "name: release\ndescription: A\n", "gamma rollout")
+			writeSkill(filepath.Join(tempDir, "repo", "b", "release"), "name: release\ndescription: B\n", "delta rollout")
+
+			manager.UpdateGitRepos([]string{"repo"})
+			err := manager.RebuildIndex()
+			Expect(err).NotTo(HaveOccurred())
+
+			results, err := manager.SearchSkills("alpha")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Name).To(Equal("deploy"))
+			Expect(results[0].Content).To(ContainSubstring("alpha"))
+
+			results, err = manager.SearchSkills("rollout")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(4))
+			var contents []string
+			for _, skill := range results {
+				contents = append(contents, skill.Content)
+			}
+			Expect(contents).To(ConsistOf("alpha rollout", "beta rollout", "gamma rollout", "delta rollout"))
+		})
+
+		It("should search metadata values and tags", func() {
+			skillDir := filepath.Join(tempDir, "helm")
+			err := os.MkdirAll(skillDir, 0755)
+			Expect(err).NotTo(HaveOccurred())
+			err = os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: helm\ndescription: Helm guide\nmetadata:\n  owner: sre-team\n  tags: charts, packaging\n---\n# Helm\n\nMentions docker."), 0644)
+			Expect(err).NotTo(HaveOccurred())
+			err = manager.RebuildIndex()
+			Expect(err).NotTo(HaveOccurred())
+
+			results, err := manager.SearchSkills("sre-team")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Name).To(Equal("helm"))
+
+			results, err = manager.SearchSkillsWithOptions("packaging", domain.SearchOptions{Fields: []string{"tags"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Name).To(Equal("helm"))
+
+			// Restricting the fields excludes content matches
+			results, err = manager.SearchSkillsWithOptions("docker", domain.SearchOptions{Fields: []string{"metadata"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(BeEmpty())
+
+			_, err = manager.SearchSkillsWithOptions("docker", domain.SearchOptions{Fields: []string{"bogus"}})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should parse absolute and relative time filters", func() {
+			now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+			t, err := domain.ParseTimeFilter("2025-01-02T03:04:05Z", now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(Equal(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)))
+
+			t, err = domain.ParseTimeFilter("7d", now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(Equal(now.Add(-7 * 24 * time.Hour)))
+
+			t, err = domain.ParseTimeFilter("2w", now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(Equal(now.Add(-14 * 24 * time.Hour)))
+
+			t, err = domain.ParseTimeFilter("12h", now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t).To(Equal(now.Add(-12 * time.Hour)))
+
+			_, err = domain.ParseTimeFilter("last week", now)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should keep the index in a separate directory when configured", func() {
+			skillsDir, err := os.MkdirTemp("", "skillserver-test")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(skillsDir)
+			Expect(os.MkdirAll(filepath.Join(skillsDir, "helm"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillsDir, "helm", "SKILL.md"), []byte("---\nname: helm\ndescription: Helm guide\n---\n# Helm"), 0644)).To(Succeed())
+			// Left behind by an earlier version
+			Expect(os.MkdirAll(filepath.Join(skillsDir, ".index"), 0755)).To(Succeed())
+
+			indexDir := filepath.Join(tempDir, "cache", "index")
+			separate, err := domain.NewFileSystemManagerWithIndexDir(skillsDir, indexDir, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(separate.GetIndexDir()).To(Equal(indexDir))
+			Expect(filepath.Join(indexDir, "index_meta.json")).To(BeAnExistingFile())
+
+			results, err := separate.SearchSkills("helm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+
+			entries, err := os.ReadDir(skillsDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Name()).To(Equal("helm"))
+		})
+
+		It("should not list skills from an index kept among the skills", func() {
+			skillsDir, err := os.MkdirTemp("", "skillserver-test")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(skillsDir)
+			Expect(os.MkdirAll(filepath.Join(skillsDir, "helm"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillsDir, "helm", "SKILL.md"), []byte("---\nname: helm\ndescription: Helm guide\n---\n# Helm"), 0644)).To(Succeed())
+
+			indexDir := filepath.Join(skillsDir, "search-index")
+			inside, err := domain.NewFileSystemManagerWithIndexDir(skillsDir, indexDir, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.MkdirAll(filepath.Join(indexDir, "nested"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(indexDir, "nested", "SKILL.md"), []byte("---\nname: nested\ndescription: Not a skill\n---\n"), 0644)).To(Succeed())
+
+			skills, err := inside.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skills).To(HaveLen(1))
+			Expect(skills[0].Name).To(Equal("helm"))
+		})
+
+		It("should start and rebuild the index when the one on disk is corrupted", func() {
+			corruptDir, err := os.MkdirTemp("", "skillserver-test")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(corruptDir)
+			Expect(os.MkdirAll(filepath.Join(corruptDir, "helm"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(corruptDir, "helm", "SKILL.md"), []byte("---\nname: helm\ndescription: Helm guide\n---\n# Helm"), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(corruptDir, ".index", "store"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(corruptDir, ".index", "index_meta.json"), []byte("garbage"), 0644)).To(Succeed())
+
+			recovered, err := domain.NewFileSystemManager(corruptDir, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recovered.RecoveredIndex()).To(HaveOccurred())
+			Expect(recovered.SearchStatus().Status).To(Equal(domain.SearchStatusOK))
+
+			results, err := recovered.SearchSkills("helm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+		})
+
+		It("should close the index so it can be cleanly reopened", func() {
+			closeDir, err := os.MkdirTemp("", "skillserver-test")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(closeDir)
+			skillsDir := filepath.Join(closeDir, "skills")
+			indexDir := filepath.Join(closeDir, "index")
+			Expect(os.MkdirAll(filepath.Join(skillsDir, "helm"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillsDir, "helm", "SKILL.md"), []byte("---\nname: helm\ndescription: Helm guide\n---\n# Helm"), 0644)).To(Succeed())
+
+			first, err := domain.NewFileSystemManagerWithIndexDir(skillsDir, indexDir, nil)
+			Expect(err).NotTo(HaveOccurred())
+			first.RequestReindex()
+			Expect(first.Close()).To(Succeed())
+			Expect(first.Close()).To(Succeed())
+			Expect(first.RebuildIndex()).To(MatchError(domain.ErrIndexClosed))
+
+			// Searches keep working by scanning the skills
+			results, err := first.SearchSkills("helm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+
+			reopened, err := domain.NewFileSystemManagerWithIndexDir(skillsDir, indexDir, nil)
+			Expect(err).NotTo(HaveOccurred())
+			defer reopened.Close()
+			Expect(reopened.RecoveredIndex()).NotTo(HaveOccurred())
+			Expect(reopened.SearchStatus().Status).To(Equal(domain.SearchStatusOK))
+
+			results, err = reopened.SearchSkills("helm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+		})
 	})
 
 	Context("YAML Frontmatter", func() {
@@ -183,6 +527,112 @@ Content here.`
 			Expect(skill.Metadata.Description).To(Equal("A guide to Docker"))
 		})
 
+		It("should parse icon and color presentation hints", func() {
+			metadata, _, err := domain.ParseFrontmatter("---\nname: docker\ndescription: Docker\nicon: fa-docker\ncolor: \"#1e90ff\"\n---\n# Docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.Icon).To(Equal("fa-docker"))
+			Expect(metadata.Color).To(Equal("#1e90ff"))
+
+			metadata, _, err = domain.ParseFrontmatter("---\nname: docker\ndescription: Docker\ncolor: RebeccaPurple\n---\n# Docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.Color).To(Equal("RebeccaPurple"))
+		})
+
+		It("should serve a skill with an invalid icon or color without the hint", func() {
+			for _, hints := range []string{"color: \"#12345\"", "color: notacolor", "icon: \"not an icon\""} {
+				metadata, _, err := domain.ParseFrontmatter("---\nname: docker\ndescription: Docker\n" + hints + "\n---\n# Docker")
+				Expect(err).NotTo(HaveOccurred(), hints)
+				Expect(metadata.Icon).To(BeEmpty(), hints)
+				Expect(metadata.Color).To(BeEmpty(), hints)
+			}
+
+			writeSkill(filepath.Join(tempDir, "docker"), "name: docker\ndescription: Docker\nicon: fa-docker\ncolor: notacolor\n", "# Docker")
+			skills, err := manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skills).To(HaveLen(1))
+			Expect(skills[0].Metadata.Icon).To(Equal("fa-docker"))
+			Expect(skills[0].Metadata.Color).To(BeEmpty())
+		})
+
+		It("should parse version and tags", func() {
+			metadata, _, err := domain.ParseFrontmatter("---\nname: docker\ndescription: Docker\nversion: \"1.10\"\ntags:\n  - containers\n  - devops\n---\n# Docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.Version).To(Equal("1.10"))
+			Expect(metadata.Tags).To(Equal(domain.StringList{"containers", "devops"}))
+
+			// A comma-separated string is accepted too
+			metadata, _, err = domain.ParseFrontmatter("---\nname: docker\ndescription: Docker\ntags: containers, devops\n---\n# Docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.Tags).To(Equal(domain.StringList{"containers", "devops"}))
+
+			_, _, err = domain.ParseFrontmatter("---\nname: docker\ndescription: Docker\ntags: [\"\"]\n---\n# Docker")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should parse allowed tools as a space-delimited list", func() {
+			metadata, _, err := domain.ParseFrontmatter("---\nname: docker\ndescription: Docker\nallowed-tools: Bash(git:*)  Read\n---\n# Docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.AllowedTools).To(Equal("Bash(git:*)  Read"))
+			Expect(metadata.AllowedToolsList()).To(Equal([]string{"Bash(git:*)", "Read"}))
+
+			tools, duplicates, err := domain.ParseAllowedTools("Read Write Read")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tools).To(Equal([]string{"Read", "Write"}))
+			Expect(duplicates).To(Equal([]string{"Read"}))
+
+			for _, allowedTools := range []string{"Read, Write", "Read,Write", "Read , Write"} {
+				_, _, err = domain.ParseFrontmatter("---\nname: docker\ndescription: Docker\nallowed-tools: " + allowedTools + "\n---\n# Docker")
+				Expect(err).To(MatchError(ContainSubstring("space-delimited")), "allowed-tools %q should be invalid", allowedTools)
+			}
+		})
+
+		It("should keep author-defined frontmatter keys", func() {
+			metadata, _, err := domain.ParseFrontmatter("---\nname: docker\ndescription: Docker\nauthor: Jane Doe\nlicense: MIT\n---\n# Docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.License).To(Equal("MIT"))
+			Expect(metadata.Extra).To(Equal(map[string]any{"author": "Jane Doe"}))
+		})
+
+		It("should format frontmatter that parses back", func() {
+			frontmatter, err := domain.FormatFrontmatter(&domain.SkillMetadata{
+				Name:        "docker",
+				Description: `Run "containers": fast`,
+				Version:     "1.10",
+				Color:       "#1e90ff",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(frontmatter).To(HavePrefix("---\nname: docker\n"))
+			Expect(frontmatter).To(HaveSuffix("---\n\n"))
+
+			metadata, _, err := domain.ParseFrontmatter(frontmatter + "# Docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metadata.Description).To(Equal(`Run "containers": fast`))
+			Expect(metadata.Version).To(Equal("1.10"))
+			Expect(metadata.Color).To(Equal("#1e90ff"))
+		})
+
+		It("should edit frontmatter in place", func() {
+			content := "---\n# Owned by the platform team\nname: docker\nauthor: Jane Doe\ndescription: Docker\nlicense: MIT\n---\n\n# Docker\n"
+			updated, err := domain.UpdateFrontmatter(content, "# Docker\n\n  indented\n", []domain.FrontmatterField{
+				{Key: "description", Value: "Docker guide"},
+				{Key: "license", Value: nil},
+				{Key: "version", Value: "1.10"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(Equal("---\n# Owned by the platform team\nname: docker\nauthor: Jane Doe\ndescription: Docker guide\nversion: \"1.10\"\n---\n\n# Docker\n\n  indented\n"))
+		})
+
+		It("should search frontmatter tags", func() {
+			skillDir := filepath.Join(tempDir, "docker")
+			Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: docker\ndescription: Docker\ntags: [containerization]\n---\n# Docker"), 0644)).To(Succeed())
+			Expect(manager.RebuildIndex()).To(Succeed())
+
+			results, err := manager.SearchSkillsWithOptions("containerization", domain.SearchOptions{Fields: []string{"tags"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+		})
+
 		It("should require frontmatter", func() {
 			skillDir := filepath.Join(tempDir, "docker")
 			err := os.MkdirAll(skillDir, 0755)
@@ -198,6 +648,27 @@ Content here.`
 	})
 
 	Context("Git Repository Filtering", func() {
+		It("should not look for skills in .git or dependency directories", func() {
+			repoDir := filepath.Join(tempDir, "repo")
+			writeSkill(filepath.Join(repoDir, "helm"), "name: helm\ndescription: A skill\n", "# helm")
+			// Decoys that would be found if these directories were traversed
+			writeSkill(filepath.Join(repoDir, ".git", "objects", "helm"), "name: decoy\ndescription: A skill\n", "# decoy")
+			writeSkill(filepath.Join(repoDir, "node_modules", "pkg"), "name: pkg\ndescription: A skill\n", "# pkg")
+			writeSkill(filepath.Join(tempDir, ".hidden"), "name: hidden\ndescription: A skill\n", "# hidden")
+
+			manager.UpdateGitRepos([]string{"repo"})
+
+			skills, err := manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skills).To(HaveLen(1))
+			Expect(skills[0].Name).To(Equal("repo/helm"))
+
+			// .git sorts before helm, so a walk into it would find the decoy first
+			skill, err := manager.ReadSkill("repo/helm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Content).To(Equal("# helm"))
+		})
+
 		It("should filter out skills from disabled git repos", func() {
 			// Create a git repo structure
 			repoDir := filepath.Join(tempDir, "enabled-repo")
@@ -303,4 +774,643 @@ description: Skill 2
 			Expect(skills[0].Name).To(Equal("repo2/skill2"))
 		})
 	})
+
+	Context("Name collisions", func() {
+		BeforeEach(func() {
+			writeSkill(filepath.Join(tempDir, "docker"), "name: docker\ndescription: Local\n", "local")
+			writeSkill(filepath.Join(tempDir, "repo1", "docker"), "name: docker\ndescription: Repo 1\nid: docker\n", "repo1")
+			writeSkill(filepath.Join(tempDir, "repo2", "nested", "docker"), "name: docker\ndescription: Nested\n", "nested")
+			writeSkill(filepath.Join(tempDir, "repo2", "docker"), "name: docker\ndescription: Repo 2\n", "repo2")
+			writeSkill(filepath.Join(tempDir, "helm"), "name: helm\ndescription: Helm\n", "helm")
+			manager.UpdateGitRepos([]string{"repo1", "repo2"})
+		})
+
+		It("should flag skills sharing a directory name", func() {
+			skills, err := manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			ambiguous := make(map[string]bool)
+			for _, skill := range skills {
+				if filepath.Base(skill.SourcePath) == "docker" {
+					Expect(skill.Ambiguous).To(BeTrue(), skill.SourcePath)
+				} else {
+					Expect(skill.Ambiguous).To(BeFalse(), skill.SourcePath)
+				}
+				ambiguous[skill.Name] = skill.Ambiguous
+			}
+			Expect(ambiguous).To(HaveKeyWithValue("docker", true))
+			Expect(ambiguous).To(HaveKeyWithValue("repo1/docker", true))
+			Expect(ambiguous).To(HaveKeyWithValue("helm", false))
+		})
+
+		It("should resolve colliding names deterministically", func() {
+			_, err := manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+
+			// The local skill wins over the git skill whose stable id is its name
+			skill, err := manager.ReadSkill("docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Content).To(Equal("local"))
+			Expect(skill.ReadOnly).To(BeFalse())
+
+			skill, err = manager.ReadSkill("repo1/docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Content).To(Equal("repo1"))
+			Expect(skill.ReadOnly).To(BeTrue())
+
+			// The skill at the repo root wins over a nested one, although "nested" sorts first
+			for i := 0; i < 3; i++ {
+				skill, err = manager.ReadSkill("repo2/docker")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(skill.Content).To(Equal("repo2"))
+			}
+		})
+	})
+
+	Context("Local overlay", func() {
+		var overlayDir string
+		gitFile := func(path string) string {
+			content, err := os.ReadFile(filepath.Join(tempDir, "repo", "docker", path))
+			Expect(err).NotTo(HaveOccurred())
+			return string(content)
+		}
+
+		BeforeEach(func() {
+			skillDir := filepath.Join(tempDir, "repo", "docker")
+			Expect(os.MkdirAll(filepath.Join(skillDir, "assets"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: docker\ndescription: From git\n---\n# Docker"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "assets", "config.txt"), []byte("git"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "assets", "other.txt"), []byte("other"), 0644)).To(Succeed())
+			manager.UpdateGitRepos([]string{"repo"})
+
+			var err error
+			overlayDir, err = os.MkdirTemp("", "skillserver-overlay")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(os.RemoveAll, overlayDir)
+		})
+
+		It("should refuse writes to git skills without an overlay", func() {
+			skill, err := manager.ReadSkill("repo/docker")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = manager.WritableSkillDir(skill)
+			Expect(err).To(MatchError(domain.ErrNoOverlay))
+		})
+
+		It("should prefer overlaid resources and leave the git checkout untouched", func() {
+			Expect(manager.SetOverlayDir(overlayDir)).To(Succeed())
+			skill, err := manager.ReadSkill("repo/docker")
+			Expect(err).NotTo(HaveOccurred())
+			dir, err := manager.WritableSkillDir(skill)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dir).To(Equal(filepath.Join(overlayDir, "repo", "docker")))
+
+			Expect(os.MkdirAll(filepath.Join(dir, "assets"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "assets", "config.txt"), []byte("local"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "assets", "extra.txt"), []byte("extra"), 0644)).To(Succeed())
+
+			content, err := manager.ReadSkillResource("repo/docker", "assets/config.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content.Content).To(Equal("local"))
+			Expect(gitFile("assets/config.txt")).To(Equal("git"))
+
+			resources, err := manager.ListSkillResources("repo/docker")
+			Expect(err).NotTo(HaveOccurred())
+			sizes := map[string]int64{}
+			for _, res := range resources {
+				sizes[res.Path] = res.Size
+			}
+			Expect(sizes).To(Equal(map[string]int64{"assets/config.txt": 5, "assets/other.txt": 5, "assets/extra.txt": 5}))
+		})
+
+		It("should shadow SKILL.md in reads and listings", func() {
+			Expect(manager.SetOverlayDir(overlayDir)).To(Succeed())
+			dir := filepath.Join(overlayDir, "repo", "docker")
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: docker\ndescription: Overlaid\n---\n# Docker"), 0644)).To(Succeed())
+
+			skill, err := manager.ReadSkill("repo/docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Metadata.Description).To(Equal("Overlaid"))
+			Expect(skill.ReadOnly).To(BeTrue())
+			Expect(skill.Overlaid).To(BeTrue())
+
+			skills, err := manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skills).To(HaveLen(1))
+			Expect(skills[0].Metadata.Description).To(Equal("Overlaid"))
+			Expect(gitFile("SKILL.md")).To(ContainSubstring("From git"))
+		})
+	})
+
+	Context("Copying skills", func() {
+		BeforeEach(func() {
+			localDir := filepath.Join(tempDir, "docker")
+			Expect(os.MkdirAll(filepath.Join(localDir, "scripts"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(localDir, "SKILL.md"), []byte("---\nid: docker-id\nname: docker\ndescription: Docker\n---\n# Docker"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(localDir, "scripts", "run.sh"), []byte("#!/bin/sh"), 0755)).To(Succeed())
+
+			gitDir := filepath.Join(tempDir, "repo", "helm")
+			Expect(os.MkdirAll(filepath.Join(gitDir, "assets"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(gitDir, "SKILL.md"), []byte("---\nname: helm\ndescription: Helm\n---\n# Helm"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(gitDir, "assets", "values.yaml"), []byte("replicas: 1"), 0644)).To(Succeed())
+			manager.UpdateGitRepos([]string{"repo"})
+		})
+
+		It("should copy a local skill with its resources under the new name", func() {
+			skill, err := manager.CopySkill("docker", "docker-copy")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Name).To(Equal("docker-copy"))
+			Expect(skill.ID).To(Equal("docker-copy"))
+			Expect(skill.Metadata.Name).To(Equal("docker-copy"))
+			skillMd, err := os.ReadFile(filepath.Join(tempDir, "docker-copy", "SKILL.md"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(skillMd)).To(ContainSubstring("name: docker-copy\n"))
+			Expect(string(skillMd)).NotTo(ContainSubstring("docker-id"))
+
+			info, err := os.Stat(filepath.Join(tempDir, "docker-copy", "scripts", "run.sh"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+
+			original, err := manager.ReadSkill("docker-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(original.Metadata.Name).To(Equal("docker"))
+		})
+
+		It("should copy a git skill into an editable local skill", func() {
+			skill, err := manager.CopySkill("repo/helm", "my-helm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.ReadOnly).To(BeFalse())
+			Expect(skill.SourcePath).To(Equal(filepath.Join(tempDir, "my-helm")))
+			Expect(skill.Metadata.Name).To(Equal("my-helm"))
+
+			content, err := manager.ReadSkillResource("my-helm", "assets/values.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content.Content).To(Equal("replicas: 1"))
+		})
+
+		It("should include overlaid files of git skills", func() {
+			overlayDir, err := os.MkdirTemp("", "skillserver-overlay")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(os.RemoveAll, overlayDir)
+			Expect(manager.SetOverlayDir(overlayDir)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(overlayDir, "repo", "helm", "assets"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(overlayDir, "repo", "helm", "assets", "values.yaml"), []byte("replicas: 3"), 0644)).To(Succeed())
+
+			_, err = manager.CopySkill("repo/helm", "my-helm")
+			Expect(err).NotTo(HaveOccurred())
+			content, err := manager.ReadSkillResource("my-helm", "assets/values.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content.Content).To(Equal("replicas: 3"))
+		})
+
+		It("should refuse to overwrite an existing skill", func() {
+			_, err := manager.CopySkill("repo/helm", "docker")
+			Expect(err).To(MatchError(domain.ErrSkillExists))
+		})
+
+		It("should reject invalid names", func() {
+			_, err := manager.CopySkill("docker", "Not Valid")
+			Expect(err).To(HaveOccurred())
+			Expect(filepath.Join(tempDir, "Not Valid")).NotTo(BeADirectory())
+		})
+	})
+
+	Context("Renaming skills", func() {
+		BeforeEach(func() {
+			for _, name := range []string{"docker", "helm"} {
+				dir := filepath.Join(tempDir, name)
+				Expect(os.MkdirAll(filepath.Join(dir, "references"), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nid: "+name+"-id\nname: "+name+"\ndescription: A skill\n---\n# Skill"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(dir, "references", "guide.md"), []byte("# Guide"), 0644)).To(Succeed())
+			}
+			gitDir := filepath.Join(tempDir, "repo", "linux")
+			Expect(os.MkdirAll(gitDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(gitDir, "SKILL.md"), []byte("---\nname: linux\ndescription: Linux\n---\n# Linux"), 0644)).To(Succeed())
+			manager.UpdateGitRepos([]string{"repo"})
+		})
+
+		It("should move the skill and its resources to the new name", func() {
+			skill, err := manager.RenameSkill("docker", "containers")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Name).To(Equal("containers"))
+			Expect(skill.ID).To(Equal("docker-id"))
+			Expect(skill.Metadata.Name).To(Equal("containers"))
+			Expect(filepath.Join(tempDir, "docker")).NotTo(BeADirectory())
+
+			content, err := manager.ReadSkillResource("containers", "references/guide.md")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content.Content).To(Equal("# Guide"))
+		})
+
+		It("should refuse to rename over an existing skill", func() {
+			_, err := manager.RenameSkill("docker", "helm")
+			Expect(err).To(MatchError(domain.ErrSkillExists))
+			Expect(filepath.Join(tempDir, "docker", "SKILL.md")).To(BeARegularFile())
+		})
+
+		It("should refuse to rename git skills", func() {
+			_, err := manager.RenameSkill("repo/linux", "my-linux")
+			Expect(err).To(MatchError(domain.ErrRenameReadOnly))
+			Expect(filepath.Join(tempDir, "repo", "linux")).To(BeADirectory())
+		})
+	})
+
+	Context("Content length", func() {
+		It("should accept content up to the limit", func() {
+			_, content, err := domain.ParseFrontmatterWithLimit("---\nname: docker\ndescription: Docker\n---\n"+strings.Repeat("x", 100), 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(HaveLen(100))
+
+			_, _, err = domain.ParseFrontmatterWithLimit("---\nname: docker\ndescription: Docker\n---\n"+strings.Repeat("x", 101), 100)
+			Expect(err).To(MatchError(domain.ErrContentTooLarge))
+		})
+
+		It("should skip skills over the limit with a warning", func() {
+			var logs strings.Builder
+			manager.SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+			writeSkill(filepath.Join(tempDir, "small"), "name: small\ndescription: A skill\n", strings.Repeat("x", 100))
+			writeSkill(filepath.Join(tempDir, "large"), "name: large\ndescription: A skill\n", strings.Repeat("x", 101))
+			Expect(manager.SetMaxContentLength(100)).To(Succeed())
+
+			skills, err := manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skills).To(HaveLen(1))
+			Expect(skills[0].Name).To(Equal("small"))
+			Expect(logs.String()).To(ContainSubstring("skipping skill large"))
+
+			results, err := manager.SearchSkills("large")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(BeEmpty())
+		})
+
+		It("should read skills a raised limit lets in", func() {
+			writeSkill(filepath.Join(tempDir, "large"), "name: large\ndescription: A skill\n", strings.Repeat("x", domain.DefaultMaxContentLength+1))
+			_, err := manager.ReadSkill("large")
+			Expect(err).To(MatchError(domain.ErrContentTooLarge))
+
+			Expect(manager.SetMaxContentLength(domain.DefaultMaxContentLength + 1)).To(Succeed())
+			skill, err := manager.ReadSkill("large")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Content).To(HaveLen(domain.DefaultMaxContentLength + 1))
+		})
+	})
+
+	Context("Timestamps", func() {
+		It("should report the modification time of a rewritten SKILL.md", func() {
+			skillDir := filepath.Join(tempDir, "docker")
+			skillMd := filepath.Join(skillDir, "SKILL.md")
+			Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(skillMd, []byte("---\nname: docker\ndescription: Docker guide\n---\n# Docker"), 0644)).To(Succeed())
+			past := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+			Expect(os.Chtimes(skillMd, past, past)).To(Succeed())
+
+			skill, err := manager.ReadSkill("docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Modified).To(BeTemporally("==", past))
+			Expect(skill.Created).NotTo(BeZero())
+
+			Expect(os.WriteFile(skillMd, []byte("---\nname: docker\ndescription: Docker guide\n---\n# Docker, updated"), 0644)).To(Succeed())
+			skill, err = manager.ReadSkill("docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Modified).To(BeTemporally(">", past))
+			Expect(skill.Modified).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+	})
+
+	Context("Concurrent index rebuilds", func() {
+		It("should index every skill when rebuilds run at the same time", func() {
+			const rebuilds = 20
+			var wg sync.WaitGroup
+			for i := 0; i < rebuilds; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer GinkgoRecover()
+					defer wg.Done()
+					name := fmt.Sprintf("skill-%d", i)
+					Expect(os.MkdirAll(filepath.Join(tempDir, name), 0755)).To(Succeed())
+					content := "---\nname: " + name + "\ndescription: A skill\n---\n# Kubernetes deployment guide"
+					Expect(os.WriteFile(filepath.Join(tempDir, name, "SKILL.md"), []byte(content), 0644)).To(Succeed())
+					Expect(manager.RebuildIndex()).To(Succeed())
+				}(i)
+			}
+			wg.Wait()
+
+			status := manager.SearchStatus()
+			Expect(status.Status).To(Equal(domain.SearchStatusOK))
+			Expect(status.Documents).To(Equal(rebuilds))
+
+			results, err := manager.SearchSkills("kubernetes")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(rebuilds))
+		})
+	})
+
+	Context("Concurrent index updates", func() {
+		It("should keep the latest version of a skill updated while the index is rebuilt", func() {
+			skillDir := filepath.Join(tempDir, "infra")
+			Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: infra\ndescription: Infrastructure\n---\n# Kubernetes"), 0644)).To(Succeed())
+			Expect(manager.RebuildIndex()).To(Succeed())
+
+			// A skill skipped for the first time is logged once the rebuild has listed the skills,
+			// which holds the rebuild there until the update has had its chance to run
+			logs := &pausingWriter{written: make(chan struct{}, 1), release: make(chan struct{})}
+			manager.SetLogger(slog.New(slog.NewTextHandler(logs, nil)))
+			Expect(os.MkdirAll(filepath.Join(tempDir, "broken"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(tempDir, "broken", "SKILL.md"), []byte("---\nname: broken\n---\n"), 0644)).To(Succeed())
+
+			rebuilt := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(rebuilt)
+				Expect(manager.RebuildIndex()).To(Succeed())
+			}()
+			<-logs.written
+
+			Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: infra\ndescription: Infrastructure\n---\n# Terraform"), 0644)).To(Succeed())
+			updated := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				defer close(updated)
+				Expect(manager.UpdateSkillIndex("infra")).To(Succeed())
+			}()
+			select {
+			case <-updated:
+			case <-time.After(100 * time.Millisecond):
+			}
+			close(logs.release)
+			<-rebuilt
+			<-updated
+
+			results, err := manager.SearchSkills("terraform")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			results, err = manager.SearchSkills("kubernetes")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(BeEmpty())
+		})
+	})
+
+	Context("Requested rebuilds", func() {
+		It("should coalesce rapid requests into far fewer rebuilds", func() {
+			manager.SetReindexDelay(50 * time.Millisecond)
+			before := manager.SearchStatus().Rebuilds
+
+			const requests = 50
+			for i := 0; i < requests; i++ {
+				name := fmt.Sprintf("skill-%d", i)
+				Expect(os.MkdirAll(filepath.Join(tempDir, name), 0755)).To(Succeed())
+				content := "---\nname: " + name + "\ndescription: A skill\n---\n# Kubernetes deployment guide"
+				Expect(os.WriteFile(filepath.Join(tempDir, name, "SKILL.md"), []byte(content), 0644)).To(Succeed())
+				manager.RequestReindex()
+			}
+
+			// The rebuild runs after the last request, so it indexes every skill
+			Eventually(func() int { return manager.SearchStatus().Documents }, 5*time.Second, 20*time.Millisecond).Should(Equal(requests))
+			Consistently(func() int { return manager.SearchStatus().Rebuilds }, 200*time.Millisecond).Should(BeNumerically("<=", before+2))
+		})
+
+		It("should run a pending rebuild right away when flushed", func() {
+			manager.SetReindexDelay(time.Hour)
+			Expect(os.MkdirAll(filepath.Join(tempDir, "docker"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(tempDir, "docker", "SKILL.md"), []byte("---\nname: docker\ndescription: Docker guide\n---\n# Docker"), 0644)).To(Succeed())
+			manager.RequestReindex()
+			Expect(manager.SearchStatus().Documents).To(Equal(0))
+
+			Expect(manager.FlushReindex()).To(Succeed())
+			Expect(manager.SearchStatus().Documents).To(Equal(1))
+		})
+	})
+
+	Context("Nested skills", func() {
+		BeforeEach(func() {
+			writeSkill(filepath.Join(tempDir, "repo", "a", "skill"), "name: skill\ndescription: A skill\n", "a")
+			writeSkill(filepath.Join(tempDir, "repo", "b", "skill"), "name: skill\ndescription: A skill\n", "b")
+			writeSkill(filepath.Join(tempDir, "repo", "category", "deep", "helm"), "name: helm\ndescription: A skill\n", "helm")
+			Expect(os.MkdirAll(filepath.Join(tempDir, "repo", "a", "skill", "scripts"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(tempDir, "repo", "a", "skill", "scripts", "run.sh"), []byte("#!/bin/sh"), 0644)).To(Succeed())
+			manager.UpdateGitRepos([]string{"repo"})
+		})
+
+		It("should name skills after their full path within the repo", func() {
+			skills, err := manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, skill := range skills {
+				names = append(names, skill.Name)
+			}
+			Expect(names).To(ConsistOf("repo/a/skill", "repo/b/skill", "repo/category/deep/helm"))
+		})
+
+		It("should read skills sharing a directory name by their full path", func() {
+			skill, err := manager.ReadSkill("repo/a/skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Content).To(Equal("a"))
+			Expect(skill.ReadOnly).To(BeTrue())
+
+			skill, err = manager.ReadSkill("repo/b/skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Content).To(Equal("b"))
+
+			resources, err := manager.ListSkillResources("repo/a/skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(HaveLen(1))
+			resources, err = manager.ListSkillResources("repo/b/skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(BeEmpty())
+
+			_, err = manager.ExportSkill("repo/b/skill")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should still resolve the short repoName/skillName form", func() {
+			skill, err := manager.ReadSkill("repo/helm")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Name).To(Equal("repo/category/deep/helm"))
+		})
+
+		It("should not resolve paths outside the skills directory", func() {
+			_, err := manager.ReadSkill("repo/../repo/a/skill")
+			Expect(err).To(HaveOccurred())
+			_, err = manager.ReadSkill("repo/.git/skill")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Skills manifest", func() {
+		writeManifest := func(manifest string) {
+			Expect(os.WriteFile(filepath.Join(tempDir, "repo", domain.SkillsManifestFile), []byte(manifest), 0644)).To(Succeed())
+		}
+		names := func() []string {
+			skills, err := manager.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, skill := range skills {
+				names = append(names, skill.Name)
+			}
+			return names
+		}
+
+		BeforeEach(func() {
+			writeSkill(filepath.Join(tempDir, "repo", "packages", "docker", "skill"), "name: skill\ndescription: A skill\n", "skill")
+			writeSkill(filepath.Join(tempDir, "repo", "docs", "runbooks", "oncall"), "name: oncall\ndescription: A skill\n", "oncall")
+			writeSkill(filepath.Join(tempDir, "repo", "examples", "draft"), "name: draft\ndescription: A skill\n", "draft")
+			manager.UpdateGitRepos([]string{"repo"})
+		})
+
+		It("should list only the skills in the manifest, with its overrides", func() {
+			writeManifest("skills:\n  - path: packages/docker/skill\n    description: Build and run containers\n    tags: [containers]\n    metadata:\n      team: platform\n  - path: docs/runbooks/oncall\n")
+			Expect(names()).To(ConsistOf("repo/packages/docker/skill", "repo/docs/runbooks/oncall"))
+
+			skill, err := manager.ReadSkill("repo/packages/docker/skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Metadata.Description).To(Equal("Build and run containers"))
+			Expect(skill.Metadata.Tags).To(Equal(domain.StringList{"containers"}))
+			Expect(skill.Metadata.Metadata).To(HaveKeyWithValue("team", "platform"))
+
+			skill, err = manager.ReadSkill("repo/docs/runbooks/oncall")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Metadata.Description).To(Equal("A skill"))
+
+			_, err = manager.ReadSkill("repo/examples/draft")
+			Expect(err).To(MatchError(ContainSubstring("not listed in skills.yaml")))
+		})
+
+		It("should also discover unlisted skills if the manifest asks for it", func() {
+			writeManifest("discover: true\nskills:\n  - path: packages/docker/skill\n    description: Build and run containers\n")
+			Expect(names()).To(ConsistOf("repo/packages/docker/skill", "repo/docs/runbooks/oncall", "repo/examples/draft"))
+		})
+
+		It("should report listed skills without a SKILL.md", func() {
+			writeManifest("skills:\n  - path: packages/missing\n  - path: docs/runbooks/oncall\n")
+			Expect(names()).To(ConsistOf("repo/docs/runbooks/oncall"))
+			issues, err := manager.SkillIssues()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issues).To(ConsistOf(HaveField("Path", "repo/packages/missing")))
+		})
+
+		It("should discover the skills of a repository whose manifest is invalid", func() {
+			writeManifest("skills:\n  - path: ../outside\n")
+			Expect(names()).To(ConsistOf("repo/packages/docker/skill", "repo/docs/runbooks/oncall", "repo/examples/draft"))
+			issues, err := manager.SkillIssues()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issues).To(ConsistOf(HaveField("Path", "repo/skills.yaml")))
+		})
+
+		It("should read skills against the manifest of the last scan", func() {
+			writeManifest("skills:\n  - path: docs/runbooks/oncall\n    description: Paged at night\n")
+			Expect(names()).To(ConsistOf("repo/docs/runbooks/oncall"))
+
+			// Changes to the manifest apply from the next scan, e.g. the rebuild after a sync
+			writeManifest("skills:\n  - path: examples/draft\n")
+			skill, err := manager.ReadSkill("repo/docs/runbooks/oncall")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Metadata.Description).To(Equal("Paged at night"))
+
+			Expect(manager.RebuildIndex()).To(Succeed())
+			_, err = manager.ReadSkill("repo/docs/runbooks/oncall")
+			Expect(err).To(MatchError(ContainSubstring("not listed in skills.yaml")))
+			_, err = manager.ReadSkill("repo/examples/draft")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should ignore manifests outside git repositories", func() {
+			writeSkill(filepath.Join(tempDir, "local"), "name: local\ndescription: A skill\n", "local")
+			Expect(os.WriteFile(filepath.Join(tempDir, domain.SkillsManifestFile), []byte("skills:\n  - path: nothing\n"), 0644)).To(Succeed())
+			Expect(names()).To(ContainElement("local"))
+		})
+
+		It("should reject manifests with unusable paths", func() {
+			for _, manifest := range []string{
+				"skills:\n  - description: no path\n",
+				"skills:\n  - path: /abs\n",
+				"skills:\n  - path: a/../b\n",
+				"skills:\n  - path: .hidden/skill\n",
+				"skills:\n  - path: a\n  - path: a\n",
+			} {
+				_, err := domain.ParseSkillsManifest([]byte(manifest))
+				Expect(err).To(HaveOccurred(), manifest)
+			}
+		})
+	})
+
+	Context("Stable IDs", func() {
+		It("should read a skill by its frontmatter id regardless of location", func() {
+			skillDir := filepath.Join(tempDir, "repo1", "nested", "deploy")
+			Expect(os.MkdirAll(filepath.Join(skillDir, "scripts"), 0755)).To(Succeed())
+			content := "---\nid: 7f3c2a9e-deploy\nname: deploy\ndescription: Deploy\n---\n# Deploy\n"
+			Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(content), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "scripts", "run.sh"), []byte("#!/bin/sh"), 0644)).To(Succeed())
+			manager.UpdateGitRepos([]string{"repo1"})
+
+			// Not listed yet: the lookup must rescan
+			skill, err := manager.ReadSkill("7f3c2a9e-deploy")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.ID).To(Equal("7f3c2a9e-deploy"))
+			Expect(skill.Name).To(Equal("repo1/nested/deploy"))
+
+			// Reading by location still works and reports the stable ID
+			skill, err = manager.ReadSkill("repo1/nested/deploy")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.ID).To(Equal("7f3c2a9e-deploy"))
+
+			resources, err := manager.ListSkillResources("7f3c2a9e-deploy")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(HaveLen(1))
+		})
+
+		It("should keep stable IDs up to date on writes and rescan for unknown IDs at most once in a while", func() {
+			// A name that cannot be an ID does not use up the rescan
+			writeSkill(filepath.Join(tempDir, "alpha"), "id: alpha-id\nname: alpha\ndescription: A skill\n", "# Skill\n")
+			_, err := manager.ReadSkill("not an id")
+			Expect(err).To(HaveOccurred())
+			skill, err := manager.ReadSkill("alpha-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Name).To(Equal("alpha"))
+
+			// A skill written behind the manager's back right after a rescan is not found by ID until the next scan
+			writeSkill(filepath.Join(tempDir, "beta"), "id: beta-id\nname: beta\ndescription: A skill\n", "# Skill\n")
+			_, err = manager.ReadSkill("beta-id")
+			Expect(err).To(HaveOccurred())
+
+			// Unless it was written through the manager
+			Expect(manager.UpdateSkillIndex("beta")).To(Succeed())
+			skill, err = manager.ReadSkill("beta-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(skill.Name).To(Equal("beta"))
+
+			writeSkill(filepath.Join(tempDir, "beta"), "id: beta-id-2\nname: beta\ndescription: A skill\n", "# Skill\n")
+			Expect(manager.UpdateSkillIndex("beta")).To(Succeed())
+			_, err = manager.ReadSkill("beta-id")
+			Expect(err).To(HaveOccurred())
+			_, err = manager.ReadSkill("beta-id-2")
+			Expect(err).NotTo(HaveOccurred())
+
+			skill, err = manager.ReadSkill("beta")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.RemoveAll(skill.SourcePath)).To(Succeed())
+			Expect(manager.RemoveSkillIndex(*skill)).To(Succeed())
+			_, err = manager.ReadSkill("beta-id-2")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should reject invalid ids", func() {
+			_, _, err := domain.ParseFrontmatter("---\nid: repo/skill\nname: docker\ndescription: Docker\n---\n# Docker")
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
+
+// pausingWriter signals writes on written, which needs room for one, and holds them until release is closed
+type pausingWriter struct {
+	written chan struct{}
+	release chan struct{}
+}
+
+func (w *pausingWriter) Write(p []byte) (int, error) {
+	select {
+	case w.written <- struct{}{}:
+	default:
+	}
+	<-w.release
+	return len(p), nil
+}
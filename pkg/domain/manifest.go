@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Large git repositories can declare their skills in a manifest at their root rather than having every
+// directory searched for SKILL.md files:
+//
+//	skills:
+//	  - path: packages/docker/skill
+//	    description: Build and run containers # Overrides the SKILL.md description
+//	    tags: [containers]
+//	  - path: docs/runbooks/oncall
+//	discover: true # Also list skills not in the manifest (default false)
+//
+// Listed skills are named after their path like discovered ones (repoName/packages/docker/skill).
+// Without discover, skills missing from the manifest are neither listed nor read.
+
+// SkillsManifestFile is the name of the manifest at the root of a git repository
+const SkillsManifestFile = "skills.yaml"
+
+// SkillsManifest lists the skills of a git repository
+type SkillsManifest struct {
+	Skills   []ManifestSkill `yaml:"skills"`
+	Discover bool            `yaml:"discover,omitempty"` // Also discover skills not listed
+}
+
+// ManifestSkill is a skill listed in a manifest, with optional overrides of its SKILL.md frontmatter
+type ManifestSkill struct {
+	Path        string            `yaml:"path"` // Skill directory, relative to the repository root, with forward slashes
+	Description string            `yaml:"description,omitempty"`
+	Tags        StringList        `yaml:"tags,omitempty"`
+	Metadata    map[string]string `yaml:"metadata,omitempty"` // Merged into the SKILL.md metadata
+}
+
+// ParseSkillsManifest parses and validates a skills.yaml manifest
+func ParseSkillsManifest(data []byte) (*SkillsManifest, error) {
+	var manifest SkillsManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", SkillsManifestFile, err)
+	}
+
+	seen := make(map[string]bool, len(manifest.Skills))
+	for i, skill := range manifest.Skills {
+		if err := validateManifestPath(skill.Path); err != nil {
+			return nil, fmt.Errorf("%s: skill %d: %w", SkillsManifestFile, i+1, err)
+		}
+		if seen[skill.Path] {
+			return nil, fmt.Errorf("%s: skill %d: path %q is listed more than once", SkillsManifestFile, i+1, skill.Path)
+		}
+		seen[skill.Path] = true
+
+		if len(skill.Description) > 1024 {
+			return nil, fmt.Errorf("%s: skill %s: description must be 1-1024 characters, got %d", SkillsManifestFile, skill.Path, len(skill.Description))
+		}
+		if err := ValidateSkillTags(skill.Tags); err != nil {
+			return nil, fmt.Errorf("%s: skill %s: %w", SkillsManifestFile, skill.Path, err)
+		}
+	}
+	return &manifest, nil
+}
+
+// validateManifestPath checks that a manifest path names a directory within the repository that
+// skill names can refer to
+func validateManifestPath(p string) error {
+	if p == "" {
+		return errors.New("path is required")
+	}
+	if strings.Contains(p, `\`) || path.IsAbs(p) || path.Clean(p) != p {
+		return fmt.Errorf("path %q must be a clean relative path with forward slashes", p)
+	}
+	for _, part := range strings.Split(p, "/") {
+		if part == "." || part == ".." || SkipSkillDiscovery(part) {
+			return fmt.Errorf("path %q must not contain %q", p, part)
+		}
+	}
+	return nil
+}
+
+// ReadSkillsManifest reads the manifest at the root of the git repository checked out in repoDir
+// Returns nil without an error if the repository has no manifest
+func ReadSkillsManifest(repoDir string) (*SkillsManifest, error) {
+	data, err := os.ReadFile(filepath.Join(repoDir, SkillsManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", SkillsManifestFile, err)
+	}
+	return ParseSkillsManifest(data)
+}
+
+// find returns the manifest entry of the skill at relPath within the repository, nil if not listed
+func (manifest *SkillsManifest) find(relPath string) *ManifestSkill {
+	for i := range manifest.Skills {
+		if manifest.Skills[i].Path == relPath {
+			return &manifest.Skills[i]
+		}
+	}
+	return nil
+}
+
+// repoManifests reads the manifests of the enabled git repositories
+// A repository whose manifest is invalid has its skills discovered instead, with an issue explaining why
+func (m *FileSystemManager) repoManifests() (map[string]*SkillsManifest, []SkillIssue) {
+	manifests := make(map[string]*SkillsManifest)
+	var issues []SkillIssue
+	for _, repoName := range m.gitRepos {
+		repoDir := filepath.Join(m.skillsDir, repoName)
+		manifest, err := ReadSkillsManifest(repoDir)
+		if err != nil {
+			issues = append(issues, m.skillIssue(filepath.Join(repoDir, SkillsManifestFile), repoName, err))
+			continue
+		}
+		if manifest != nil {
+			manifests[repoName] = manifest
+		}
+	}
+	return manifests, issues
+}
+
+// manifestSkill returns the manifest entry of a git repository skill, nil if its repository has no
+// valid manifest or it is discovered rather than listed
+// Manifests are those read by the last ListSkills, which reports invalid ones as issues, so reading a
+// skill never parses skills.yaml again
+// Returns an error for a skill missing from a manifest that does not allow discovery
+func (m *FileSystemManager) manifestSkill(skillPath string) (*ManifestSkill, error) {
+	relPath, err := filepath.Rel(m.skillsDir, skillPath)
+	if err != nil {
+		return nil, nil
+	}
+	repoName, repoPath, ok := strings.Cut(filepath.ToSlash(relPath), "/")
+	if !ok {
+		return nil, nil
+	}
+	m.mu.RLock()
+	manifest := m.manifests[repoName]
+	m.mu.RUnlock()
+	if manifest == nil {
+		return nil, nil
+	}
+	if entry := manifest.find(repoPath); entry != nil {
+		return entry, nil
+	}
+	if !manifest.Discover {
+		return nil, fmt.Errorf("skill is not listed in %s of %s", SkillsManifestFile, repoName)
+	}
+	return nil, nil
+}
+
+// apply overrides the frontmatter of a listed skill with the manifest's values
+func (entry *ManifestSkill) apply(metadata *SkillMetadata) {
+	if entry.Description != "" {
+		metadata.Description = entry.Description
+	}
+	if len(entry.Tags) > 0 {
+		metadata.Tags = entry.Tags
+	}
+	if len(entry.Metadata) > 0 {
+		merged := make(map[string]string, len(metadata.Metadata)+len(entry.Metadata))
+		for key, value := range metadata.Metadata {
+			merged[key] = value
+		}
+		for key, value := range entry.Metadata {
+			merged[key] = value
+		}
+		metadata.Metadata = merged
+	}
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Skills from git repositories are read-only. With a local overlay directory configured, writes to them
+// go to a copy in the overlay instead, mirroring the layout of the skills directory
+// (overlayDir/repoName/.../skillName/...). Files in the overlay shadow the git ones when reading,
+// while the git checkout itself is never modified, so syncs keep working.
+
+// ErrNoOverlay is returned when writing to a read-only skill without a local overlay configured
+var ErrNoOverlay = errors.New("cannot modify read-only skill from git repository")
+
+// SetOverlayDir enables the local overlay for git repository skills, keeping overlaid files in dir
+// An empty dir disables the overlay
+func (m *FileSystemManager) SetOverlayDir(dir string) error {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create overlay directory: %w", err)
+		}
+	}
+	m.mu.Lock()
+	m.overlayDir = dir
+	m.mu.Unlock()
+
+	// Index the overlaid SKILL.md files instead of the git ones
+	if dir == "" {
+		return nil
+	}
+	return m.RebuildIndex()
+}
+
+// OverlayDir returns the local overlay directory, empty if the overlay is disabled
+func (m *FileSystemManager) OverlayDir() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.overlayDir
+}
+
+// overlaySkillDir returns the overlay directory of a git repository skill, whether it exists or not
+// Returns an empty string for local skills or when the overlay is disabled
+func (m *FileSystemManager) overlaySkillDir(skillPath string) string {
+	overlayDir := m.OverlayDir()
+	if overlayDir == "" || !m.isGitRepoPath(skillPath) {
+		return ""
+	}
+	relPath, err := filepath.Rel(m.skillsDir, skillPath)
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(overlayDir, relPath)
+}
+
+// WritableSkillDir returns the directory writes to a skill go to: the skill's own directory for local
+// skills, its overlay directory (created if needed) for git repository skills
+// Returns ErrNoOverlay for git repository skills when the overlay is disabled
+func (m *FileSystemManager) WritableSkillDir(skill *Skill) (string, error) {
+	if !skill.ReadOnly {
+		return skill.SourcePath, nil
+	}
+	overlayDir := m.overlaySkillDir(skill.SourcePath)
+	if overlayDir == "" {
+		return "", ErrNoOverlay
+	}
+	if err := os.MkdirAll(overlayDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create overlay directory: %w", err)
+	}
+	return overlayDir, nil
+}
+
+// SkillMdPath returns the SKILL.md file a skill is read from: its overlay copy if there is one
+func (m *FileSystemManager) SkillMdPath(skill *Skill) string {
+	return m.skillMdPath(skill.SourcePath)
+}
+
+// skillMdPath returns the SKILL.md file of a skill directory, or its overlay copy if there is one
+func (m *FileSystemManager) skillMdPath(skillPath string) string {
+	if overlayDir := m.overlaySkillDir(skillPath); overlayDir != "" {
+		if _, err := os.Stat(filepath.Join(overlayDir, "SKILL.md")); err == nil {
+			return filepath.Join(overlayDir, "SKILL.md")
+		}
+	}
+	return filepath.Join(skillPath, "SKILL.md")
+}
+
+// resourceFile returns the file a resource is read from: its overlay copy if there is one, otherwise
+// the file in the skill directory
+func (m *FileSystemManager) resourceFile(skillPath, resourcePath string) (string, error) {
+	if overlayDir := m.overlaySkillDir(skillPath); overlayDir != "" {
+		if _, err := os.Stat(filepath.Join(overlayDir, resourcePath)); err == nil {
+			return ResolveResourcePath(overlayDir, resourcePath)
+		}
+	}
+	return ResolveResourcePath(skillPath, resourcePath)
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Bulk operations such as importing several skills or syncing several repositories would otherwise
+// trigger a full rebuild each. RequestReindex coalesces them: requests arriving within the reindex
+// delay of each other collapse into a single rebuild, which always runs after the last request.
+
+const (
+	// DefaultReindexDelay is how long RequestReindex waits for requests to stop arriving before rebuilding
+	DefaultReindexDelay = 250 * time.Millisecond
+	// maxReindexWaitFactor bounds how long a steady stream of requests can postpone a rebuild,
+	// as a multiple of the reindex delay
+	maxReindexWaitFactor = 20
+)
+
+// reindexState tracks a pending coalesced rebuild
+type reindexState struct {
+	timer   *time.Timer // Pending rebuild, nil if none
+	since   time.Time   // When the first request of the pending rebuild arrived
+	delay   time.Duration
+	onError func(error) // Called with the error of a failed coalesced rebuild (nil = ignored)
+}
+
+// SetReindexDelay sets how long RequestReindex waits for requests to stop arriving before rebuilding
+func (m *FileSystemManager) SetReindexDelay(delay time.Duration) {
+	m.reindexMu.Lock()
+	defer m.reindexMu.Unlock()
+	m.reindex.delay = delay
+}
+
+// SetReindexErrorHandler sets the function called with the error of a failed coalesced rebuild
+func (m *FileSystemManager) SetReindexErrorHandler(onError func(error)) {
+	m.reindexMu.Lock()
+	defer m.reindexMu.Unlock()
+	m.reindex.onError = onError
+}
+
+// RequestReindex schedules a rebuild of the search index once requests have stopped arriving for the
+// reindex delay, and returns immediately
+// Requests made while a rebuild is running schedule another one, so the index always ends up
+// reflecting the skills as they were after the last request
+func (m *FileSystemManager) RequestReindex() {
+	m.reindexMu.Lock()
+	defer m.reindexMu.Unlock()
+
+	now := time.Now()
+	if m.reindex.timer != nil && m.reindex.timer.Stop() {
+		// Postpone the pending rebuild, but not past the longest wait
+		wait := m.reindex.delay
+		if deadline := m.reindex.since.Add(maxReindexWaitFactor * m.reindex.delay); now.Add(wait).After(deadline) {
+			wait = max(deadline.Sub(now), 0)
+		}
+		m.reindex.timer.Reset(wait)
+		return
+	}
+
+	m.reindex.since = now
+	var timer *time.Timer
+	timer = time.AfterFunc(m.reindex.delay, func() {
+		m.runRequestedReindex(timer)
+	})
+	m.reindex.timer = timer
+}
+
+// FlushReindex runs a pending requested rebuild right away and waits for it
+// Does nothing if no rebuild is pending
+func (m *FileSystemManager) FlushReindex() error {
+	m.reindexMu.Lock()
+	pending := m.reindex.timer != nil && m.reindex.timer.Stop()
+	if pending {
+		m.reindex.timer = nil
+	}
+	m.reindexMu.Unlock()
+
+	if !pending {
+		return nil
+	}
+	return m.RebuildIndex()
+}
+
+// runRequestedReindex runs the rebuild scheduled by RequestReindex with timer
+func (m *FileSystemManager) runRequestedReindex(timer *time.Timer) {
+	// Requests arriving from here on schedule a new rebuild
+	m.reindexMu.Lock()
+	if m.reindex.timer == timer {
+		m.reindex.timer = nil
+	}
+	onError := m.reindex.onError
+	m.reindexMu.Unlock()
+
+	// A rebuild scheduled just before the manager was closed has nothing left to update
+	if err := m.RebuildIndex(); err != nil && !errors.Is(err, ErrIndexClosed) && onError != nil {
+		onError(err)
+	}
+}
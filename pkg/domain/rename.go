@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrRenameReadOnly is returned when renaming a skill synced from a git repository
+var ErrRenameReadOnly = errors.New("cannot rename read-only skill from git repository")
+
+// RenameSkill renames a local skill to newName, moving its directory and rewriting the frontmatter name,
+// and returns the renamed skill
+// Resources and the skill's stable ID are kept. The search index is left to the caller to update.
+func (m *FileSystemManager) RenameSkill(skillID, newName string) (*Skill, error) {
+	if err := ValidateSkillName(newName); err != nil {
+		return nil, err
+	}
+	skill, err := m.ReadSkill(skillID)
+	if err != nil {
+		return nil, err
+	}
+	if skill.ReadOnly {
+		return nil, ErrRenameReadOnly
+	}
+	if newName == skill.Name {
+		return skill, nil
+	}
+
+	targetDir := filepath.Join(filepath.Dir(skill.SourcePath), newName)
+	if _, err := os.Lstat(targetDir); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSkillExists, newName)
+	}
+	if _, err := m.ReadSkill(newName); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSkillExists, newName)
+	}
+
+	content, err := os.ReadFile(filepath.Join(skill.SourcePath, "SKILL.md"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SKILL.md: %w", err)
+	}
+	renamed, err := renameFrontmatter(string(content), newName, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(skill.SourcePath, targetDir); err != nil {
+		return nil, fmt.Errorf("failed to rename skill directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(targetDir, "SKILL.md"), []byte(renamed), 0644); err != nil {
+		// Move the directory back so the skill's name still matches its directory
+		os.Rename(targetDir, skill.SourcePath)
+		return nil, fmt.Errorf("failed to rewrite SKILL.md: %w", err)
+	}
+	return m.ReadSkill(newName)
+}
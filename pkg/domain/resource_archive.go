@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultMaxResourceFileSize is the largest file a resource archive may contain by default
+	DefaultMaxResourceFileSize = 10 * 1024 * 1024
+	// maxResourceArchiveSize is the largest total uncompressed size of a resource archive
+	maxResourceArchiveSize = 100 * 1024 * 1024
+	// maxResourceArchiveFiles is the largest number of files in a resource archive
+	maxResourceArchiveFiles = 1000
+)
+
+// resourceEntry is a file read from a resource archive
+type resourceEntry struct {
+	path string // Resource path, e.g. "scripts/tool.sh"
+	mode int64  // Mode stored in the archive
+	data []byte
+}
+
+// ExtractResourceArchive extracts a tar.gz or zip archive of resources into a skill directory
+// Every entry must be a valid resource path (under one of the resource directories of opts, by default
+// scripts/, references/ or assets/) allowed by the extension policy of opts; nothing is written unless the whole archive is valid.
+// Existing files are overwritten. Returns the paths of the extracted resources
+func ExtractResourceArchive(archiveData []byte, skillPath string, opts ImportOptions) ([]string, error) {
+	var entries []resourceEntry
+	var err error
+	switch {
+	case bytes.HasPrefix(archiveData, []byte("PK\x03\x04")):
+		entries, err = readZipResources(archiveData, opts.maxResourceFileSize())
+	case bytes.HasPrefix(archiveData, []byte{0x1f, 0x8b}):
+		entries, err = readTarGzResources(archiveData, opts.maxResourceFileSize())
+	default:
+		return nil, fmt.Errorf("unsupported archive format (expected tar.gz or zip)")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("archive does not contain any files")
+	}
+
+	resourceDirs := opts.ResourceDirs
+	if resourceDirs == nil {
+		resourceDirs = DefaultResourceDirs
+	}
+
+	// Validate every entry before writing anything
+	for _, entry := range entries {
+		if err := resourceDirs.ValidatePath(entry.path); err != nil {
+			return nil, fmt.Errorf("invalid path in archive %s: %w", entry.path, err)
+		}
+		if err := opts.Extensions.Check(entry.path); err != nil {
+			return nil, fmt.Errorf("invalid file in archive %s: %w", entry.path, err)
+		}
+		if _, err := ResolveResourcePath(skillPath, filepath.FromSlash(entry.path)); err != nil {
+			return nil, fmt.Errorf("invalid path in archive %s: %w", entry.path, err)
+		}
+	}
+
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		targetPath := filepath.Join(skillPath, filepath.FromSlash(entry.path))
+		if err := os.MkdirAll(filepath.Dir(targetPath), opts.DirMode.Perm()); err != nil {
+			return paths, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(targetPath, entry.data, opts.fileMode(entry.mode)); err != nil {
+			return paths, fmt.Errorf("failed to write %s: %w", entry.path, err)
+		}
+		paths = append(paths, entry.path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// cleanArchivePath normalizes an archive entry name into a resource path
+func cleanArchivePath(name string) (string, error) {
+	if strings.Contains(name, "..") {
+		return "", fmt.Errorf("invalid path in archive: %s", name)
+	}
+	cleaned := path.Clean(strings.TrimPrefix(filepath.ToSlash(name), "./"))
+	if path.IsAbs(cleaned) {
+		return "", fmt.Errorf("invalid path in archive: %s", name)
+	}
+	return cleaned, nil
+}
+
+// resourceArchiveReader accumulates archive entries while enforcing the size and count limits
+type resourceArchiveReader struct {
+	entries     []resourceEntry
+	total       int64
+	maxFileSize int64
+}
+
+// add reads an archive entry, rejecting it if it exceeds the limits
+func (r *resourceArchiveReader) add(name string, mode int64, content io.Reader) error {
+	resourcePath, err := cleanArchivePath(name)
+	if err != nil {
+		return err
+	}
+	if len(r.entries) >= maxResourceArchiveFiles {
+		return fmt.Errorf("archive contains too many files (max %d)", maxResourceArchiveFiles)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, r.maxFileSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if int64(len(data)) > r.maxFileSize {
+		return fmt.Errorf("file %s too large (max %d bytes)", name, r.maxFileSize)
+	}
+	r.total += int64(len(data))
+	if r.total > maxResourceArchiveSize {
+		return fmt.Errorf("archive too large when extracted (max %d bytes)", maxResourceArchiveSize)
+	}
+
+	r.entries = append(r.entries, resourceEntry{path: resourcePath, mode: mode, data: data})
+	return nil
+}
+
+// readTarGzResources reads the regular files of a tar.gz archive
+func readTarGzResources(archiveData []byte, maxFileSize int64) ([]resourceEntry, error) {
+	gzr, err := gzip.NewReader(bytes.NewReader(archiveData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gzr.Close()
+
+	reader := resourceArchiveReader{maxFileSize: maxFileSize}
+	tarReader := tar.NewReader(gzr)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar header: %w", err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			continue // Directories are created as needed
+		case tar.TypeReg:
+			if err := reader.add(header.Name, header.Mode, tarReader); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported entry in archive: %s", header.Name)
+		}
+	}
+	return reader.entries, nil
+}
+
+// readZipResources reads the regular files of a zip archive
+func readZipResources(archiveData []byte, maxFileSize int64) ([]resourceEntry, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archiveData), int64(len(archiveData)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip archive: %w", err)
+	}
+
+	reader := resourceArchiveReader{maxFileSize: maxFileSize}
+	for _, file := range zr.File {
+		mode := file.Mode()
+		if mode.IsDir() {
+			continue // Directories are created as needed
+		}
+		if !mode.IsRegular() {
+			return nil, fmt.Errorf("unsupported entry in archive: %s", file.Name)
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", file.Name, err)
+		}
+		err = reader.add(file.Name, int64(mode.Perm()), rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return reader.entries, nil
+}
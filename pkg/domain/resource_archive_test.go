@@ -0,0 +1,104 @@
+package domain_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+)
+
+var _ = Describe("Resource Archives", func() {
+	var (
+		skillDir string
+		err      error
+	)
+
+	tarGz := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		gzw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gzw)
+		for name, content := range files {
+			Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})).To(Succeed())
+			_, err := tw.Write([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).To(Succeed())
+		Expect(gzw.Close()).To(Succeed())
+		return buf.Bytes()
+	}
+
+	BeforeEach(func() {
+		skillDir, err = os.MkdirTemp("", "skillserver-resource-archive-test")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(skillDir)
+	})
+
+	It("should extract a tar.gz archive of resources", func() {
+		archive := tarGz(map[string]string{
+			"./scripts/build.sh":     "#!/bin/sh",
+			"scripts/lib/helpers.sh": "helpers",
+			"references/guide.md":    "# Guide",
+		})
+
+		paths, err := domain.ExtractResourceArchive(archive, skillDir, domain.DefaultImportOptions)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paths).To(Equal([]string{"references/guide.md", "scripts/build.sh", "scripts/lib/helpers.sh"}))
+
+		content, err := os.ReadFile(filepath.Join(skillDir, "scripts", "lib", "helpers.sh"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("helpers"))
+	})
+
+	It("should extract a zip archive of resources", func() {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, err := zw.Create("assets/data.json")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Write([]byte("{}"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(zw.Close()).To(Succeed())
+
+		paths, err := domain.ExtractResourceArchive(buf.Bytes(), skillDir, domain.DefaultImportOptions)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(paths).To(Equal([]string{"assets/data.json"}))
+		Expect(filepath.Join(skillDir, "assets", "data.json")).To(BeAnExistingFile())
+	})
+
+	It("should write nothing if any entry is outside the resource directories", func() {
+		archive := tarGz(map[string]string{
+			"scripts/ok.sh": "ok",
+			"SKILL.md":      "overwrite",
+		})
+
+		_, err := domain.ExtractResourceArchive(archive, skillDir, domain.DefaultImportOptions)
+		Expect(err).To(HaveOccurred())
+		Expect(filepath.Join(skillDir, "scripts", "ok.sh")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(skillDir, "SKILL.md")).NotTo(BeAnExistingFile())
+	})
+
+	It("should reject path traversal and disallowed file types", func() {
+		_, err := domain.ExtractResourceArchive(tarGz(map[string]string{"scripts/../../evil.sh": "x"}), skillDir, domain.DefaultImportOptions)
+		Expect(err).To(HaveOccurred())
+
+		opts := domain.DefaultImportOptions
+		opts.Extensions = domain.ExtensionPolicy{Denied: []string{".exe"}}
+		_, err = domain.ExtractResourceArchive(tarGz(map[string]string{"assets/tool.exe": "x"}), skillDir, opts)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("not allowed"))
+	})
+
+	It("should reject unknown archive formats", func() {
+		_, err := domain.ExtractResourceArchive([]byte("plain text"), skillDir, domain.DefaultImportOptions)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,8 +1,12 @@
 package domain
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"mime"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -15,43 +19,107 @@ const (
 	ResourceTypeScript    ResourceType = "script"
 	ResourceTypeReference ResourceType = "reference"
 	ResourceTypeAsset     ResourceType = "asset"
+	ResourceTypeOther     ResourceType = "other" // Files outside the resource directories, listed by ListSkillFiles
 )
 
-// SkillResource represents a resource file in a skill
-type SkillResource struct {
-	Type     ResourceType
-	Path     string    // Relative path from skill root (e.g., "scripts/script.py")
-	Name     string    // Filename only
-	Size     int64     // File size in bytes
-	MimeType string    // MIME type
-	Readable bool      // true if text file, false if binary
-	Modified time.Time // Last modification time
+// ResourceDir is a skill subdirectory holding resources of a type
+type ResourceDir struct {
+	Name string // Directory name, e.g. "scripts"
+	Type ResourceType
 }
 
-// ResourceContent represents the content of a resource
-type ResourceContent struct {
-	Content  string // UTF-8 for text, base64 for binary
-	Encoding string // "utf-8" or "base64"
-	MimeType string
-	Size     int64
+// ResourceDirs are the skill subdirectories recognized as holding resources, in order of preference
+// Files outside them are not listed as resources and cannot be written
+type ResourceDirs []ResourceDir
+
+// DefaultResourceDirs are the resource directories of the Agent Skills specification
+var DefaultResourceDirs = ResourceDirs{
+	{Name: "scripts", Type: ResourceTypeScript},
+	{Name: "references", Type: ResourceTypeReference},
+	{Name: "assets", Type: ResourceTypeAsset},
 }
 
-// ValidateResourcePath validates a resource path
-func ValidateResourcePath(path string) error {
-	// Normalize path separators
+// ParseResourceDirs parses a comma-separated list of directory=type pairs, e.g. "bin=script,docs=reference,files=asset"
+// Each type needs at least one directory; an empty list results in DefaultResourceDirs
+func ParseResourceDirs(list string) (ResourceDirs, error) {
+	if strings.TrimSpace(list) == "" {
+		return DefaultResourceDirs, nil
+	}
+
+	var dirs ResourceDirs
+	seen := make(map[string]bool)
+	for _, pair := range strings.Split(list, ",") {
+		name, typ, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		name, typ = strings.TrimSpace(name), strings.TrimSpace(typ)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid resource directory %q (expected directory=type)", pair)
+		}
+		if strings.ContainsAny(name, `/\`) || name == ".." || SkipSkillDiscovery(name) {
+			return nil, fmt.Errorf("invalid resource directory name %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("resource directory %q is listed more than once", name)
+		}
+		seen[name] = true
+
+		switch ResourceType(typ) {
+		case ResourceTypeScript, ResourceTypeReference, ResourceTypeAsset:
+		default:
+			return nil, fmt.Errorf("invalid type %q for resource directory %q (expected script, reference or asset)", typ, name)
+		}
+		dirs = append(dirs, ResourceDir{Name: name, Type: ResourceType(typ)})
+	}
+
+	for _, typ := range []ResourceType{ResourceTypeScript, ResourceTypeReference, ResourceTypeAsset} {
+		if _, ok := dirs.DirFor(typ); !ok {
+			return nil, fmt.Errorf("no resource directory for type %s", typ)
+		}
+	}
+	return dirs, nil
+}
+
+// String formats the directories as a comma-separated list of directory=type pairs, as parsed by ParseResourceDirs
+func (d ResourceDirs) String() string {
+	pairs := make([]string, len(d))
+	for i, dir := range d {
+		pairs[i] = dir.Name + "=" + string(dir.Type)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// DirFor returns the preferred directory for resources of a type
+func (d ResourceDirs) DirFor(typ ResourceType) (string, bool) {
+	for _, dir := range d {
+		if dir.Type == typ {
+			return dir.Name, true
+		}
+	}
+	return "", false
+}
+
+// dirOf returns the resource directory a resource path is in
+func (d ResourceDirs) dirOf(path string) (ResourceDir, bool) {
 	path = filepath.ToSlash(path)
+	for _, dir := range d {
+		if strings.HasPrefix(path, dir.Name+"/") {
+			return dir, true
+		}
+	}
+	return ResourceDir{}, false
+}
 
+// ValidatePath validates a resource path: it must be a relative path within one of the directories
+func (d ResourceDirs) ValidatePath(path string) error {
 	// Must start with one of the resource directory names
-	validPrefixes := []string{"scripts/", "references/", "assets/"}
-	valid := false
-	for _, prefix := range validPrefixes {
-		if strings.HasPrefix(path, prefix) {
-			valid = true
-			break
+	if _, ok := d.dirOf(path); !ok {
+		prefixes := make([]string, len(d))
+		for i, dir := range d {
+			prefixes[i] = dir.Name + "/"
 		}
-	}
-	if !valid {
-		return fmt.Errorf("resource path must start with scripts/, references/, or assets/")
+		if len(prefixes) > 1 {
+			prefixes[len(prefixes)-1] = "or " + prefixes[len(prefixes)-1]
+		}
+		return fmt.Errorf("resource path must start with %s", strings.Join(prefixes, ", "))
 	}
 
 	// Check for path traversal
@@ -67,19 +135,210 @@ func ValidateResourcePath(path string) error {
 	return nil
 }
 
-// GetResourceType determines the resource type from a path
-func GetResourceType(path string) ResourceType {
-	path = filepath.ToSlash(path)
-	if strings.HasPrefix(path, "scripts/") {
-		return ResourceTypeScript
+// TypeOf determines the resource type from the directory of a path, defaulting to asset
+func (d ResourceDirs) TypeOf(path string) ResourceType {
+	if dir, ok := d.dirOf(path); ok {
+		return dir.Type
 	}
-	if strings.HasPrefix(path, "references/") {
-		return ResourceTypeReference
+	return ResourceTypeAsset // Default fallback
+}
+
+// SkillResource represents a resource file in a skill
+type SkillResource struct {
+	Type       ResourceType
+	Path       string    // Relative path from skill root (e.g., "scripts/script.py")
+	Name       string    // Filename only
+	Size       int64     // File size in bytes
+	MimeType   string    // MIME type
+	Readable   bool      // true if text file, false if binary
+	Modified   time.Time // Last modification time
+	LFSPointer bool      // true if the file is an unresolved Git LFS pointer
+}
+
+// ResourceContent represents the content of a resource
+type ResourceContent struct {
+	Content  string // UTF-8 for text, base64 for binary
+	Encoding string // "utf-8" or "base64"
+	MimeType string
+	Size     int64
+}
+
+// lfsPointerPrefix is the header every Git LFS pointer file starts with
+const lfsPointerPrefix = "version https://git-lfs.github.com/spec/"
+
+// maxLFSPointerSize is the maximum size of a Git LFS pointer file per the LFS spec
+const maxLFSPointerSize = 1024
+
+// ErrLFSPointer is returned when reading a resource that is an unresolved Git LFS pointer
+var ErrLFSPointer = errors.New("resource is an unresolved Git LFS pointer (LFS content was not fetched)")
+
+// IsLFSPointer reports whether content looks like a Git LFS pointer file
+// size is the full file size, content may be only the beginning of the file
+func IsLFSPointer(size int64, content []byte) bool {
+	if size > maxLFSPointerSize {
+		return false
+	}
+	return bytes.HasPrefix(content, []byte(lfsPointerPrefix))
+}
+
+// SkillMetaFile is the optional sidecar file at the skill root holding per-resource overrides
+const SkillMetaFile = ".skillmeta.json"
+
+// ResourceOverride overrides auto-detected properties of a resource
+type ResourceOverride struct {
+	MimeType string `json:"mime_type,omitempty"`
+	Readable *bool  `json:"readable,omitempty"`
+}
+
+// SkillMeta represents the contents of the .skillmeta.json sidecar file
+type SkillMeta struct {
+	Resources map[string]ResourceOverride `json:"resources,omitempty"` // Keyed by resource path (e.g., "assets/data.txt")
+}
+
+// LoadSkillMeta loads the sidecar file from a skill directory
+// A missing file results in an empty SkillMeta
+func LoadSkillMeta(skillPath string) (*SkillMeta, error) {
+	meta := &SkillMeta{}
+	data, err := os.ReadFile(filepath.Join(skillPath, SkillMetaFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return meta, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", SkillMetaFile, err)
 	}
-	if strings.HasPrefix(path, "assets/") {
-		return ResourceTypeAsset
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", SkillMetaFile, err)
 	}
-	return ResourceTypeAsset // Default fallback
+	return meta, nil
+}
+
+// loadSkillMetaOrEmpty loads the sidecar file, ignoring a missing or malformed file
+// so that a broken sidecar never makes the skill's resources unavailable
+func loadSkillMetaOrEmpty(skillPath string) *SkillMeta {
+	meta, err := LoadSkillMeta(skillPath)
+	if err != nil {
+		return &SkillMeta{}
+	}
+	return meta
+}
+
+// override returns the MIME type and readable flag for a resource after applying any override
+func (m *SkillMeta) override(resourcePath, mimeType string, readable bool) (string, bool) {
+	o, ok := m.Resources[filepath.ToSlash(resourcePath)]
+	if !ok {
+		return mimeType, readable
+	}
+	if o.MimeType != "" {
+		mimeType = o.MimeType
+	}
+	if o.Readable != nil {
+		readable = *o.Readable
+	}
+	return mimeType, readable
+}
+
+// ValidateResourcePath validates a resource path against DefaultResourceDirs
+func ValidateResourcePath(path string) error {
+	return DefaultResourceDirs.ValidatePath(path)
+}
+
+// ExtensionPolicy restricts which file extensions may be stored as skill resources
+// An empty policy allows everything. Extensions are compared case-insensitively and include the leading dot
+type ExtensionPolicy struct {
+	Allowed []string // If set, only these extensions are allowed ("" allows files without an extension)
+	Denied  []string // These extensions are always rejected
+}
+
+// ParseExtensionList parses a comma-separated list such as "exe, .so,DLL" into normalized extensions
+// A lone "." stands for files without an extension
+func ParseExtensionList(list string) []string {
+	var extensions []string
+	for _, ext := range strings.Split(list, ",") {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if ext == "." {
+			ext = ""
+		} else if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		extensions = append(extensions, ext)
+	}
+	return extensions
+}
+
+// Check returns an error if the policy does not allow a file at path
+func (p ExtensionPolicy) Check(path string) error {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, denied := range p.Denied {
+		if ext == strings.ToLower(denied) {
+			return fmt.Errorf("file extension %s is not allowed", ext)
+		}
+	}
+	if len(p.Allowed) == 0 {
+		return nil
+	}
+	for _, allowed := range p.Allowed {
+		if ext == strings.ToLower(allowed) {
+			return nil
+		}
+	}
+	if ext == "" {
+		return fmt.Errorf("files without an extension are not allowed")
+	}
+	return fmt.Errorf("file extension %s is not allowed", ext)
+}
+
+// ErrResourceOutsideSkill is returned for resource paths that lead outside their skill directory through a symlink
+var ErrResourceOutsideSkill = errors.New("resource path resolves outside the skill directory")
+
+// ResolveResourcePath joins a validated resource path to a skill directory, refusing paths that lead outside
+// the skill directory through symlinks (or that are broken symlinks)
+// The path does not need to exist, so it can be used before writing a resource
+func ResolveResourcePath(skillPath, resourcePath string) (string, error) {
+	root, err := filepath.EvalSymlinks(skillPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve skill directory: %w", err)
+	}
+	fullPath := filepath.Join(skillPath, resourcePath)
+	resolved, err := resolveExisting(fullPath)
+	if err != nil {
+		return "", err
+	}
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return "", ErrResourceOutsideSkill
+	}
+	return fullPath, nil
+}
+
+// resolveExisting resolves the symlinks of the longest existing prefix of a path, keeping the rest as is
+func resolveExisting(path string) (string, error) {
+	var missing []string
+	for {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return filepath.Join(append([]string{resolved}, missing...)...), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to resolve resource path: %w", err)
+		}
+		// A broken symlink would be followed by a write, wherever it points
+		if _, lerr := os.Lstat(path); lerr == nil {
+			return "", ErrResourceOutsideSkill
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", fmt.Errorf("failed to resolve resource path: %w", err)
+		}
+		missing = append([]string{filepath.Base(path)}, missing...)
+		path = parent
+	}
+}
+
+// GetResourceType determines the resource type from a path within DefaultResourceDirs
+func GetResourceType(path string) ResourceType {
+	return DefaultResourceDirs.TypeOf(path)
 }
 
 // IsTextFile determines if a file is text-based based on MIME type
@@ -193,6 +193,121 @@ Content here.
 			_, err := manager.GetSkillResourceInfo("test-skill", "scripts/nonexistent.py")
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("should mark Git LFS pointer files and refuse to read them", func() {
+			assetsDir := filepath.Join(tempDir, "test-skill", "assets")
+			os.MkdirAll(assetsDir, 0755)
+			pointer := "version https://git-lfs.github.com/spec/v1\noid sha256:4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393\nsize 12345\n"
+			os.WriteFile(filepath.Join(assetsDir, "model.bin"), []byte(pointer), 0644)
+
+			info, err := manager.GetSkillResourceInfo("test-skill", "assets/model.bin")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.LFSPointer).To(BeTrue())
+			Expect(info.Readable).To(BeFalse())
+
+			_, err = manager.ReadSkillResource("test-skill", "assets/model.bin")
+			Expect(err).To(MatchError(domain.ErrLFSPointer))
+		})
+	})
+
+	Context("Reading Skill Files", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(filepath.Join(tempDir, "test-skill", "USAGE.md"), []byte("# Usage"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(tempDir, "secret.txt"), []byte("secret"), 0644)).To(Succeed())
+		})
+
+		It("should read a file next to SKILL.md", func() {
+			content, err := manager.ReadSkillFile("test-skill", "USAGE.md")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content.Content).To(Equal("# Usage"))
+			Expect(content.Encoding).To(Equal("utf-8"))
+			Expect(content.Size).To(Equal(int64(7)))
+		})
+
+		It("should reject paths outside the skill and hidden files", func() {
+			_, err := manager.ReadSkillFile("test-skill", "../secret.txt")
+			Expect(err).To(HaveOccurred())
+			_, err = manager.ReadSkillFile("test-skill", "/etc/passwd")
+			Expect(err).To(HaveOccurred())
+			_, err = manager.ReadSkillFile("test-skill", domain.SkillMetaFile)
+			Expect(err).To(HaveOccurred())
+
+			Expect(os.Symlink(filepath.Join(tempDir, "secret.txt"), filepath.Join(tempDir, "test-skill", "link.txt"))).To(Succeed())
+			_, err = manager.ReadSkillFile("test-skill", "link.txt")
+			Expect(err).To(MatchError(domain.ErrResourceOutsideSkill))
+		})
+
+		It("should report missing files and directories as not found", func() {
+			_, err := manager.ReadSkillFile("test-skill", "MISSING.md")
+			Expect(err).To(MatchError(os.ErrNotExist))
+			Expect(os.MkdirAll(filepath.Join(tempDir, "test-skill", "docs"), 0755)).To(Succeed())
+			_, err = manager.ReadSkillFile("test-skill", "docs")
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+	})
+
+	Context("Listing Skill Files", func() {
+		BeforeEach(func() {
+			skillDir := filepath.Join(tempDir, "test-skill")
+			for _, dir := range []string{"scripts", "config", ".git", "nested"} {
+				Expect(os.MkdirAll(filepath.Join(skillDir, dir), 0755)).To(Succeed())
+			}
+			Expect(os.WriteFile(filepath.Join(skillDir, "README.md"), []byte("# Readme"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "scripts", "run.py"), []byte("print('hi')"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "config", "settings.json"), []byte("{}"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, ".git", "HEAD"), []byte("ref"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "nested", "SKILL.md"), []byte("---\nname: nested\ndescription: Nested\n---\n"), 0644)).To(Succeed())
+		})
+
+		It("should list the files inside and outside the resource directories", func() {
+			files, err := manager.ListSkillFiles("test-skill")
+			Expect(err).NotTo(HaveOccurred())
+			types := map[string]domain.ResourceType{}
+			for _, file := range files {
+				types[file.Path] = file.Type
+			}
+			Expect(types).To(Equal(map[string]domain.ResourceType{
+				"README.md":            domain.ResourceTypeOther,
+				"config/settings.json": domain.ResourceTypeOther,
+				"scripts/run.py":       domain.ResourceTypeScript,
+			}))
+		})
+
+		It("should only list the resource directories as resources", func() {
+			resources, err := manager.ListSkillResources("test-skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(HaveLen(1))
+			Expect(resources[0].Path).To(Equal("scripts/run.py"))
+		})
+	})
+
+	Context("Sidecar Overrides", func() {
+		It("should apply MIME type and readable overrides from .skillmeta.json", func() {
+			assetsDir := filepath.Join(tempDir, "test-skill", "assets")
+			os.MkdirAll(assetsDir, 0755)
+			os.WriteFile(filepath.Join(assetsDir, "data.txt"), []byte("a,b\n1,2\n"), 0644)
+			os.WriteFile(filepath.Join(assetsDir, "custom.dat"), []byte{0x00, 0x01, 0x02}, 0644)
+			sidecar := `{"resources": {
+  "assets/data.txt": {"mime_type": "text/csv"},
+  "assets/custom.dat": {"readable": true}
+}}`
+			os.WriteFile(filepath.Join(tempDir, "test-skill", domain.SkillMetaFile), []byte(sidecar), 0644)
+
+			info, err := manager.GetSkillResourceInfo("test-skill", "assets/data.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.MimeType).To(Equal("text/csv"))
+
+			resources, err := manager.ListSkillResources("test-skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(HaveLen(2))
+			for _, res := range resources {
+				Expect(res.Readable).To(BeTrue(), "resource %s should be readable", res.Path)
+			}
+
+			content, err := manager.ReadSkillResource("test-skill", "assets/custom.dat")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content.Encoding).To(Equal("utf-8"))
+		})
 	})
 
 	Context("Path Validation", func() {
@@ -223,4 +338,155 @@ Content here.
 			}
 		})
 	})
+
+	Context("Symlinks", func() {
+		var assetsDir string
+
+		BeforeEach(func() {
+			assetsDir = filepath.Join(tempDir, "test-skill", "assets")
+			Expect(os.MkdirAll(assetsDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(assetsDir, "data.txt"), []byte("data"), 0644)).To(Succeed())
+		})
+
+		It("should refuse to read through a symlink leading outside the skill", func() {
+			Expect(os.Symlink("/etc/passwd", filepath.Join(assetsDir, "passwd"))).To(Succeed())
+
+			_, err := manager.ReadSkillResource("test-skill", "assets/passwd")
+			Expect(err).To(MatchError(domain.ErrResourceOutsideSkill))
+			_, err = manager.OpenSkillResource("test-skill", "assets/passwd")
+			Expect(err).To(MatchError(domain.ErrResourceOutsideSkill))
+			_, err = manager.GetSkillResourceInfo("test-skill", "assets/passwd")
+			Expect(err).To(MatchError(domain.ErrResourceOutsideSkill))
+
+			resources, err := manager.ListSkillResources("test-skill")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resources).To(HaveLen(1))
+			Expect(resources[0].Path).To(Equal("assets/data.txt"))
+		})
+
+		It("should refuse paths through a symlinked directory leading outside the skill", func() {
+			outside, err := os.MkdirTemp("", "skillserver-outside")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(outside)
+			Expect(os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644)).To(Succeed())
+			Expect(os.Symlink(outside, filepath.Join(tempDir, "test-skill", "references"))).To(Succeed())
+
+			_, err = manager.ReadSkillResource("test-skill", "references/secret.txt")
+			Expect(err).To(MatchError(domain.ErrResourceOutsideSkill))
+			_, err = domain.ResolveResourcePath(filepath.Join(tempDir, "test-skill"), "references/new/file.md")
+			Expect(err).To(MatchError(domain.ErrResourceOutsideSkill))
+		})
+
+		It("should refuse broken symlinks", func() {
+			Expect(os.Symlink(filepath.Join(os.TempDir(), "skillserver-missing"), filepath.Join(assetsDir, "missing"))).To(Succeed())
+			_, err := domain.ResolveResourcePath(filepath.Join(tempDir, "test-skill"), "assets/missing")
+			Expect(err).To(MatchError(domain.ErrResourceOutsideSkill))
+		})
+
+		It("should follow symlinks within the skill", func() {
+			Expect(os.Symlink("data.txt", filepath.Join(assetsDir, "alias.txt"))).To(Succeed())
+
+			content, err := manager.ReadSkillResource("test-skill", "assets/alias.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content.Content).To(Equal("data"))
+		})
+	})
+
+	Context("Custom Resource Directories", func() {
+		var dirs domain.ResourceDirs
+
+		BeforeEach(func() {
+			dirs, err = domain.ParseResourceDirs("bin=script, docs=reference, files=asset")
+			Expect(err).NotTo(HaveOccurred())
+			manager.SetResourceDirs(dirs)
+
+			for path, content := range map[string]string{
+				"bin/run.sh":          "#!/bin/sh",
+				"docs/guide/intro.md": "# Intro",
+				"files/data.txt":      "data",
+				"scripts/old.py":      "print('old')",
+			} {
+				fullPath := filepath.Join(tempDir, "test-skill", path)
+				Expect(os.MkdirAll(filepath.Dir(fullPath), 0755)).To(Succeed())
+				Expect(os.WriteFile(fullPath, []byte(content), 0644)).To(Succeed())
+			}
+		})
+
+		It("should parse and format directory lists", func() {
+			Expect(dirs.String()).To(Equal("bin=script,docs=reference,files=asset"))
+			dir, ok := dirs.DirFor(domain.ResourceTypeReference)
+			Expect(ok).To(BeTrue())
+			Expect(dir).To(Equal("docs"))
+
+			defaults, err := domain.ParseResourceDirs("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(defaults).To(Equal(domain.DefaultResourceDirs))
+		})
+
+		It("should reject invalid directory lists", func() {
+			for _, list := range []string{
+				"bin=script,docs=reference",               // no asset directory
+				"bin=script,docs=reference,files=binary",  // unknown type
+				"bin=script,bin=reference,files=asset",    // duplicate
+				"bin/x=script,docs=reference,files=asset", // nested
+				".bin=script,docs=reference,files=asset",  // hidden
+				"bin,docs=reference,files=asset",          // missing type
+			} {
+				_, err := domain.ParseResourceDirs(list)
+				Expect(err).To(HaveOccurred(), "list %q should be invalid", list)
+			}
+		})
+
+		It("should list resources in the configured directories only", func() {
+			resources, err := manager.ListSkillResources("test-skill")
+			Expect(err).NotTo(HaveOccurred())
+
+			types := map[string]domain.ResourceType{}
+			for _, res := range resources {
+				types[res.Path] = res.Type
+			}
+			Expect(types).To(Equal(map[string]domain.ResourceType{
+				"bin/run.sh":          domain.ResourceTypeScript,
+				"docs/guide/intro.md": domain.ResourceTypeReference,
+				"files/data.txt":      domain.ResourceTypeAsset,
+			}))
+		})
+
+		It("should validate paths against the configured directories", func() {
+			Expect(dirs.ValidatePath("bin/run.sh")).To(Succeed())
+			Expect(dirs.ValidatePath("scripts/old.py")).To(MatchError(ContainSubstring("bin/, docs/, or files/")))
+			Expect(dirs.ValidatePath("bin/../../etc/passwd")).NotTo(Succeed())
+			Expect(dirs.TypeOf("docs/guide/intro.md")).To(Equal(domain.ResourceTypeReference))
+
+			_, err := manager.ReadSkillResource("test-skill", "scripts/old.py")
+			Expect(err).To(HaveOccurred())
+			info, err := manager.GetSkillResourceInfo("test-skill", "files/data.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Type).To(Equal(domain.ResourceTypeAsset))
+		})
+	})
+
+	Context("Extension Policy", func() {
+		It("should allow everything by default", func() {
+			Expect(domain.ExtensionPolicy{}.Check("assets/tool.exe")).To(Succeed())
+		})
+
+		It("should reject denied extensions case-insensitively", func() {
+			policy := domain.ExtensionPolicy{Denied: domain.ParseExtensionList("exe, .SO")}
+			Expect(policy.Check("assets/tool.EXE")).NotTo(Succeed())
+			Expect(policy.Check("assets/lib.so")).NotTo(Succeed())
+			Expect(policy.Check("scripts/run.sh")).To(Succeed())
+		})
+
+		It("should only accept allowed extensions when an allowlist is set", func() {
+			policy := domain.ExtensionPolicy{Allowed: domain.ParseExtensionList(".md,py")}
+			Expect(policy.Check("references/API.md")).To(Succeed())
+			Expect(policy.Check("scripts/run.py")).To(Succeed())
+			Expect(policy.Check("scripts/run.sh")).NotTo(Succeed())
+			Expect(policy.Check("scripts/run")).NotTo(Succeed())
+
+			policy.Allowed = domain.ParseExtensionList(".md,.")
+			Expect(policy.Check("scripts/run")).To(Succeed())
+		})
+	})
 })
@@ -0,0 +1,251 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SkillFilter decides whether a skill is visible through a FilteredManager
+type SkillFilter func(skill Skill) bool
+
+// FilteredManager is a SkillManager decorator that only exposes the skills accepted by a filter
+type FilteredManager struct {
+	inner  SkillManager
+	filter SkillFilter
+}
+
+// NewFilteredManager creates a new FilteredManager wrapping inner
+func NewFilteredManager(inner SkillManager, filter SkillFilter) *FilteredManager {
+	return &FilteredManager{
+		inner:  inner,
+		filter: filter,
+	}
+}
+
+// filterSkills returns only the skills accepted by the filter
+func (f *FilteredManager) filterSkills(skills []Skill) []Skill {
+	var filtered []Skill
+	for _, skill := range skills {
+		if f.filter(skill) {
+			filtered = append(filtered, skill)
+		}
+	}
+	return filtered
+}
+
+// ListSkills returns the skills visible in this view
+func (f *FilteredManager) ListSkills() ([]Skill, error) {
+	skills, err := f.inner.ListSkills()
+	if err != nil {
+		return nil, err
+	}
+	return f.filterSkills(skills), nil
+}
+
+// ReadSkill reads a skill, reporting skills outside of this view as not found
+func (f *FilteredManager) ReadSkill(name string) (*Skill, error) {
+	skill, err := f.inner.ReadSkill(name)
+	if err != nil {
+		return nil, err
+	}
+	if !f.filter(*skill) {
+		return nil, fmt.Errorf("skill not found: %s", name)
+	}
+	return skill, nil
+}
+
+// SearchSkills searches for skills matching the query within this view
+func (f *FilteredManager) SearchSkills(query string) ([]Skill, error) {
+	skills, err := f.inner.SearchSkills(query)
+	if err != nil {
+		return nil, err
+	}
+	return f.filterSkills(skills), nil
+}
+
+// SearchSkillsWithOptions searches for skills within this view, narrowed by opts
+// If the underlying manager cannot narrow searches, only the time range and tags are applied
+func (f *FilteredManager) SearchSkillsWithOptions(query string, opts SearchOptions) ([]Skill, error) {
+	var skills []Skill
+	var err error
+	if searcher, ok := f.inner.(SkillSearcher); ok {
+		skills, err = searcher.SearchSkillsWithOptions(query, opts)
+	} else {
+		skills, err = f.inner.SearchSkills(query)
+		skills = FilterTags(FilterModified(skills, opts.Modified), opts.Tags)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f.filterSkills(skills), nil
+}
+
+// RebuildIndex rebuilds the underlying search index
+func (f *FilteredManager) RebuildIndex() error {
+	return f.inner.RebuildIndex()
+}
+
+// ListSkillResources lists the resources of a skill visible in this view
+func (f *FilteredManager) ListSkillResources(skillID string) ([]SkillResource, error) {
+	if _, err := f.ReadSkill(skillID); err != nil {
+		return nil, err
+	}
+	return f.inner.ListSkillResources(skillID)
+}
+
+// ReadSkillResource reads a resource of a skill visible in this view
+func (f *FilteredManager) ReadSkillResource(skillID, resourcePath string) (*ResourceContent, error) {
+	if _, err := f.ReadSkill(skillID); err != nil {
+		return nil, err
+	}
+	return f.inner.ReadSkillResource(skillID, resourcePath)
+}
+
+// OpenSkillResource opens a resource of a skill visible in this view, if the underlying manager supports streaming
+func (f *FilteredManager) OpenSkillResource(skillID, resourcePath string) (io.ReadSeekCloser, error) {
+	if _, err := f.ReadSkill(skillID); err != nil {
+		return nil, err
+	}
+	opener, ok := f.inner.(ResourceOpener)
+	if !ok {
+		return nil, fmt.Errorf("resource streaming is not supported")
+	}
+	return opener.OpenSkillResource(skillID, resourcePath)
+}
+
+// ListSkillFiles lists the files of a skill visible in this view, if the underlying manager supports it
+func (f *FilteredManager) ListSkillFiles(skillID string) ([]SkillResource, error) {
+	if _, err := f.ReadSkill(skillID); err != nil {
+		return nil, err
+	}
+	reader, ok := f.inner.(SkillFileReader)
+	if !ok {
+		return nil, fmt.Errorf("reading skill files is not supported")
+	}
+	return reader.ListSkillFiles(skillID)
+}
+
+// ReadSkillFile reads a file of a skill visible in this view, if the underlying manager supports it
+func (f *FilteredManager) ReadSkillFile(skillID, filePath string) (*ResourceContent, error) {
+	if _, err := f.ReadSkill(skillID); err != nil {
+		return nil, err
+	}
+	reader, ok := f.inner.(SkillFileReader)
+	if !ok {
+		return nil, fmt.Errorf("reading skill files is not supported")
+	}
+	return reader.ReadSkillFile(skillID, filePath)
+}
+
+// GetSkillResourceInfo gets resource metadata of a skill visible in this view
+func (f *FilteredManager) GetSkillResourceInfo(skillID, resourcePath string) (*SkillResource, error) {
+	if _, err := f.ReadSkill(skillID); err != nil {
+		return nil, err
+	}
+	return f.inner.GetSkillResourceInfo(skillID, resourcePath)
+}
+
+// ExportSkill exports a skill visible in this view, if the underlying manager supports exports
+func (f *FilteredManager) ExportSkill(skillID string) ([]byte, error) {
+	if _, err := f.ReadSkill(skillID); err != nil {
+		return nil, err
+	}
+	exporter, ok := f.inner.(SkillExporter)
+	if !ok {
+		return nil, fmt.Errorf("skill export is not supported")
+	}
+	return exporter.ExportSkill(skillID)
+}
+
+// Scope is a named subset of skills, exposed through its own MCP server
+type Scope struct {
+	Name   string
+	Filter SkillFilter
+}
+
+// SkillRepo returns the name of the git repository a skill comes from, or "" for local skills
+func SkillRepo(skill Skill) string {
+	if !skill.ReadOnly {
+		return ""
+	}
+	repo, _, found := strings.Cut(skill.Name, "/")
+	if !found {
+		return ""
+	}
+	return repo
+}
+
+// parseScopeSelector parses a single scope selector into a filter
+// Supported selectors are "local" and "repo:<repoName>"
+func parseScopeSelector(selector string) (SkillFilter, error) {
+	if selector == "local" {
+		return func(skill Skill) bool {
+			return !skill.ReadOnly
+		}, nil
+	}
+
+	kind, value, found := strings.Cut(selector, ":")
+	if !found || value == "" {
+		return nil, fmt.Errorf("invalid scope selector %q (expected local or repo:<name>)", selector)
+	}
+
+	switch kind {
+	case "repo":
+		return func(skill Skill) bool {
+			return SkillRepo(skill) == value
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown scope selector type %q", kind)
+	}
+}
+
+// ParseScopes parses a scope specification of the form "name=selector,selector;name2=selector"
+// A skill belongs to a scope if it matches any of the scope's selectors
+func ParseScopes(spec string) ([]Scope, error) {
+	var scopes []Scope
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(spec, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, selectors, found := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		if !found || strings.TrimSpace(selectors) == "" {
+			return nil, fmt.Errorf("invalid scope %q (expected name=selector[,selector])", part)
+		}
+		if !skillNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid scope name %q (lowercase letters, numbers, and hyphens only)", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate scope name %q", name)
+		}
+		seen[name] = true
+
+		var filters []SkillFilter
+		for _, selector := range strings.Split(selectors, ",") {
+			filter, err := parseScopeSelector(strings.TrimSpace(selector))
+			if err != nil {
+				return nil, fmt.Errorf("scope %s: %w", name, err)
+			}
+			filters = append(filters, filter)
+		}
+
+		scopes = append(scopes, Scope{
+			Name: name,
+			Filter: func(skill Skill) bool {
+				for _, filter := range filters {
+					if filter(skill) {
+						return true
+					}
+				}
+				return false
+			},
+		})
+	}
+
+	return scopes, nil
+}
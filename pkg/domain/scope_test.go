@@ -0,0 +1,75 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+)
+
+var _ = Describe("Scopes", func() {
+	var (
+		manager *domain.FileSystemManager
+		tempDir string
+		err     error
+	)
+
+	BeforeEach(func() {
+		tempDir, err = os.MkdirTemp("", "skillserver-scope-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeSkill(filepath.Join(tempDir, "local-skill"), "name: local-skill\ndescription: local-skill skill\n", "# local-skill\n")
+		writeSkill(filepath.Join(tempDir, "ops-repo", "deploy"), "name: deploy\ndescription: deploy skill\n", "# deploy\n")
+		writeSkill(filepath.Join(tempDir, "dev-repo", "lint"), "name: lint\ndescription: lint skill\n", "# lint\n")
+
+		manager, err = domain.NewFileSystemManager(tempDir, []string{"ops-repo", "dev-repo"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	Context("ParseScopes", func() {
+		It("should parse named scopes with multiple selectors", func() {
+			scopes, err := domain.ParseScopes("ops=repo:ops-repo,local; dev=repo:dev-repo")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(scopes).To(HaveLen(2))
+			Expect(scopes[0].Name).To(Equal("ops"))
+			Expect(scopes[1].Name).To(Equal("dev"))
+		})
+
+		It("should reject malformed specifications", func() {
+			for _, spec := range []string{"ops", "ops=", "Ops=local", "ops=unknown:x", "ops=local;ops=local"} {
+				_, err := domain.ParseScopes(spec)
+				Expect(err).To(HaveOccurred(), "spec %q should be invalid", spec)
+			}
+		})
+	})
+
+	Context("FilteredManager", func() {
+		It("should only expose skills in the scope", func() {
+			scopes, err := domain.ParseScopes("ops=repo:ops-repo,local")
+			Expect(err).NotTo(HaveOccurred())
+			view := domain.NewFilteredManager(manager, scopes[0].Filter)
+
+			skills, err := view.ListSkills()
+			Expect(err).NotTo(HaveOccurred())
+			var ids []string
+			for _, skill := range skills {
+				ids = append(ids, skill.ID)
+			}
+			Expect(ids).To(ConsistOf("local-skill", "ops-repo/deploy"))
+
+			_, err = view.ReadSkill("ops-repo/deploy")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = view.ReadSkill("dev-repo/lint")
+			Expect(err).To(HaveOccurred())
+			_, err = view.ListSkillResources("dev-repo/lint")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
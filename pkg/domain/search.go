@@ -1,105 +1,758 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/mapping"
+	bleveQuery "github.com/blevesearch/bleve/v2/search/query"
 )
 
+// SearchStatus describes whether full-text search is fully functional
+type SearchStatus string
+
+const (
+	// SearchStatusOK means the index is built and up to date
+	SearchStatusOK SearchStatus = "ok"
+	// SearchStatusRebuilding means a degraded index is being rebuilt and results come from a fallback scan
+	SearchStatusRebuilding SearchStatus = "rebuilding"
+	// SearchStatusDegraded means the index could not be built and results come from a fallback scan
+	SearchStatusDegraded SearchStatus = "degraded"
+)
+
+const (
+	// DefaultSearchFuzziness is the edit distance within which search terms match by default
+	DefaultSearchFuzziness = 1
+	// MaxSearchFuzziness is the largest edit distance the index supports
+	MaxSearchFuzziness = 2
+)
+
+// searchFieldBoosts weights matches in some fields above others, so a skill named after a term outranks
+// one that mentions it in passing; fields not listed have a weight of 1
+var searchFieldBoosts = map[string]float64{
+	"name":        4,
+	"description": 2,
+	"tags":        2,
+}
+
+// minPrefixLength is the shortest search term that also matches as the start of longer words
+const minPrefixLength = 3
+
+// ErrSearchUnavailable is returned by Searcher.Search when the index cannot currently be queried
+var ErrSearchUnavailable = errors.New("search index unavailable")
+
+// ErrIndexClosed is returned when re-indexing after the search index was closed
+var ErrIndexClosed = errors.New("search index is closed")
+
+// SearchStatusInfo reports the state of the search index
+type SearchStatusInfo struct {
+	Status      SearchStatus `json:"status"`
+	Error       string       `json:"error,omitempty"`        // Last indexing error when degraded
+	Documents   int          `json:"documents"`              // Number of indexed skills
+	LastIndexed *time.Time   `json:"last_indexed,omitempty"` // Time of the last successful rebuild
+	Changed     int          `json:"changed"`                // Documents written or removed by the last rebuild
+	Rebuilds    int          `json:"rebuilds"`               // Full rebuilds run since the searcher was created
+}
+
+// ModifiedRange restricts skills to those modified within a time range
+// A zero bound leaves that side of the range open; After is inclusive, Before is exclusive
+type ModifiedRange struct {
+	After  time.Time
+	Before time.Time
+}
+
+// IsZero reports whether the range does not restrict anything
+func (r ModifiedRange) IsZero() bool {
+	return r.After.IsZero() && r.Before.IsZero()
+}
+
+// Contains reports whether t falls within the range
+func (r ModifiedRange) Contains(t time.Time) bool {
+	if !r.After.IsZero() && t.Before(r.After) {
+		return false
+	}
+	if !r.Before.IsZero() && !t.Before(r.Before) {
+		return false
+	}
+	return true
+}
+
+// FilterModified returns the skills modified within the range
+func FilterModified(skills []Skill, r ModifiedRange) []Skill {
+	if r.IsZero() {
+		return skills
+	}
+	filtered := make([]Skill, 0, len(skills))
+	for _, skill := range skills {
+		if r.Contains(skill.Modified) {
+			filtered = append(filtered, skill)
+		}
+	}
+	return filtered
+}
+
+// TagFilter restricts skills to those with some or all of a set of tags
+// Tags are compared case-insensitively; skills without tags never match a non-empty filter
+type TagFilter struct {
+	Tags     []string
+	MatchAll bool // Require every tag instead of any of them
+}
+
+// IsZero reports whether the filter does not restrict anything
+func (f TagFilter) IsZero() bool {
+	return len(f.Tags) == 0
+}
+
+// Matches reports whether skill has the tags the filter asks for
+func (f TagFilter) Matches(skill Skill) bool {
+	if f.IsZero() {
+		return true
+	}
+	tags := skillTags(skill)
+	for _, tag := range f.Tags {
+		found := slices.Contains(tags, normalizeTag(tag))
+		if f.MatchAll && !found {
+			return false
+		}
+		if !f.MatchAll && found {
+			return true
+		}
+	}
+	return f.MatchAll
+}
+
+// FilterTags returns the skills matching the tag filter
+func FilterTags(skills []Skill, f TagFilter) []Skill {
+	if f.IsZero() {
+		return skills
+	}
+	filtered := make([]Skill, 0, len(skills))
+	for _, skill := range skills {
+		if f.Matches(skill) {
+			filtered = append(filtered, skill)
+		}
+	}
+	return filtered
+}
+
+// SkillSort is the order of a skill listing
+type SkillSort string
+
+const (
+	// SortDefault keeps the order skills are listed in
+	SortDefault SkillSort = ""
+	// SortByName orders skills by name
+	SortByName SkillSort = "name"
+	// SortByModified orders skills by SKILL.md modification time, most recent first
+	SortByModified SkillSort = "modified"
+	// SortByCreated orders skills by creation time, most recent first
+	SortByCreated SkillSort = "created"
+)
+
+// ParseSkillSort parses a skill order: "name", "modified", "created", or empty for the listing order
+func ParseSkillSort(s string) (SkillSort, error) {
+	switch order := SkillSort(strings.ToLower(strings.TrimSpace(s))); order {
+	case SortDefault, SortByName, SortByModified, SortByCreated:
+		return order, nil
+	default:
+		return SortDefault, fmt.Errorf("sort must be \"name\", \"modified\" or \"created\"")
+	}
+}
+
+// SortSkills orders skills in place; skills that compare equal keep their relative order
+func SortSkills(skills []Skill, by SkillSort) {
+	switch by {
+	case SortByName:
+		sort.SliceStable(skills, func(i, j int) bool { return skills[i].Name < skills[j].Name })
+	case SortByModified:
+		sort.SliceStable(skills, func(i, j int) bool { return skills[i].Modified.After(skills[j].Modified) })
+	case SortByCreated:
+		sort.SliceStable(skills, func(i, j int) bool { return skills[i].Created.After(skills[j].Created) })
+	}
+}
+
+// skillTags returns the normalized frontmatter tags and comma-separated metadata "tags" of a skill
+func skillTags(skill Skill) []string {
+	if skill.Metadata == nil {
+		return nil
+	}
+	var tags []string
+	for _, tag := range skill.Metadata.Tags {
+		tags = append(tags, normalizeTag(tag))
+	}
+	for _, tag := range strings.Split(skill.Metadata.Metadata["tags"], ",") {
+		if tag = normalizeTag(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// normalizeTag returns the form tags are compared and indexed in
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// SearchFields are the skill fields that can be searched
+// metadata covers every value of the frontmatter metadata map, tags the frontmatter tags and the
+// comma-separated metadata "tags" value
+var SearchFields = []string{"name", "description", "content", "license", "compatibility", "metadata", "tags"}
+
+// SearchOptions narrows a search
+type SearchOptions struct {
+	Modified ModifiedRange // Only match skills modified within this range
+	Tags     TagFilter     // Only match skills with these tags
+	Fields   []string      // Only search these fields (see SearchFields); empty searches all of them
+}
+
+// Validate returns an error if the options name a field that cannot be searched
+func (o SearchOptions) Validate() error {
+	for _, field := range o.Fields {
+		if !slices.Contains(SearchFields, field) {
+			return fmt.Errorf("unknown search field %q (must be one of %s)", field, strings.Join(SearchFields, ", "))
+		}
+	}
+	return nil
+}
+
+// searchFields returns the fields to search
+func (o SearchOptions) searchFields() []string {
+	if len(o.Fields) == 0 {
+		return SearchFields
+	}
+	return o.Fields
+}
+
+// skillFieldText returns the searchable text of a skill field
+func skillFieldText(skill Skill, field string) string {
+	switch field {
+	case "name":
+		return skill.Name
+	case "content":
+		return skill.Content
+	}
+	if skill.Metadata == nil {
+		return ""
+	}
+	switch field {
+	case "description":
+		return skill.Metadata.Description
+	case "license":
+		return skill.Metadata.License
+	case "compatibility":
+		return skill.Metadata.Compatibility
+	case "metadata":
+		// Flatten the values in a stable order
+		keys := make([]string, 0, len(skill.Metadata.Metadata))
+		for key := range skill.Metadata.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		values := make([]string, 0, len(keys))
+		for _, key := range keys {
+			values = append(values, skill.Metadata.Metadata[key])
+		}
+		return strings.Join(values, "\n")
+	case "tags":
+		tags := append([]string{}, skill.Metadata.Tags...)
+		for _, tag := range strings.Split(skill.Metadata.Metadata["tags"], ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		return strings.Join(tags, " ")
+	}
+	return ""
+}
+
+// ParseTimeFilter parses an RFC3339 timestamp, or a duration before now such as "7d", "2w" or "12h"
+func ParseTimeFilter(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	// Days and weeks are not supported by time.ParseDuration
+	var d time.Duration
+	var err error
+	switch {
+	case strings.HasSuffix(value, "d"), strings.HasSuffix(value, "w"):
+		unit := 24 * time.Hour
+		if strings.HasSuffix(value, "w") {
+			unit *= 7
+		}
+		var n int
+		n, err = strconv.Atoi(value[:len(value)-1])
+		d = time.Duration(n) * unit
+	default:
+		d, err = time.ParseDuration(value)
+	}
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid time %q: must be an RFC3339 timestamp or a duration such as 7d, 2w or 12h", value)
+	}
+	return now.Add(-d), nil
+}
+
+// LegacyIndexDirName is the directory inside the skills directory that held the search index in earlier versions
+const LegacyIndexDirName = ".index"
+
+// indexBuildSuffix is appended to the index path for the directory a replacement index is built in
+const indexBuildSuffix = ".new"
+
+// DefaultIndexDir returns where the search index of skillsDir is kept by default: a directory named after
+// skillsDir in the user's cache directory, or a hidden sibling of skillsDir if there is no cache directory
+func DefaultIndexDir(skillsDir string) string {
+	absDir, err := filepath.Abs(skillsDir)
+	if err != nil {
+		absDir = filepath.Clean(skillsDir)
+	}
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		sum := sha256.Sum256([]byte(absDir))
+		return filepath.Join(cacheDir, "skillserver", "index-"+hex.EncodeToString(sum[:8]))
+	}
+	return filepath.Join(filepath.Dir(absDir), "."+filepath.Base(absDir)+"-index")
+}
+
 // Searcher handles full-text search using bleve
 type Searcher struct {
 	indexPath string
 	index     bleve.Index
+	fuzziness int   // Edit distance within which terms match
+	recovered error // Why an existing index was discarded when the searcher was created, if it was
+	closed    bool  // Set by Close; the index is never reopened afterwards
+
+	rebuildMu   sync.Mutex   // Serializes rebuilds
+	mu          sync.RWMutex // Guards index and the status fields below
+	status      SearchStatus
+	lastErr     error
+	documents   int
+	changed     int
+	rebuilds    int
+	lastIndexed time.Time
 }
 
-// NewSearcher creates a new Searcher with a bleve index
-func NewSearcher(skillsDir string) (*Searcher, error) {
-	indexPath := filepath.Join(skillsDir, ".index")
+// NewSearcher creates a new Searcher with a bleve index in indexPath, creating it if needed
+func NewSearcher(indexPath string) (*Searcher, error) {
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create search index directory: %w", err)
+	}
 
 	// Try to open existing index
+	var recovered error
 	index, err := bleve.Open(indexPath)
+	switch {
+	case errors.Is(err, bleve.ErrorIndexPathDoesNotExist):
+		index, err = bleve.New(indexPath, newIndexMapping())
+	case err != nil:
+		// A process killed mid-write can leave an index that cannot be opened. The index only mirrors
+		// the skills on disk, so start over and let the caller rebuild it
+		recovered = fmt.Errorf("search index %s could not be opened and was recreated: %w", indexPath, err)
+		index, err = recreateIndex(indexPath)
+	case index.Mapping().AnalyzerNameForPath("tag") != keyword.Name:
+		// Indexes created before tag filtering cannot match tags exactly, start over
+		index.Close()
+		index, err = recreateIndex(indexPath)
+	}
 	if err != nil {
-		// Create new index if it doesn't exist
-		mapping := bleve.NewIndexMapping()
-		index, err = bleve.New(indexPath, mapping)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create search index: %w", err)
-		}
+		return nil, fmt.Errorf("failed to create search index: %w", err)
 	}
 
 	return &Searcher{
 		indexPath: indexPath,
 		index:     index,
+		fuzziness: DefaultSearchFuzziness,
+		recovered: recovered,
+		status:    SearchStatusOK,
 	}, nil
 }
 
-// IndexSkills indexes a list of skills
+// ownsPath reports whether path is the index directory or the directory a replacement index is built in
+func (s *Searcher) ownsPath(path string) bool {
+	path = filepath.Clean(path)
+	return path == filepath.Clean(s.indexPath) || path == filepath.Clean(s.indexPath+indexBuildSuffix)
+}
+
+// recreateIndex replaces whatever is at indexPath with a new, empty index
+func recreateIndex(indexPath string) (bleve.Index, error) {
+	if err := os.RemoveAll(indexPath); err != nil {
+		return nil, fmt.Errorf("failed to remove old index: %w", err)
+	}
+	return bleve.New(indexPath, newIndexMapping())
+}
+
+// Recovered returns why an existing index could not be opened and was replaced by an empty one,
+// or nil if it was opened normally
+func (s *Searcher) Recovered() error {
+	return s.recovered
+}
+
+// SetFuzziness sets the edit distance within which search terms match, from 0 (exact) to MaxSearchFuzziness
+func (s *Searcher) SetFuzziness(fuzziness int) error {
+	if fuzziness < 0 || fuzziness > MaxSearchFuzziness {
+		return fmt.Errorf("search fuzziness must be between 0 and %d", MaxSearchFuzziness)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fuzziness = fuzziness
+	return nil
+}
+
+// Fuzziness returns the edit distance within which search terms match
+func (s *Searcher) Fuzziness() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fuzziness
+}
+
+// Status returns the current state of the search index
+func (s *Searcher) Status() SearchStatusInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	info := SearchStatusInfo{
+		Status:    s.status,
+		Documents: s.documents,
+		Changed:   s.changed,
+		Rebuilds:  s.rebuilds,
+	}
+	if s.lastErr != nil {
+		info.Error = s.lastErr.Error()
+	}
+	if !s.lastIndexed.IsZero() {
+		lastIndexed := s.lastIndexed
+		info.LastIndexed = &lastIndexed
+	}
+	return info
+}
+
+// setStatus records the state of the search index
+func (s *Searcher) setStatus(status SearchStatus, err error) {
+	s.status = status
+	s.lastErr = err
+}
+
+// IndexSkills brings the index in line with a list of skills
+// Only skills that changed since they were indexed are written, and skills that are gone are removed.
+// If the index cannot be updated in place, a new one is built next to it, which keeps serving searches
+// until it is swapped in. If that fails too, the searcher reports itself as degraded
 func (s *Searcher) IndexSkills(skills []Skill) error {
-	// Clear existing index by deleting and recreating
-	s.index.Close()
-	os.RemoveAll(s.indexPath)
+	s.rebuildMu.Lock()
+	defer s.rebuildMu.Unlock()
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrIndexClosed
+	}
+	s.rebuilds++
+	if s.status != SearchStatusOK {
+		s.setStatus(SearchStatusRebuilding, s.lastErr)
+	}
+	s.mu.Unlock()
 
-	mapping := bleve.NewIndexMapping()
-	index, err := bleve.New(s.indexPath, mapping)
+	changed, err := s.updateIndex(skills)
 	if err != nil {
-		return fmt.Errorf("failed to recreate index: %w", err)
+		// Start over from an empty index
+		buildPath := s.indexPath + indexBuildSuffix
+		var index bleve.Index
+		index, err = buildIndex(buildPath, skills)
+		if err == nil {
+			err = s.swapIndex(index, buildPath)
+		}
+		if err != nil {
+			os.RemoveAll(buildPath)
+			s.mu.Lock()
+			s.setStatus(SearchStatusDegraded, err)
+			s.mu.Unlock()
+			return err
+		}
+		changed = len(skills)
 	}
-	s.index = index
 
-	// Index each skill
+	s.mu.Lock()
+	s.documents = len(skills)
+	s.changed = changed
+	s.lastIndexed = time.Now()
+	s.setStatus(SearchStatusOK, nil)
+	s.mu.Unlock()
+	return nil
+}
+
+// updateIndex writes the skills whose document hash differs from the indexed one and removes the
+// documents of skills that are gone. Returns the number of documents written or removed
+func (s *Searcher) updateIndex(skills []Skill) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.index == nil {
+		return 0, fmt.Errorf("index is not open")
+	}
+
+	indexed, err := indexedHashes(s.index)
+	if err != nil {
+		return 0, err
+	}
+
+	batch := s.index.NewBatch()
 	for _, skill := range skills {
-		doc := map[string]any{
-			"name":    skill.Name,
-			"content": skill.Content,
-		}
-		if skill.Metadata != nil {
-			if skill.Metadata.Description != "" {
-				doc["description"] = skill.Metadata.Description
-			}
-			// Index metadata fields if present
-			if skill.Metadata.License != "" {
-				doc["license"] = skill.Metadata.License
-			}
-			if skill.Metadata.Compatibility != "" {
-				doc["compatibility"] = skill.Metadata.Compatibility
+		id := documentID(skill)
+		doc, hash := skillDocument(skill)
+		if indexed[id] != hash {
+			if err := batch.Index(id, doc); err != nil {
+				return 0, fmt.Errorf("failed to index skill %s: %w", skill.Name, err)
 			}
 		}
-		if err := index.Index(skill.Name, doc); err != nil {
+		delete(indexed, id)
+	}
+	for id := range indexed {
+		batch.Delete(id)
+	}
+
+	changed := batch.Size()
+	if changed == 0 {
+		return 0, nil
+	}
+	if err := s.index.Batch(batch); err != nil {
+		return 0, fmt.Errorf("failed to write index: %w", err)
+	}
+	return changed, nil
+}
+
+// IndexSkill adds or updates the document of a single skill, leaving the other documents untouched
+// Returns ErrSearchUnavailable if the index is being rebuilt or is degraded, in which case a full rebuild is needed
+func (s *Searcher) IndexSkill(skill Skill) error {
+	return s.modifyIndex(func(index bleve.Index) error {
+		doc, _ := skillDocument(skill)
+		if err := index.Index(documentID(skill), doc); err != nil {
 			return fmt.Errorf("failed to index skill %s: %w", skill.Name, err)
 		}
+		return nil
+	})
+}
+
+// RemoveFromIndex removes the document with the given ID (the skill's directory, see documentID)
+// Returns ErrSearchUnavailable if the index is being rebuilt or is degraded, in which case a full rebuild is needed
+func (s *Searcher) RemoveFromIndex(id string) error {
+	return s.modifyIndex(func(index bleve.Index) error {
+		if err := index.Delete(id); err != nil {
+			return fmt.Errorf("failed to remove %s from index: %w", id, err)
+		}
+		return nil
+	})
+}
+
+// modifyIndex applies a single-document change to the index and refreshes the document count
+func (s *Searcher) modifyIndex(change func(index bleve.Index) error) error {
+	s.rebuildMu.Lock()
+	defer s.rebuildMu.Unlock()
+
+	s.mu.RLock()
+	if s.status != SearchStatusOK || s.index == nil {
+		s.mu.RUnlock()
+		return ErrSearchUnavailable
+	}
+	err := change(s.index)
+	count, countErr := s.index.DocCount()
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if countErr == nil {
+		s.mu.Lock()
+		s.documents = int(count)
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// indexedHashes returns the document hash of every indexed skill, keyed by document ID
+func indexedHashes(index bleve.Index) (map[string]string, error) {
+	count, err := index.DocCount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count indexed documents: %w", err)
+	}
+	hashes := make(map[string]string, count)
+	if count == 0 {
+		return hashes, nil
+	}
+
+	req := bleve.NewSearchRequest(bleve.NewMatchAllQuery())
+	req.Size = int(count)
+	req.Fields = []string{"hash"}
+	results, err := index.Search(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexed documents: %w", err)
+	}
+	for _, hit := range results.Hits {
+		hash, _ := hit.Fields["hash"].(string)
+		hashes[hit.ID] = hash
+	}
+	return hashes, nil
+}
+
+// skillDocument returns the index document of a skill, and a hash of its content to detect changes
+func skillDocument(skill Skill) (map[string]any, string) {
+	doc := map[string]any{
+		"name":     skill.Name,
+		"id":       skill.ID,
+		"modified": skill.Modified,
+	}
+	// Index searchable fields if present
+	for _, field := range SearchFields {
+		if text := skillFieldText(skill, field); text != "" {
+			doc[field] = text
+		}
+	}
+	if tags := skillTags(skill); len(tags) > 0 {
+		doc["tag"] = tags
 	}
 
+	// Maps are marshaled with sorted keys, so equal documents hash the same
+	data, _ := json.Marshal(doc)
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	doc["hash"] = hash
+	return doc, hash
+}
+
+// swapIndex replaces the current index with the one built at buildPath
+// Searches are blocked only while the index directory is moved into place
+func (s *Searcher) swapIndex(index bleve.Index, buildPath string) error {
+	// The index has to be closed to be moved
+	if err := index.Close(); err != nil {
+		return fmt.Errorf("failed to close new index: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.index != nil {
+		s.index.Close()
+		s.index = nil
+	}
+	if err := os.RemoveAll(s.indexPath); err != nil {
+		return fmt.Errorf("failed to remove old index: %w", err)
+	}
+	if err := os.Rename(buildPath, s.indexPath); err != nil {
+		return fmt.Errorf("failed to move new index into place: %w", err)
+	}
+	opened, err := bleve.Open(s.indexPath)
+	if err != nil {
+		return fmt.Errorf("failed to open new index: %w", err)
+	}
+	s.index = opened
 	return nil
 }
 
+// newIndexMapping returns the mapping of the search index
+// Tags are indexed whole, so filters match "machine-learning" but not "machine"
+func newIndexMapping() *mapping.IndexMappingImpl {
+	tagMapping := bleve.NewTextFieldMapping()
+	tagMapping.Analyzer = keyword.Name
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultMapping.AddFieldMappingsAt("tag", tagMapping)
+	return indexMapping
+}
+
+// buildIndex creates a new index at indexPath containing skills
+func buildIndex(indexPath string, skills []Skill) (bleve.Index, error) {
+	os.RemoveAll(indexPath)
+
+	index, err := bleve.New(indexPath, newIndexMapping())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create index: %w", err)
+	}
+
+	// Index each skill
+	batch := index.NewBatch()
+	for _, skill := range skills {
+		doc, _ := skillDocument(skill)
+		if err := batch.Index(documentID(skill), doc); err != nil {
+			index.Close()
+			return nil, fmt.Errorf("failed to index skill %s: %w", skill.Name, err)
+		}
+	}
+	if err := index.Batch(batch); err != nil {
+		index.Close()
+		return nil, fmt.Errorf("failed to write index: %w", err)
+	}
+
+	return index, nil
+}
+
 // Search performs a full-text search and returns matching skills
+// Returns ErrSearchUnavailable if the index is being rebuilt or is degraded
 func (s *Searcher) Search(query string) ([]Skill, error) {
+	return s.SearchWithOptions(query, SearchOptions{})
+}
+
+// SearchWithOptions performs a full-text search narrowed by opts
+// Returns ErrSearchUnavailable if the index is being rebuilt or is degraded
+func (s *Searcher) SearchWithOptions(query string, opts SearchOptions) ([]Skill, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Rebuilds only hold the write lock while swapping in a new index
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.status != SearchStatusOK || s.closed {
+		return nil, ErrSearchUnavailable
+	}
 	if s.index == nil {
 		return []Skill{}, nil
 	}
 
 	// Create a disjunction query to search across multiple fields
-	contentQuery := bleve.NewMatchQuery(query)
-	contentQuery.SetField("content")
-
-	nameQuery := bleve.NewMatchQuery(query)
-	nameQuery.SetField("name")
-
-	descQuery := bleve.NewMatchQuery(query)
-	descQuery.SetField("description")
-
-	licenseQuery := bleve.NewMatchQuery(query)
-	licenseQuery.SetField("license")
+	var fieldQueries []bleveQuery.Query
+	for _, field := range opts.searchFields() {
+		fieldQueries = append(fieldQueries, s.fieldQuery(query, field)...)
+	}
+	var searchQuery bleveQuery.Query = bleve.NewDisjunctionQuery(fieldQueries...)
 
-	compatibilityQuery := bleve.NewMatchQuery(query)
-	compatibilityQuery.SetField("compatibility")
+	// Restrict to the modification time range, if any
+	if modified := opts.Modified; !modified.IsZero() {
+		dateQuery := bleve.NewDateRangeQuery(modified.After, modified.Before)
+		dateQuery.SetField("modified")
+		searchQuery = bleve.NewConjunctionQuery(searchQuery, dateQuery)
+	}
 
-	disjunction := bleve.NewDisjunctionQuery(contentQuery, nameQuery, descQuery, licenseQuery, compatibilityQuery)
+	// Restrict to skills with any or all of the tags, if any
+	if tags := opts.Tags; !tags.IsZero() {
+		tagQueries := make([]bleveQuery.Query, len(tags.Tags))
+		for i, tag := range tags.Tags {
+			tagQuery := bleve.NewTermQuery(normalizeTag(tag))
+			tagQuery.SetField("tag")
+			tagQueries[i] = tagQuery
+		}
+		if tags.MatchAll {
+			searchQuery = bleve.NewConjunctionQuery(append([]bleveQuery.Query{searchQuery}, tagQueries...)...)
+		} else {
+			searchQuery = bleve.NewConjunctionQuery(searchQuery, bleve.NewDisjunctionQuery(tagQueries...))
+		}
+	}
 
-	req := bleve.NewSearchRequest(disjunction)
+	// Hits come ordered by score, highest first
+	req := bleve.NewSearchRequest(searchQuery)
 	req.Size = 100 // Limit results
+	req.Fields = []string{"name", "id"}
+	req.Highlight = bleve.NewHighlight()
+	req.Highlight.AddField("content")
 
 	searchResults, err := s.index.Search(req)
 	if err != nil {
@@ -108,20 +761,80 @@ func (s *Searcher) Search(query string) ([]Skill, error) {
 
 	var skills []Skill
 	for _, hit := range searchResults.Hits {
-		// The hit.ID is the skill name/ID used for indexing
-		skills = append(skills, Skill{
-			Name: hit.ID,
-			ID:   hit.ID, // ID is the same as Name
-		})
+		// The hit.ID is the document ID, the name and ID are stored alongside it
+		name, _ := hit.Fields["name"].(string)
+		id, _ := hit.Fields["id"].(string)
+		skill := Skill{
+			Name:       name,
+			ID:         id,
+			SourcePath: hit.ID,
+			Score:      hit.Score,
+		}
+		if fragments := hit.Fragments["content"]; len(fragments) > 0 {
+			skill.Snippet = fragments[0]
+		}
+		skills = append(skills, skill)
 	}
 
 	return skills, nil
 }
 
-// Close closes the search index
+// fieldQuery returns the queries matching query in one field: exact terms, which rank highest,
+// terms within the configured edit distance, and the start of longer words ("kubernet" matches "kubernetes")
+// Match queries go through the field's analyzer, which lowercases; prefixes are lowercased here
+func (s *Searcher) fieldQuery(query, field string) []bleveQuery.Query {
+	boost := 1.0
+	if fieldBoost, ok := searchFieldBoosts[field]; ok {
+		boost = fieldBoost
+	}
+
+	exact := bleve.NewMatchQuery(query)
+	exact.SetField(field)
+	exact.SetBoost(2 * boost)
+	queries := []bleveQuery.Query{exact}
+
+	if s.fuzziness > 0 {
+		fuzzy := bleve.NewMatchQuery(query)
+		fuzzy.SetField(field)
+		fuzzy.SetFuzziness(s.fuzziness)
+		fuzzy.SetBoost(boost)
+		queries = append(queries, fuzzy)
+	}
+
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if len(term) < minPrefixLength {
+			continue
+		}
+		prefix := bleve.NewPrefixQuery(term)
+		prefix.SetField(field)
+		prefix.SetBoost(boost)
+		queries = append(queries, prefix)
+	}
+	return queries
+}
+
+// documentID returns the index document ID of a skill
+// Neither names (nested git skills may share one) nor frontmatter ids (which can be duplicated) are
+// guaranteed to be unique, so skills are keyed by their directory
+func documentID(skill Skill) string {
+	if skill.SourcePath != "" {
+		return skill.SourcePath
+	}
+	return skill.Name
+}
+
+// Close closes the search index, waiting for a running rebuild to finish
+// Searches fall back to scanning skills afterwards, and closing again does nothing
 func (s *Searcher) Close() error {
-	if s.index != nil {
-		return s.index.Close()
+	s.rebuildMu.Lock()
+	defer s.rebuildMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.index == nil {
+		return nil
 	}
-	return nil
+	index := s.index
+	s.index = nil
+	return index.Close()
 }
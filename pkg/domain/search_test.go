@@ -0,0 +1,321 @@
+package domain_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+)
+
+var _ = Describe("Searcher", func() {
+	var (
+		searcher *domain.Searcher
+		tempDir  string
+		skills   []domain.Skill
+		err      error
+	)
+
+	BeforeEach(func() {
+		tempDir, err = os.MkdirTemp("", "skillserver-search-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		searcher, err = domain.NewSearcher(filepath.Join(tempDir, "index"))
+		Expect(err).NotTo(HaveOccurred())
+
+		skills = nil
+		for i := 0; i < 20; i++ {
+			skills = append(skills, domain.Skill{
+				Name:     fmt.Sprintf("skill-%d", i),
+				ID:       fmt.Sprintf("skill-%d", i),
+				Content:  "kubernetes deployment guide",
+				Metadata: &domain.SkillMetadata{Description: "A skill"},
+			})
+		}
+		Expect(searcher.IndexSkills(skills)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		searcher.Close()
+		os.RemoveAll(tempDir)
+	})
+
+	It("should keep serving searches from the index while it is rebuilt", func() {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				Expect(searcher.IndexSkills(skills)).To(Succeed())
+			}
+		}()
+
+		for i := 0; i < 50; i++ {
+			results, err := searcher.Search("kubernetes")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(len(skills)))
+		}
+		wg.Wait()
+
+		status := searcher.Status()
+		Expect(status.Status).To(Equal(domain.SearchStatusOK))
+		Expect(status.Documents).To(Equal(len(skills)))
+	})
+
+	It("should only write the documents that changed", func() {
+		// Nothing changed since the index was built
+		Expect(searcher.IndexSkills(skills)).To(Succeed())
+		Expect(searcher.Status().Changed).To(Equal(0))
+
+		updated := append([]domain.Skill{}, skills[1:]...)
+		updated[0].Content = "helm chart guide"
+		Expect(searcher.IndexSkills(updated)).To(Succeed())
+		status := searcher.Status()
+		Expect(status.Changed).To(Equal(2)) // skill-1 updated, skill-0 removed
+		Expect(status.Documents).To(Equal(len(skills) - 1))
+
+		results, err := searcher.Search("helm")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Name).To(Equal("skill-1"))
+
+		results, err = searcher.Search("kubernetes")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(len(skills) - 2))
+	})
+
+	It("should replace an index that cannot be opened", func() {
+		corruptDir, err := os.MkdirTemp("", "skillserver-search-test")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(corruptDir)
+		Expect(os.MkdirAll(filepath.Join(corruptDir, ".index"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(corruptDir, ".index", "index_meta.json"), []byte("{not json"), 0644)).To(Succeed())
+
+		recovered, err := domain.NewSearcher(filepath.Join(corruptDir, ".index"))
+		Expect(err).NotTo(HaveOccurred())
+		defer recovered.Close()
+		Expect(recovered.Recovered()).To(HaveOccurred())
+
+		Expect(recovered.IndexSkills(skills)).To(Succeed())
+		results, err := recovered.Search("kubernetes")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(len(skills)))
+
+		Expect(searcher.Recovered()).NotTo(HaveOccurred())
+	})
+
+	It("should add and remove a single skill without touching the others", func() {
+		Expect(searcher.IndexSkill(domain.Skill{Name: "helm", Content: "helm chart guide"})).To(Succeed())
+		Expect(searcher.Status().Documents).To(Equal(len(skills) + 1))
+
+		results, err := searcher.Search("helm")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		results, err = searcher.Search("kubernetes")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(len(skills)))
+
+		Expect(searcher.RemoveFromIndex("helm")).To(Succeed())
+		Expect(searcher.Status().Documents).To(Equal(len(skills)))
+		results, err = searcher.Search("helm")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(BeEmpty())
+	})
+
+	Context("with partial, misspelled or differently cased queries", func() {
+		BeforeEach(func() {
+			Expect(searcher.IndexSkills(append(skills,
+				domain.Skill{Name: "docker", Content: "Build and run containers", Metadata: &domain.SkillMetadata{Description: "Docker guide"}},
+			))).To(Succeed())
+		})
+
+		It("should match the start of longer words", func() {
+			results, err := searcher.Search("kubernet")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(len(skills)))
+		})
+
+		It("should ignore case", func() {
+			results, err := searcher.Search("DOCKER")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+			Expect(results[0].Name).To(Equal("docker"))
+		})
+
+		It("should match terms within the configured edit distance", func() {
+			results, err := searcher.Search("dxckxr")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(BeEmpty())
+
+			Expect(searcher.SetFuzziness(2)).To(Succeed())
+			results, err = searcher.Search("dxckxr")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+
+			Expect(searcher.SetFuzziness(0)).To(Succeed())
+			results, err = searcher.Search("dockxr")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(BeEmpty())
+
+			Expect(searcher.SetFuzziness(1)).To(Succeed())
+			results, err = searcher.Search("dockxr")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(1))
+		})
+
+		It("should rank exact matches first", func() {
+			Expect(searcher.IndexSkill(domain.Skill{Name: "dockers", Content: "dockers"})).To(Succeed())
+			results, err := searcher.Search("docker")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(2))
+			Expect(results[0].Name).To(Equal("docker"))
+		})
+
+		It("should reject an unsupported fuzziness", func() {
+			Expect(searcher.SetFuzziness(3)).NotTo(Succeed())
+			Expect(searcher.SetFuzziness(-1)).NotTo(Succeed())
+			Expect(searcher.Fuzziness()).To(Equal(domain.DefaultSearchFuzziness))
+		})
+	})
+
+	It("should return a highlighted snippet around a match deep in the content", func() {
+		content := strings.Repeat("Introductory text about deployments. ", 100) + "Use the flux controller for gitops. " + strings.Repeat("More text. ", 50)
+		Expect(searcher.IndexSkill(domain.Skill{Name: "flux", Content: content})).To(Succeed())
+
+		results, err := searcher.Search("gitops")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Snippet).To(ContainSubstring("<mark>gitops</mark>"))
+		Expect(results[0].Snippet).NotTo(HavePrefix("Introductory"))
+		Expect(results[0].Score).To(BeNumerically(">", 0))
+	})
+
+	It("should rank name and description matches above content mentions", func() {
+		// Mentions docker in two fields, which outranks the single name match unless fields are weighted
+		Expect(searcher.IndexSkill(domain.Skill{
+			Name:    "compose",
+			Content: "Start it with docker compose.",
+			Metadata: &domain.SkillMetadata{
+				Description: "Multi-container applications",
+				Metadata:    map[string]string{"runtime": "docker"},
+			},
+		})).To(Succeed())
+		Expect(searcher.IndexSkill(domain.Skill{
+			Name:     "docker",
+			Content:  strings.Repeat("Build images and run containers. ", 20),
+			Metadata: &domain.SkillMetadata{Description: "Container runtime guide"},
+		})).To(Succeed())
+
+		results, err := searcher.Search("docker")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Name).To(Equal("docker"))
+	})
+
+	It("should order results by score", func() {
+		Expect(searcher.IndexSkill(domain.Skill{Name: "argo", Content: "argo argo argo gitops"})).To(Succeed())
+		Expect(searcher.IndexSkill(domain.Skill{Name: "flux", Content: "flux controller for gitops with a much longer description of everything it does"})).To(Succeed())
+
+		results, err := searcher.Search("argo gitops")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].Name).To(Equal("argo"))
+		Expect(results[0].Score).To(BeNumerically(">=", results[1].Score))
+	})
+
+	Context("with tag filters", func() {
+		names := func(results []domain.Skill) []string {
+			var names []string
+			for _, skill := range results {
+				names = append(names, skill.Name)
+			}
+			return names
+		}
+
+		BeforeEach(func() {
+			tagged := []domain.Skill{
+				{Name: "cilium", Content: "kubernetes networking", Metadata: &domain.SkillMetadata{Tags: domain.StringList{"networking", "Machine-Learning"}}},
+				{Name: "calico", Content: "kubernetes networking", Metadata: &domain.SkillMetadata{Tags: domain.StringList{"networking"}}},
+				{Name: "kubeflow", Content: "kubernetes pipelines", Metadata: &domain.SkillMetadata{Metadata: map[string]string{"tags": "machine-learning, mlops"}}},
+			}
+			Expect(searcher.IndexSkills(append(skills, tagged...))).To(Succeed())
+		})
+
+		It("should only return skills with a single tag", func() {
+			results, err := searcher.SearchWithOptions("kubernetes", domain.SearchOptions{
+				Tags: domain.TagFilter{Tags: []string{"networking"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(results)).To(ConsistOf("cilium", "calico"))
+		})
+
+		It("should match any of several tags by default", func() {
+			results, err := searcher.SearchWithOptions("kubernetes", domain.SearchOptions{
+				Tags: domain.TagFilter{Tags: []string{"networking", "MLOps"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(results)).To(ConsistOf("cilium", "calico", "kubeflow"))
+		})
+
+		It("should match all tags when requested", func() {
+			results, err := searcher.SearchWithOptions("kubernetes", domain.SearchOptions{
+				Tags: domain.TagFilter{Tags: []string{"networking", "machine-learning"}, MatchAll: true},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(results)).To(ConsistOf("cilium"))
+		})
+
+		It("should match whole tags only", func() {
+			results, err := searcher.SearchWithOptions("kubernetes", domain.SearchOptions{
+				Tags: domain.TagFilter{Tags: []string{"machine"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(BeEmpty())
+		})
+
+		It("should filter skills the same way outside the index", func() {
+			all := append(skills, domain.Skill{Name: "calico", Metadata: &domain.SkillMetadata{Tags: domain.StringList{"networking"}}})
+			Expect(names(domain.FilterTags(all, domain.TagFilter{Tags: []string{"Networking"}}))).To(Equal([]string{"calico"}))
+			Expect(domain.FilterTags(all, domain.TagFilter{Tags: []string{"networking", "dns"}, MatchAll: true})).To(BeEmpty())
+			Expect(domain.FilterTags(all, domain.TagFilter{})).To(HaveLen(len(all)))
+		})
+	})
+})
+
+// BenchmarkIndexSkillsUnchanged measures a rebuild that finds nothing to write
+func BenchmarkIndexSkillsUnchanged(b *testing.B) {
+	searcher, err := domain.NewSearcher(filepath.Join(b.TempDir(), "index"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer searcher.Close()
+
+	var skills []domain.Skill
+	for i := 0; i < 300; i++ {
+		skills = append(skills, domain.Skill{
+			Name:       fmt.Sprintf("skill-%d", i),
+			SourcePath: fmt.Sprintf("/skills/skill-%d", i),
+			Content:    "kubernetes deployment guide",
+		})
+	}
+	if err := searcher.IndexSkills(skills); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := searcher.IndexSkills(skills); err != nil {
+			b.Fatal(err)
+		}
+		if changed := searcher.Status().Changed; changed != 0 {
+			b.Fatalf("unchanged rebuild wrote %d documents", changed)
+		}
+	}
+}
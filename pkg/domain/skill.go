@@ -1,36 +1,87 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
+	"html"
 	"regexp"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // SkillMetadata represents YAML frontmatter metadata per Agent Skills specification
 type SkillMetadata struct {
-	Name          string            `yaml:"name"`        // Required, 1-64 chars, lowercase alphanumeric + hyphens
-	Description   string            `yaml:"description"` // Required, 1-1024 chars
+	ID            string            `yaml:"id,omitempty"` // Optional stable identifier (e.g. a UUID), independent of the skill's location
+	Name          string            `yaml:"name"`         // Required, 1-64 chars, lowercase alphanumeric + hyphens
+	Description   string            `yaml:"description"`  // Required, 1-1024 chars
 	License       string            `yaml:"license,omitempty"`
 	Compatibility string            `yaml:"compatibility,omitempty"` // Max 500 chars
+	Version       string            `yaml:"version,omitempty"`       // Free-form, e.g. "1.2.0"; max 64 chars
+	Tags          StringList        `yaml:"tags,omitempty"`          // Max 32 tags of 1-64 chars
 	Metadata      map[string]string `yaml:"metadata,omitempty"`
 	AllowedTools  string            `yaml:"allowed-tools,omitempty"` // Space-delimited
+	Icon          string            `yaml:"icon,omitempty"`          // Presentation hint, e.g. "fa-docker"
+	Color         string            `yaml:"color,omitempty"`         // Presentation hint, hex or CSS color name
+
+	Extra map[string]any `yaml:",inline"` // Author-defined keys not covered above, e.g. "author"
+}
+
+// StringList is a YAML list of strings that also accepts a single comma-separated string,
+// e.g. both "tags: [docker, k8s]" and "tags: docker, k8s"
+type StringList []string
+
+// UnmarshalYAML decodes a sequence or a comma-separated scalar
+func (l *StringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var list StringList
+		for _, item := range strings.Split(value.Value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		*l = list
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
 }
 
 // Skill represents a skill directory with SKILL.md file
 type Skill struct {
-	Name       string // Display name (same as ID for local skills, repoName/skillName for git repo skills)
-	ID         string // Unique identifier to use when reading the skill (repoName/skillName or skillName)
+	Name       string // Display name and location (skillName for local skills, its path such as repoName/category/skillName for git repo skills)
+	ID         string // Unique identifier to use when reading the skill (the frontmatter id if set, otherwise the same as Name)
 	Content    string
 	Metadata   *SkillMetadata
-	SourcePath string // Full path to the skill directory
-	ReadOnly   bool   // True if skill is from a git repository
+	SourcePath string    // Full path to the skill directory
+	ReadOnly   bool      // True if skill is from a git repository
+	Overlaid   bool      // Some of the files of a git repository skill are shadowed by the local overlay
+	Ambiguous  bool      // Another skill has the same directory name or ID (only set by ListSkills); refer to it by Name
+	Created    time.Time // When the skill directory was created, approximated by its inode change time
+	Modified   time.Time // Last modification time of SKILL.md
+
+	ContentLength int // Size of Content in bytes
+	WordCount     int // Number of whitespace-separated words in Content
+	TokenEstimate int // Approximate number of LLM tokens in Content
+
+	Score   float64 // Search relevance, only set in results from the search index
+	Snippet string  // HTML-escaped content around the best match with matched terms in <mark>, only set in results from the search index
 }
 
 var (
 	// Valid skill name pattern: lowercase letters, numbers, hyphens, 1-64 chars, no leading/trailing hyphens, no consecutive hyphens
 	skillNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	// Hex colors (#rgb, #rgba, #rrggbb, #rrggbbaa); CSS color names are checked against cssColorNames
+	skillHexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	// Stable skill IDs such as UUIDs or user-chosen identifiers (no slashes, so they never look like a git repo skill name)
+	skillIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+	// Icon identifiers such as "fa-docker" or "mdi:rocket"
+	skillIconPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_:-]{0,63}$`)
 )
 
 // ValidateSkillName validates a skill name according to Agent Skills specification
@@ -53,44 +104,188 @@ func ValidateSkillName(name string) error {
 	return nil
 }
 
-// ParseFrontmatter extracts YAML frontmatter from markdown content
-// Returns the metadata (if present) and the remaining content
-func ParseFrontmatter(content string) (*SkillMetadata, string, error) {
-	content = strings.TrimSpace(content)
-	if !strings.HasPrefix(content, "---") {
-		return nil, content, fmt.Errorf("frontmatter is required (must start with ---)")
+// ValidateSkillID validates the optional stable skill ID
+func ValidateSkillID(id string) error {
+	if id != "" && !skillIDPattern.MatchString(id) {
+		return fmt.Errorf("id must be 1-128 letters, numbers, '.', '_' or '-', got %q", id)
+	}
+	return nil
+}
+
+// ValidateSkillVersion validates the optional version string
+func ValidateSkillVersion(version string) error {
+	if len(version) > 64 || strings.ContainsAny(version, "\r\n") {
+		return fmt.Errorf("version must be a single line of max 64 characters, got %q", version)
+	}
+	return nil
+}
+
+// ValidateSkillTags validates the optional tags
+func ValidateSkillTags(tags []string) error {
+	if len(tags) > 32 {
+		return fmt.Errorf("a skill may have max 32 tags, got %d", len(tags))
+	}
+	for _, tag := range tags {
+		if tag == "" || len(tag) > 64 || strings.ContainsAny(tag, ",\r\n") {
+			return fmt.Errorf("tags must be 1-64 characters without commas or newlines, got %q", tag)
+		}
+	}
+	return nil
+}
+
+// ParseAllowedTools splits the space-delimited allowed-tools field into tool names
+// Names must not contain commas (the field is not comma-separated); repeated names are dropped from tools
+// and returned in duplicates
+func ParseAllowedTools(allowedTools string) (tools, duplicates []string, err error) {
+	seen := make(map[string]bool)
+	for _, tool := range strings.Fields(allowedTools) {
+		if strings.Trim(tool, ",") == "" || strings.Contains(tool, ",") {
+			return nil, nil, fmt.Errorf("allowed-tools must be a space-delimited list of tool names, got %q", allowedTools)
+		}
+		if seen[tool] {
+			duplicates = append(duplicates, tool)
+			continue
+		}
+		seen[tool] = true
+		tools = append(tools, tool)
+	}
+	return tools, duplicates, nil
+}
+
+// AllowedToolsList returns the tool names of the allowed-tools field, without duplicates
+func (m *SkillMetadata) AllowedToolsList() []string {
+	tools, _, _ := ParseAllowedTools(m.AllowedTools)
+	return tools
+}
+
+// ValidateSkillColor validates the optional color presentation hint
+func ValidateSkillColor(color string) error {
+	if color != "" && !skillHexColorPattern.MatchString(color) && !isCSSColorName(color) {
+		return fmt.Errorf("color must be a hex color (e.g. #1e90ff) or a CSS color name, got %q", color)
+	}
+	return nil
+}
+
+// ValidateSkillIcon validates the optional icon presentation hint
+func ValidateSkillIcon(icon string) error {
+	if icon != "" && !skillIconPattern.MatchString(icon) {
+		return fmt.Errorf("icon must be 1-64 letters, numbers, '-', '_' or ':', got %q", icon)
 	}
+	return nil
+}
+
+// DefaultMaxContentLength is the longest SKILL.md body accepted by default, in bytes
+const DefaultMaxContentLength = 1024 * 1024
 
-	// Find the end of frontmatter
-	endIdx := strings.Index(content[3:], "---")
-	if endIdx == -1 {
-		return nil, content, fmt.Errorf("malformed frontmatter (missing closing ---)")
+// ErrContentTooLarge is returned for a SKILL.md body longer than the maximum content length
+var ErrContentTooLarge = errors.New("skill content too large")
+
+// ValidateContentLength checks that a SKILL.md body is at most maxLength bytes long (0 = no limit)
+func ValidateContentLength(content string, maxLength int) error {
+	if maxLength > 0 && len(content) > maxLength {
+		return fmt.Errorf("%w: %d bytes, the maximum is %d", ErrContentTooLarge, len(content), maxLength)
 	}
+	return nil
+}
 
-	frontmatter := content[3 : endIdx+3]
-	remaining := strings.TrimSpace(content[endIdx+6:])
+// ParseFrontmatter extracts YAML frontmatter from markdown content
+// Returns the metadata (if present) and the remaining content, which must be at most DefaultMaxContentLength long
+func ParseFrontmatter(content string) (*SkillMetadata, string, error) {
+	return ParseFrontmatterWithLimit(content, DefaultMaxContentLength)
+}
+
+// ParseFrontmatterWithLimit is ParseFrontmatter with a maximum content length in bytes (0 = no limit)
+// An invalid icon or color is cleared rather than refused, as it only affects how the skill is displayed
+func ParseFrontmatterWithLimit(content string, maxContentLength int) (*SkillMetadata, string, error) {
+	content = strings.TrimSpace(content)
+	frontmatter, rest, err := splitFrontmatter(content)
+	if err != nil {
+		return nil, content, err
+	}
+	remaining := strings.TrimSpace(rest)
 
 	var metadata SkillMetadata
 	if err := yaml.Unmarshal([]byte(frontmatter), &metadata); err != nil {
 		return nil, content, fmt.Errorf("failed to parse frontmatter: %w", err)
 	}
 
-	// Validate required fields
-	if metadata.Name == "" {
-		return nil, content, fmt.Errorf("frontmatter 'name' field is required")
+	if problems := metadataProblems(&metadata); len(problems) > 0 {
+		return nil, content, problems[0]
 	}
-	if err := ValidateSkillName(metadata.Name); err != nil {
-		return nil, content, fmt.Errorf("invalid skill name: %w", err)
+	clearInvalidPresentation(&metadata)
+	if err := ValidateContentLength(remaining, maxContentLength); err != nil {
+		return nil, content, err
 	}
-	if metadata.Description == "" {
-		return nil, content, fmt.Errorf("frontmatter 'description' field is required")
+
+	return &metadata, remaining, nil
+}
+
+// metadataProblems checks frontmatter metadata against the Agent Skills specification and returns every problem found,
+// the required fields first
+func metadataProblems(metadata *SkillMetadata) []error {
+	var problems []error
+	if metadata.Name == "" {
+		problems = append(problems, fmt.Errorf("frontmatter 'name' field is required"))
+	} else if err := ValidateSkillName(metadata.Name); err != nil {
+		problems = append(problems, fmt.Errorf("invalid skill name: %w", err))
 	}
-	if len(metadata.Description) > 1024 {
-		return nil, content, fmt.Errorf("description must be 1-1024 characters, got %d", len(metadata.Description))
+	if metadata.Description == "" {
+		problems = append(problems, fmt.Errorf("frontmatter 'description' field is required"))
+	} else if len(metadata.Description) > 1024 {
+		problems = append(problems, fmt.Errorf("description must be 1-1024 characters, got %d", len(metadata.Description)))
 	}
 	if metadata.Compatibility != "" && len(metadata.Compatibility) > 500 {
-		return nil, content, fmt.Errorf("compatibility must be max 500 characters, got %d", len(metadata.Compatibility))
+		problems = append(problems, fmt.Errorf("compatibility must be max 500 characters, got %d", len(metadata.Compatibility)))
+	}
+	for _, err := range []error{
+		ValidateSkillID(metadata.ID),
+		ValidateSkillVersion(metadata.Version),
+		ValidateSkillTags(metadata.Tags),
+	} {
+		if err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if _, _, err := ParseAllowedTools(metadata.AllowedTools); err != nil {
+		problems = append(problems, err)
 	}
+	return problems
+}
 
-	return &metadata, remaining, nil
+// clearInvalidPresentation clears an invalid icon or color presentation hint and returns why each was cleared
+func clearInvalidPresentation(metadata *SkillMetadata) []error {
+	var problems []error
+	if err := ValidateSkillIcon(metadata.Icon); err != nil {
+		metadata.Icon = ""
+		problems = append(problems, err)
+	}
+	if err := ValidateSkillColor(metadata.Color); err != nil {
+		metadata.Color = ""
+		problems = append(problems, err)
+	}
+	return problems
+}
+
+// EstimateTokens approximates the number of LLM tokens in content
+// Tokenizers average roughly four bytes per token for English text and code
+func EstimateTokens(content string) int {
+	return (len(content) + 3) / 4
+}
+
+// ContentSnippet returns the first maxRunes characters of content, with an ellipsis if it was truncated
+func ContentSnippet(content string, maxRunes int) string {
+	runes := []rune(content)
+	if len(runes) <= maxRunes {
+		return content
+	}
+	return string(runes[:maxRunes]) + "..."
+}
+
+// SearchSnippet returns the snippet of a search result: the highlighted match if the index found one in the content,
+// otherwise the start of the content; either way HTML-escaped, so matches can be told apart by their <mark> tags
+func SearchSnippet(skill Skill, maxRunes int) string {
+	if skill.Snippet != "" {
+		return skill.Snippet
+	}
+	return html.EscapeString(ContentSnippet(skill.Content, maxRunes))
 }
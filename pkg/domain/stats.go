@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SkillStats summarizes the skills served by a SkillManager
+type SkillStats struct {
+	Skills        int            // Number of skills
+	LocalSkills   int            // Skills in the skills directory itself
+	GitSkills     int            // Skills from git repositories
+	RepoSkills    map[string]int // Number of skills per git repository directory name
+	Resources     int            // Number of resources across all skills
+	ResourceBytes int64          // Total size of the resources, in bytes
+}
+
+// CollectStats counts the skills of manager and sizes up their resources
+func CollectStats(manager SkillManager) (SkillStats, error) {
+	skills, err := manager.ListSkills()
+	if err != nil {
+		return SkillStats{}, fmt.Errorf("failed to list skills: %w", err)
+	}
+
+	stats := SkillStats{
+		Skills:     len(skills),
+		RepoSkills: make(map[string]int),
+	}
+	for _, skill := range skills {
+		if skill.ReadOnly {
+			stats.GitSkills++
+			// Git repository skills are named after their path, starting with the repository directory
+			repo, _, _ := strings.Cut(skill.Name, "/")
+			stats.RepoSkills[repo]++
+		} else {
+			stats.LocalSkills++
+		}
+
+		resources, err := manager.ListSkillResources(skill.ID)
+		if err != nil {
+			return SkillStats{}, fmt.Errorf("failed to list resources of skill %s: %w", skill.Name, err)
+		}
+		stats.Resources += len(resources)
+		for _, resource := range resources {
+			stats.ResourceBytes += resource.Size
+		}
+	}
+	return stats, nil
+}
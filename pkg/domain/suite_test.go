@@ -1,6 +1,8 @@
 package domain_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -11,3 +13,10 @@ func TestDomain(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Domain Suite")
 }
+
+// writeSkill writes a SKILL.md with the given frontmatter lines and body to dir, creating the directory
+func writeSkill(dir, frontmatter, content string) {
+	GinkgoHelper()
+	Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\n"+frontmatter+"---\n"+content), 0644)).To(Succeed())
+}
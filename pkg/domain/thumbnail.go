@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Register GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultThumbnailSize is the default maximum width and height of a thumbnail
+	DefaultThumbnailSize = 128
+	// MaxThumbnailSize is the largest thumbnail size that can be requested
+	MaxThumbnailSize = 1024
+	// MaxThumbnailSourceSize is the largest image file thumbnails are generated for
+	MaxThumbnailSourceSize = 20 * 1024 * 1024
+	// maxThumbnailSourcePixels is the largest decoded image thumbnails are generated for
+	maxThumbnailSourcePixels = 40 * 1000 * 1000
+	// maxThumbnailCacheEntries is the number of thumbnails kept in memory
+	maxThumbnailCacheEntries = 256
+)
+
+var (
+	// ErrNotImage is returned when a thumbnail is requested for a resource that is not a supported image
+	ErrNotImage = errors.New("resource is not a supported image")
+	// ErrImageTooLarge is returned when a thumbnail is requested for an image that is too large to process
+	ErrImageTooLarge = errors.New("image is too large to generate a thumbnail")
+)
+
+// Thumbnail is an encoded thumbnail image
+type Thumbnail struct {
+	Data     []byte
+	MimeType string // "image/png" or "image/jpeg"
+}
+
+// GenerateThumbnail decodes a JPEG, PNG or GIF image and scales it down to fit within size x size
+// Images that already fit are not scaled up. PNG and GIF sources produce PNG thumbnails to keep
+// transparency, everything else produces JPEG
+func GenerateThumbnail(r io.Reader, size int) (*Thumbnail, error) {
+	if size <= 0 {
+		size = DefaultThumbnailSize
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrNotImage
+	}
+	if config.Width*config.Height > maxThumbnailSourcePixels {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	width, height := fitWithin(img.Bounds().Dx(), img.Bounds().Dy(), size)
+	thumb := scaleImage(img, width, height)
+
+	var buf bytes.Buffer
+	if format == "png" || format == "gif" {
+		if err := png.Encode(&buf, thumb); err != nil {
+			return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+		}
+		return &Thumbnail{Data: buf.Bytes(), MimeType: "image/png"}, nil
+	}
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return &Thumbnail{Data: buf.Bytes(), MimeType: "image/jpeg"}, nil
+}
+
+// fitWithin returns the dimensions of a width x height image scaled down to fit within size x size
+func fitWithin(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		return size, max(1, height*size/width)
+	}
+	return max(1, width*size/height), size
+}
+
+// scaleImage resizes src to width x height by averaging the source pixels covered by each target pixel
+func scaleImage(src image.Image, width, height int) *image.NRGBA {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			// Average premultiplied values so transparent pixels don't bleed their color
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
+
+// thumbnailCache keeps generated thumbnails keyed by file path, modification time, file size and thumbnail size
+type thumbnailCache struct {
+	mu      sync.Mutex
+	entries map[string]*Thumbnail
+	order   []string // Insertion order, oldest first, for eviction
+}
+
+// newThumbnailCache creates an empty thumbnail cache
+func newThumbnailCache() *thumbnailCache {
+	return &thumbnailCache{entries: make(map[string]*Thumbnail)}
+}
+
+// get returns the cached thumbnail for key, if any
+func (c *thumbnailCache) get(key string) (*Thumbnail, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	thumb, ok := c.entries[key]
+	return thumb, ok
+}
+
+// put stores a thumbnail, evicting the oldest entry when the cache is full
+func (c *thumbnailCache) put(key string, thumb *Thumbnail) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	if len(c.order) >= maxThumbnailCacheEntries {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[key] = thumb
+	c.order = append(c.order, key)
+}
+
+// GetResourceThumbnail returns a thumbnail of an image resource that fits within size x size
+// Returns ErrNotImage for non-image resources and ErrImageTooLarge for images too large to process
+func (m *FileSystemManager) GetResourceThumbnail(skillID, resourcePath string, size int) (*Thumbnail, error) {
+	info, err := m.GetSkillResourceInfo(skillID, resourcePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.LFSPointer {
+		return nil, ErrLFSPointer
+	}
+	if !strings.HasPrefix(info.MimeType, "image/") {
+		return nil, ErrNotImage
+	}
+	if info.Size > MaxThumbnailSourceSize {
+		return nil, ErrImageTooLarge
+	}
+
+	skillPath, err := m.getSkillPath(skillID)
+	if err != nil {
+		return nil, err
+	}
+	fullPath, err := m.resourceFile(skillPath, resourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s|%d|%d|%d", fullPath, info.Modified.UnixNano(), info.Size, size)
+	if thumb, ok := m.thumbnails.get(key); ok {
+		return thumb, nil
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open resource: %w", err)
+	}
+	defer file.Close()
+
+	thumb, err := GenerateThumbnail(io.LimitReader(file, MaxThumbnailSourceSize), size)
+	if err != nil {
+		return nil, err
+	}
+	m.thumbnails.put(key, thumb)
+	return thumb, nil
+}
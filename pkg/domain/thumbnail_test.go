@@ -0,0 +1,112 @@
+package domain_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+)
+
+var _ = Describe("Thumbnails", func() {
+	var (
+		manager  *domain.FileSystemManager
+		tempDir  string
+		assetDir string
+		err      error
+	)
+
+	writePNG := func(path string, width, height int) {
+		img := image.NewNRGBA(image.Rect(0, 0, width, height))
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				img.Set(x, y, color.NRGBA{R: 255, A: 255})
+			}
+		}
+		var buf bytes.Buffer
+		Expect(png.Encode(&buf, img)).To(Succeed())
+		Expect(os.WriteFile(path, buf.Bytes(), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		tempDir, err = os.MkdirTemp("", "skillserver-thumbnail-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		manager, err = domain.NewFileSystemManager(tempDir, []string{})
+		Expect(err).NotTo(HaveOccurred())
+
+		skillDir := filepath.Join(tempDir, "image-skill")
+		assetDir = filepath.Join(skillDir, "assets")
+		Expect(os.MkdirAll(assetDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: image-skill\ndescription: Images\n---\n"), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should scale images down keeping the aspect ratio", func() {
+		writePNG(filepath.Join(assetDir, "logo.png"), 400, 200)
+
+		thumb, err := manager.GetResourceThumbnail("image-skill", "assets/logo.png", 100)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(thumb.MimeType).To(Equal("image/png"))
+
+		img, _, err := image.Decode(bytes.NewReader(thumb.Data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(img.Bounds().Dx()).To(Equal(100))
+		Expect(img.Bounds().Dy()).To(Equal(50))
+		r, _, _, a := img.At(10, 10).RGBA()
+		Expect(r >> 8).To(BeNumerically("==", 255))
+		Expect(a >> 8).To(BeNumerically("==", 255))
+	})
+
+	It("should not scale small images up", func() {
+		writePNG(filepath.Join(assetDir, "icon.png"), 16, 16)
+
+		thumb, err := manager.GetResourceThumbnail("image-skill", "assets/icon.png", 128)
+		Expect(err).NotTo(HaveOccurred())
+		img, _, err := image.Decode(bytes.NewReader(thumb.Data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(img.Bounds().Dx()).To(Equal(16))
+	})
+
+	It("should regenerate the thumbnail when the image changes", func() {
+		path := filepath.Join(assetDir, "logo.png")
+		writePNG(path, 400, 200)
+		_, err := manager.GetResourceThumbnail("image-skill", "assets/logo.png", 100)
+		Expect(err).NotTo(HaveOccurred())
+
+		writePNG(path, 200, 400)
+		later := time.Now().Add(time.Minute)
+		Expect(os.Chtimes(path, later, later)).To(Succeed())
+
+		thumb, err := manager.GetResourceThumbnail("image-skill", "assets/logo.png", 100)
+		Expect(err).NotTo(HaveOccurred())
+		img, _, err := image.Decode(bytes.NewReader(thumb.Data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(img.Bounds().Dx()).To(Equal(50))
+		Expect(img.Bounds().Dy()).To(Equal(100))
+	})
+
+	It("should reject non-image resources", func() {
+		Expect(os.WriteFile(filepath.Join(assetDir, "data.txt"), []byte("hello"), 0644)).To(Succeed())
+
+		_, err := manager.GetResourceThumbnail("image-skill", "assets/data.txt", 128)
+		Expect(err).To(MatchError(domain.ErrNotImage))
+	})
+
+	It("should reject oversized images", func() {
+		Expect(os.WriteFile(filepath.Join(assetDir, "huge.png"), make([]byte, domain.MaxThumbnailSourceSize+1), 0644)).To(Succeed())
+
+		_, err := manager.GetResourceThumbnail("image-skill", "assets/huge.png", 128)
+		Expect(err).To(MatchError(domain.ErrImageTooLarge))
+	})
+})
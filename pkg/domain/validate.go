@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SkillValidation is the outcome of checking a skill without writing it to disk, e.g. before committing
+// it to a git repository. Unlike ParseFrontmatter, which stops at the first problem, it reports all of them
+type SkillValidation struct {
+	Name     string         // Directory name of the skill, if known
+	Metadata *SkillMetadata // Parsed frontmatter, nil if it cannot be parsed
+	Errors   []string       // Problems that make the skill invalid
+	Warnings []string       // Problems that do not prevent serving the skill
+}
+
+// Valid reports whether the skill has no errors
+func (v *SkillValidation) Valid() bool {
+	return len(v.Errors) == 0
+}
+
+// ValidateSkillMd checks SKILL.md content like ParseFrontmatterWithLimit, collecting every problem
+// dirName is the directory the skill lives in, which must match the frontmatter name (empty = not checked)
+func ValidateSkillMd(content, dirName string, maxContentLength int) *SkillValidation {
+	validation := &SkillValidation{Name: dirName}
+	frontmatter, rest, err := splitFrontmatter(strings.TrimSpace(content))
+	if err != nil {
+		validation.Errors = append(validation.Errors, err.Error())
+		return validation
+	}
+	var metadata SkillMetadata
+	if err := yaml.Unmarshal([]byte(frontmatter), &metadata); err != nil {
+		validation.Errors = append(validation.Errors, fmt.Sprintf("failed to parse frontmatter: %v", err))
+		return validation
+	}
+	validation.Metadata = &metadata
+
+	for _, problem := range metadataProblems(&metadata) {
+		validation.Errors = append(validation.Errors, problem.Error())
+	}
+	if dirName != "" && metadata.Name != "" && metadata.Name != dirName {
+		validation.Errors = append(validation.Errors, fmt.Sprintf("%v: SKILL.md names the skill %q but its directory is %q", ErrNameMismatch, metadata.Name, dirName))
+	}
+	body := strings.TrimSpace(rest)
+	if err := ValidateContentLength(body, maxContentLength); err != nil {
+		validation.Errors = append(validation.Errors, err.Error())
+	}
+
+	if body == "" {
+		validation.Warnings = append(validation.Warnings, "SKILL.md has no instructions after the frontmatter")
+	}
+	for _, problem := range clearInvalidPresentation(&metadata) {
+		validation.Warnings = append(validation.Warnings, fmt.Sprintf("%v; it is ignored", problem))
+	}
+	if _, duplicates, err := ParseAllowedTools(metadata.AllowedTools); err == nil && len(duplicates) > 0 {
+		validation.Warnings = append(validation.Warnings, fmt.Sprintf("allowed-tools lists tools more than once: %s", strings.Join(duplicates, ", ")))
+	}
+	return validation
+}
+
+// ValidateSkillArchive checks a skill archive like ImportSkillWithOptions would, without extracting it
+// The archive's directory must be named after the skill, whatever opts.Name and opts.AutoFix say
+func ValidateSkillArchive(archiveData []byte, opts ImportOptions, maxContentLength int) *SkillValidation {
+	dirName, skillMd, err := scanSkillArchive(archiveData, opts)
+	if err != nil {
+		return &SkillValidation{Errors: []string{err.Error()}}
+	}
+	validation := ValidateSkillMd(string(skillMd), dirName, maxContentLength)
+	// A directory named like the frontmatter has had its name checked already
+	if err := ValidateSkillName(dirName); err != nil && (validation.Metadata == nil || validation.Metadata.Name != dirName) {
+		validation.Errors = append(validation.Errors, fmt.Sprintf("invalid skill name in archive: %v", err))
+	}
+	return validation
+}
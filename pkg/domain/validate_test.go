@@ -0,0 +1,103 @@
+package domain_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+)
+
+var _ = Describe("Skill validation", func() {
+	Context("ValidateSkillMd", func() {
+		It("should return the metadata of valid content", func() {
+			validation := domain.ValidateSkillMd("---\nname: docker\ndescription: Docker\ntags: [containers]\n---\n# Docker\n", "docker", 0)
+			Expect(validation.Valid()).To(BeTrue())
+			Expect(validation.Errors).To(BeEmpty())
+			Expect(validation.Warnings).To(BeEmpty())
+			Expect(validation.Metadata.Name).To(Equal("docker"))
+			Expect(validation.Metadata.Tags).To(Equal(domain.StringList{"containers"}))
+		})
+
+		It("should report a missing name", func() {
+			validation := domain.ValidateSkillMd("---\ndescription: Docker\n---\n# Docker\n", "", 0)
+			Expect(validation.Valid()).To(BeFalse())
+			Expect(validation.Errors).To(Equal([]string{"frontmatter 'name' field is required"}))
+		})
+
+		It("should report a description that is too long", func() {
+			validation := domain.ValidateSkillMd("---\nname: docker\ndescription: "+strings.Repeat("a", 1025)+"\n---\n# Docker\n", "", 0)
+			Expect(validation.Valid()).To(BeFalse())
+			Expect(validation.Errors).To(Equal([]string{"description must be 1-1024 characters, got 1025"}))
+		})
+
+		It("should report a bad name", func() {
+			validation := domain.ValidateSkillMd("---\nname: Docker_Tools\ndescription: Docker\n---\n# Docker\n", "", 0)
+			Expect(validation.Valid()).To(BeFalse())
+			Expect(validation.Errors).To(ConsistOf(ContainSubstring("invalid skill name")))
+		})
+
+		It("should report every problem at once", func() {
+			validation := domain.ValidateSkillMd("---\nname: -docker\ncolor: not a color\n---\n", "docker", 0)
+			Expect(validation.Errors).To(HaveLen(3))
+			Expect(validation.Errors[0]).To(ContainSubstring("invalid skill name"))
+			Expect(validation.Errors[1]).To(Equal("frontmatter 'description' field is required"))
+			Expect(validation.Errors[2]).To(ContainSubstring(`its directory is "docker"`))
+			Expect(validation.Warnings).To(ConsistOf(ContainSubstring("no instructions"), ContainSubstring("color must be")))
+		})
+
+		It("should warn about presentation hints that are ignored", func() {
+			validation := domain.ValidateSkillMd("---\nname: docker\ndescription: Docker\nicon: fa-docker\ncolor: notacolor\n---\n# Docker\n", "", 0)
+			Expect(validation.Valid()).To(BeTrue())
+			Expect(validation.Warnings).To(Equal([]string{`color must be a hex color (e.g. #1e90ff) or a CSS color name, got "notacolor"; it is ignored`}))
+			Expect(validation.Metadata.Icon).To(Equal("fa-docker"))
+			Expect(validation.Metadata.Color).To(BeEmpty())
+		})
+
+		It("should report content that cannot be parsed", func() {
+			validation := domain.ValidateSkillMd("# No frontmatter\n", "", 0)
+			Expect(validation.Valid()).To(BeFalse())
+			Expect(validation.Metadata).To(BeNil())
+			Expect(validation.Errors).To(HaveLen(1))
+		})
+
+		It("should warn about repeated allowed tools", func() {
+			validation := domain.ValidateSkillMd("---\nname: docker\ndescription: Docker\nallowed-tools: Bash Read Bash\n---\n# Docker\n", "", 0)
+			Expect(validation.Valid()).To(BeTrue())
+			Expect(validation.Warnings).To(Equal([]string{"allowed-tools lists tools more than once: Bash"}))
+		})
+	})
+
+	Context("ValidateSkillArchive", func() {
+		archive := func(dir, skillMd string) []byte {
+			var buf bytes.Buffer
+			gzw := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gzw)
+			Expect(tw.WriteHeader(&tar.Header{Name: dir + "/SKILL.md", Mode: 0644, Size: int64(len(skillMd)), Typeflag: tar.TypeReg})).To(Succeed())
+			_, err := tw.Write([]byte(skillMd))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tw.Close()).To(Succeed())
+			Expect(gzw.Close()).To(Succeed())
+			return buf.Bytes()
+		}
+
+		It("should validate the SKILL.md of an archive against its directory", func() {
+			validation := domain.ValidateSkillArchive(archive("docker", "---\nname: docker\ndescription: Docker\n---\n# Docker\n"), domain.DefaultImportOptions, 0)
+			Expect(validation.Valid()).To(BeTrue())
+			Expect(validation.Name).To(Equal("docker"))
+
+			validation = domain.ValidateSkillArchive(archive("docker-v2", "---\nname: docker\ndescription: Docker\n---\n# Docker\n"), domain.DefaultImportOptions, 0)
+			Expect(validation.Valid()).To(BeFalse())
+			Expect(validation.Errors).To(ConsistOf(ContainSubstring(`its directory is "docker-v2"`)))
+		})
+
+		It("should report archives with an unexpected layout", func() {
+			validation := domain.ValidateSkillArchive(archive("wrapper/docker", "---\nname: docker\ndescription: Docker\n---\n"), domain.DefaultImportOptions, 0)
+			Expect(validation.Errors).To(Equal([]string{"archive does not contain SKILL.md file"}))
+		})
+	})
+})
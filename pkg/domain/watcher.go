@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is how long the watcher waits for changes to settle before re-indexing
+const DefaultWatchDebounce = 500 * time.Millisecond
+
+// Watcher re-indexes skills when files under the skills directory change outside the API,
+// e.g. when a SKILL.md is edited directly on disk
+type Watcher struct {
+	manager  *FileSystemManager
+	watcher  *fsnotify.Watcher
+	debounce time.Duration
+	logger   *slog.Logger // Logger for watch messages (nil = disabled)
+	done     chan struct{}
+}
+
+// NewWatcher creates a watcher for the skills directory of manager
+// Changes are batched until none have happened for debounce
+func NewWatcher(manager *FileSystemManager, debounce time.Duration) (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	return &Watcher{
+		manager:  manager,
+		watcher:  watcher,
+		debounce: debounce,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// SetLogger sets the logger for watch messages
+func (w *Watcher) SetLogger(logger *slog.Logger) {
+	w.logger = logger
+}
+
+// Start watches the skills directory and its subdirectories in the background
+func (w *Watcher) Start() error {
+	if err := w.addTree(w.manager.skillsDir); err != nil {
+		w.watcher.Close()
+		return err
+	}
+	go w.run()
+	return nil
+}
+
+// Stop stops watching and waits for a re-index in progress to finish
+func (w *Watcher) Stop() error {
+	err := w.watcher.Close()
+	<-w.done
+	return err
+}
+
+// run re-indexes once events have stopped arriving for the debounce period
+func (w *Watcher) run() {
+	defer close(w.done)
+
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if w.ignored(event.Name) {
+				continue
+			}
+			// New directories are not watched automatically
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := w.addTree(event.Name); err != nil {
+						w.logf(slog.LevelWarn, "failed to watch %s: %v", event.Name, err)
+					}
+				}
+			}
+			timer.Reset(w.debounce)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.logf(slog.LevelWarn, "file watcher error: %v", err)
+		case <-timer.C:
+			w.logf(slog.LevelDebug, "re-indexing skills after a change on disk")
+			if err := w.manager.RebuildIndex(); err != nil {
+				w.logf(slog.LevelError, "failed to re-index skills after a change on disk: %v", err)
+			}
+		}
+	}
+}
+
+// addTree watches dir and every subdirectory that is not ignored
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if w.ignored(path) {
+			return filepath.SkipDir
+		}
+		if err := w.watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// ignored reports whether path is inside the search index or a directory skipped when looking for skills,
+// such as a repository's .git or node_modules
+func (w *Watcher) ignored(path string) bool {
+	relPath, err := filepath.Rel(w.manager.skillsDir, path)
+	if err != nil || relPath == "." {
+		return false
+	}
+	dir := w.manager.skillsDir
+	for _, part := range strings.Split(relPath, string(filepath.Separator)) {
+		dir = filepath.Join(dir, part)
+		if SkipSkillDiscovery(part) || w.manager.searcher.ownsPath(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// logf logs a message at level to the configured logger, if any
+func (w *Watcher) logf(level slog.Level, format string, args ...any) {
+	if w.logger == nil || !w.logger.Enabled(context.Background(), level) {
+		return
+	}
+	w.logger.Log(context.Background(), level, fmt.Sprintf(format, args...))
+}
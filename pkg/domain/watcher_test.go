@@ -0,0 +1,59 @@
+package domain_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+)
+
+var _ = Describe("Watcher", func() {
+	var (
+		tempDir string
+		manager *domain.FileSystemManager
+		watcher *domain.Watcher
+		err     error
+	)
+
+	BeforeEach(func() {
+		tempDir, err = os.MkdirTemp("", "skillserver-watcher-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		manager, err = domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		watcher, err = domain.NewWatcher(manager, 50*time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watcher.Start()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(watcher.Stop()).To(Succeed())
+		os.RemoveAll(tempDir)
+	})
+
+	It("should index a skill written directly to disk", func() {
+		skillDir := filepath.Join(tempDir, "helm")
+		Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: helm\ndescription: Helm guide\n---\n# Helm\n\nHelm is a package manager."), 0644)).To(Succeed())
+
+		Eventually(func() domain.SearchStatusInfo {
+			return manager.SearchStatus()
+		}, 5*time.Second, 20*time.Millisecond).Should(And(
+			HaveField("Status", domain.SearchStatusOK),
+			HaveField("Documents", 1),
+		))
+		results, err := manager.SearchSkills("package")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results).To(HaveLen(1))
+
+		skills, err := manager.ListSkills()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(skills).To(HaveLen(1))
+		Expect(skills[0].Name).To(Equal("helm"))
+	})
+})
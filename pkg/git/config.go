@@ -1,8 +1,11 @@
 package git
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,10 +13,14 @@ import (
 
 // GitRepoConfig represents a git repository configuration
 type GitRepoConfig struct {
-	ID      string `json:"id"`
-	URL     string `json:"url"`
-	Name    string `json:"name"`
-	Enabled bool   `json:"enabled"`
+	ID       string `json:"id"`
+	URL      string `json:"url"`
+	Name     string `json:"name"`
+	Enabled  bool   `json:"enabled"`
+	Ref      string `json:"ref,omitempty"`      // Optional tag or commit SHA to pin the repository to
+	SSHKey   string `json:"sshKey,omitempty"`   // Optional private key path for SSH remotes, overriding the global key
+	Username string `json:"username,omitempty"` // Optional username for HTTP(S) remotes, used with Token
+	Token    string `json:"token,omitempty"`    // Optional password or access token for HTTP(S) remotes, overriding the global one
 }
 
 // ConfigManager manages git repository configurations
@@ -72,6 +79,88 @@ func (cm *ConfigManager) SaveConfig(repos []GitRepoConfig) error {
 	return nil
 }
 
+// ValidateRepoURL performs a basic well-formedness check on a git repository URL
+func ValidateRepoURL(repoURL string) error {
+	if strings.TrimSpace(repoURL) == "" {
+		return fmt.Errorf("repository URL is empty")
+	}
+	if strings.ContainsAny(repoURL, " \t\n") {
+		return fmt.Errorf("repository URL %q contains whitespace", repoURL)
+	}
+	for _, prefix := range []string{"http://", "https://", "git@", "ssh://", "file://"} {
+		if strings.HasPrefix(repoURL, prefix) {
+			if ExtractRepoName(repoURL) == "" {
+				return fmt.Errorf("cannot determine repository name from URL %q", repoURL)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid repository URL %q (expected http://, https://, ssh://, file:// or git@)", repoURL)
+}
+
+// NormalizeRepoURL returns the canonical form of a repository URL, so variants of the same URL are stored once:
+// surrounding whitespace and trailing slashes are removed, and the scheme and host are lowercased.
+// The path is kept as is, including a .git suffix, since servers may need it
+func NormalizeRepoURL(repoURL string) string {
+	normalized := strings.TrimRight(strings.TrimSpace(repoURL), "/")
+	if scheme, rest, ok := strings.Cut(normalized, "://"); ok {
+		// user@host:port/path; the user info is kept as is
+		authority, path, _ := strings.Cut(rest, "/")
+		userInfo, host, hasUser := strings.Cut(authority, "@")
+		if !hasUser {
+			userInfo, host = "", authority
+		}
+		authority = strings.ToLower(host)
+		if hasUser {
+			authority = userInfo + "@" + authority
+		}
+		normalized = strings.ToLower(scheme) + "://" + authority
+		if path != "" {
+			normalized += "/" + path
+		}
+	} else if userHost, path, ok := strings.Cut(normalized, ":"); ok && strings.Contains(userHost, "@") && !strings.Contains(userHost, "/") {
+		// scp-like SSH URL: git@host:path
+		user, host, _ := strings.Cut(userHost, "@")
+		normalized = user + "@" + strings.ToLower(host) + ":" + path
+	}
+	return normalized
+}
+
+// SameRepoURL reports whether two repository URLs point to the same repository, e.g.
+// "https://github.com/user/repo" and "https://GitHub.com/user/repo.git/"
+func SameRepoURL(a, b string) bool {
+	return repoURLKey(a) == repoURLKey(b)
+}
+
+// repoURLKey returns the form of a repository URL compared by SameRepoURL
+func repoURLKey(repoURL string) string {
+	return strings.TrimSuffix(NormalizeRepoURL(repoURL), ".git")
+}
+
+// SameRepoLocation reports whether two URLs name the same repository by host and path, in any of the
+// forms git hosts use (HTTP(S) clone, SSH or web page URL). Unlike SameRepoURL it ignores the scheme,
+// user info, port and case, e.g. "git@github.com:org/repo.git" and "https://github.com/Org/Repo", so
+// webhooks can find the configured repository an event is for
+func SameRepoLocation(a, b string) bool {
+	return RepoLocation(a) == RepoLocation(b)
+}
+
+// RepoLocation reduces a repository URL to the host and path compared by SameRepoLocation,
+// such as "github.com/org/repo"
+func RepoLocation(repoURL string) string {
+	location := repoURLKey(repoURL)
+	if u, err := url.Parse(location); err == nil && u.Scheme != "" && u.Host != "" {
+		location = u.Hostname() + u.Path
+	} else if userHost, path, ok := strings.Cut(location, ":"); ok && strings.Contains(userHost, "@") && !strings.Contains(userHost, "/") {
+		// scp-like SSH URL: git@host:path
+		_, host, _ := strings.Cut(userHost, "@")
+		location = host + "/" + path
+	} else {
+		location = strings.TrimPrefix(location, "file://")
+	}
+	return strings.ToLower(location)
+}
+
 // ExtractRepoName extracts a repository name from a URL
 func ExtractRepoName(repoURL string) string {
 	// Remove protocol and .git suffix
@@ -97,6 +186,58 @@ func ExtractRepoName(repoURL string) string {
 	return name
 }
 
+// DirName returns the directory the repository is cloned into, below the skills directory
+func (c GitRepoConfig) DirName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return ExtractRepoName(c.URL)
+}
+
+// RepoDirName returns the directory a repository is cloned into, below the skills directory, given the
+// other repositories in repos: its name, unless another repository already uses it (e.g. github.com/a/tools
+// and gitlab.com/b/tools), in which case a short hash of its URL is appended, as in tools-1a2b3c4d
+// A repository already in repos keeps its directory
+func RepoDirName(repoURL string, repos []GitRepoConfig) string {
+	for _, repo := range repos {
+		if SameRepoURL(repo.URL, repoURL) {
+			return repo.DirName()
+		}
+	}
+	name := ExtractRepoName(repoURL)
+	for _, repo := range repos {
+		if repo.DirName() == name {
+			hash := sha256.Sum256([]byte(repoURLKey(repoURL)))
+			return name + "-" + hex.EncodeToString(hash[:4])
+		}
+	}
+	return name
+}
+
+// NewRepoConfig returns the configuration of a repository added to repos, enabled, with an ID and
+// directory name that no other repository in repos uses
+func NewRepoConfig(repoURL string, repos []GitRepoConfig) GitRepoConfig {
+	name := RepoDirName(repoURL, repos)
+	return GitRepoConfig{
+		ID:      strings.ToLower(strings.ReplaceAll(name, "-", "")),
+		URL:     repoURL,
+		Name:    name,
+		Enabled: true,
+	}
+}
+
+// EnabledRepoDirNames returns the directory names of the enabled repositories in repos,
+// which the skill manager treats as read-only git repository directories
+func EnabledRepoDirNames(repos []GitRepoConfig) []string {
+	var names []string
+	for _, repo := range repos {
+		if name := repo.DirName(); repo.Enabled && name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // GenerateID generates a unique ID for a git repo config
 func GenerateID(repoURL string) string {
 	// Use a simple hash-like approach: take first 8 chars of URL hash
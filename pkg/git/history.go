@@ -0,0 +1,88 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// ErrShallowClone is returned by history-dependent operations on a shallow clone
+var ErrShallowClone = errors.New("unavailable: shallow clone")
+
+// IsShallow reports whether the repository at repoDir is a shallow clone
+func IsShallow(repoDir string) (bool, error) {
+	r, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to open repository: %w", err)
+	}
+	return isShallow(r)
+}
+
+// isShallow reports whether an opened repository has shallow commits
+func isShallow(r *git.Repository) (bool, error) {
+	shallow, err := r.Storer.Shallow()
+	if err != nil {
+		return false, fmt.Errorf("failed to read shallow commits: %w", err)
+	}
+	return len(shallow) > 0, nil
+}
+
+// CommitInfo describes a single commit in a path's history
+type CommitInfo struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	Date    time.Time `json:"date"`
+	Message string    `json:"message"`
+}
+
+// PathHistory returns up to limit most recent commits touching path (relative to the repository root)
+// Returns ErrShallowClone if the repository is a shallow clone, as its history is incomplete
+func PathHistory(repoDir, path string, limit int) ([]CommitInfo, error) {
+	r, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	if shallow, err := isShallow(r); err != nil {
+		return nil, err
+	} else if shallow {
+		return nil, ErrShallowClone
+	}
+
+	prefix := filepath.ToSlash(filepath.Clean(path))
+	iter, err := r.Log(&git.LogOptions{
+		PathFilter: func(p string) bool {
+			return prefix == "." || p == prefix || strings.HasPrefix(p, prefix+"/")
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %w", err)
+	}
+	defer iter.Close()
+
+	history := []CommitInfo{}
+	err = iter.ForEach(func(c *object.Commit) error {
+		if limit > 0 && len(history) >= limit {
+			return storer.ErrStop
+		}
+		history = append(history, CommitInfo{
+			Hash:    c.Hash.String(),
+			Author:  c.Author.Name,
+			Email:   c.Author.Email,
+			Date:    c.Author.When,
+			Message: strings.TrimSpace(c.Message),
+		})
+		return nil
+	})
+	if err != nil && !errors.Is(err, storer.ErrStop) {
+		return nil, fmt.Errorf("failed to walk log: %w", err)
+	}
+
+	return history, nil
+}
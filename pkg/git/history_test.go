@@ -0,0 +1,73 @@
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+
+	gogit "github.com/go-git/go-git/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/git"
+)
+
+var _ = Describe("PathHistory", func() {
+	var (
+		repoDir string
+		repo    *gogit.Repository
+		err     error
+	)
+
+	BeforeEach(func() {
+		repoDir, err = os.MkdirTemp("", "skillserver-history-test")
+		Expect(err).NotTo(HaveOccurred())
+		repo, err = gogit.PlainInit(repoDir, false)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(repoDir)
+	})
+
+	It("should only return commits touching the path, newest first", func() {
+		commitFile(repo, repoDir, "skill-a/SKILL.md", "a1", "add skill-a")
+		commitFile(repo, repoDir, "skill-b/SKILL.md", "b1", "add skill-b")
+		commitFile(repo, repoDir, "skill-a/SKILL.md", "a2", "update skill-a")
+
+		history, err := git.PathHistory(repoDir, "skill-a", 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(HaveLen(2))
+		Expect(history[0].Message).To(Equal("update skill-a"))
+		Expect(history[1].Message).To(Equal("add skill-a"))
+		Expect(history[0].Author).To(Equal("test"))
+	})
+
+	It("should honour the limit", func() {
+		commitFile(repo, repoDir, filepath.Join("skill-a", "SKILL.md"), "a1", "first")
+		commitFile(repo, repoDir, filepath.Join("skill-a", "SKILL.md"), "a2", "second")
+
+		history, err := git.PathHistory(repoDir, "skill-a", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(history).To(HaveLen(1))
+		Expect(history[0].Message).To(Equal("second"))
+	})
+
+	It("should report shallow clones as unavailable", func() {
+		commitFile(repo, repoDir, "skill-a/SKILL.md", "a1", "add skill-a")
+		shallow, err := git.IsShallow(repoDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shallow).To(BeFalse())
+
+		head, err := repo.Head()
+		Expect(err).NotTo(HaveOccurred())
+		err = os.WriteFile(filepath.Join(repoDir, ".git", "shallow"), []byte(head.Hash().String()+"\n"), 0644)
+		Expect(err).NotTo(HaveOccurred())
+
+		shallow, err = git.IsShallow(repoDir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(shallow).To(BeTrue())
+
+		_, err = git.PathHistory(repoDir, "skill-a", 0)
+		Expect(err).To(MatchError(git.ErrShallowClone))
+	})
+})
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// SetProxy sets a proxy URL used for all HTTP(S) git operations
+// It takes precedence over HTTPS_PROXY/HTTP_PROXY/NO_PROXY; an empty URL falls back to the environment
+func (g *GitSyncer) SetProxy(proxyURL string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.proxyURL = proxyURL
+}
+
+// proxyFor returns the proxy to use when talking to repoURL, if any
+func (g *GitSyncer) proxyFor(repoURL string) transport.ProxyOptions {
+	// Proxies only apply to HTTP(S) remotes
+	if !strings.HasPrefix(repoURL, "http://") && !strings.HasPrefix(repoURL, "https://") {
+		return transport.ProxyOptions{}
+	}
+
+	g.mu.RLock()
+	override := g.proxyURL
+	g.mu.RUnlock()
+	if override != "" {
+		return transport.ProxyOptions{URL: override}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, repoURL, nil)
+	if err != nil {
+		return transport.ProxyOptions{}
+	}
+	proxyURL, err := http.ProxyFromEnvironment(req)
+	if err != nil || proxyURL == nil {
+		return transport.ProxyOptions{}
+	}
+	return transport.ProxyOptions{URL: proxyURL.String()}
+}
+
+// wrapProxyError makes failures to reach or authenticate against the proxy explicit
+func wrapProxyError(err error, proxy transport.ProxyOptions) error {
+	if err == nil || proxy.URL == "" {
+		return err
+	}
+
+	var urlErr *url.Error
+	isProxyErr := errors.As(err, &urlErr) && urlErr.Op == "proxyconnect"
+	if !isProxyErr {
+		msg := err.Error()
+		isProxyErr = strings.Contains(msg, "proxyconnect") || strings.Contains(msg, "Proxy Authentication Required")
+	}
+	if !isProxyErr {
+		return err
+	}
+
+	// Never leak proxy credentials into errors
+	redacted := proxy.URL
+	if u, parseErr := url.Parse(proxy.URL); parseErr == nil {
+		redacted = u.Redacted()
+	}
+	return fmt.Errorf("proxy error (via %s): %w", redacted, err)
+}
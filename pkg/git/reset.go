@@ -0,0 +1,97 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ErrLocalChanges is returned when a pull is refused because the checkout has local changes
+// or commits that are not upstream
+var ErrLocalChanges = errors.New("local changes present")
+
+// maxReportedChanges is how many changed files the local changes error names
+const maxReportedChanges = 5
+
+// ResetPolicy is what a pull does with a checkout that has local changes or diverged from upstream
+type ResetPolicy string
+
+const (
+	// ResetNone refuses to pull and reports the local changes (the default)
+	ResetNone ResetPolicy = ""
+	// ResetHard discards the local changes and commits, resetting the checkout to the remote head
+	ResetHard ResetPolicy = "hard"
+)
+
+// ParseResetPolicy parses a reset policy: "hard", or empty for none
+func ParseResetPolicy(policy string) (ResetPolicy, error) {
+	switch ResetPolicy(strings.ToLower(strings.TrimSpace(policy))) {
+	case ResetNone:
+		return ResetNone, nil
+	case ResetHard:
+		return ResetHard, nil
+	default:
+		return ResetNone, fmt.Errorf("invalid git reset policy %q (expected \"hard\" or empty)", policy)
+	}
+}
+
+// SetResetPolicy sets what a pull does with a checkout that has local changes or diverged from upstream
+func (g *GitSyncer) SetResetPolicy(policy ResetPolicy) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.resetPolicy = policy
+}
+
+// getResetPolicy returns the configured reset policy
+func (g *GitSyncer) getResetPolicy() ResetPolicy {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.resetPolicy
+}
+
+// localChanges describes the changes that pulling upstream into a checkout would lose, if any:
+// uncommitted changes to the worktree, or local commits that upstream does not contain
+func localChanges(r *git.Repository, head, upstream plumbing.Hash) (string, error) {
+	w, err := r.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %w", err)
+	}
+	status, err := w.Status()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree status: %w", err)
+	}
+	if !status.IsClean() {
+		var files []string
+		for file, s := range status {
+			if s.Worktree != git.Unmodified || s.Staging != git.Unmodified {
+				files = append(files, file)
+			}
+		}
+		sort.Strings(files)
+		if len(files) > maxReportedChanges {
+			files = append(files[:maxReportedChanges], fmt.Sprintf("%d more", len(files)-maxReportedChanges))
+		}
+		return fmt.Sprintf("uncommitted changes to %s", strings.Join(files, ", ")), nil
+	}
+
+	headCommit, err := r.CommitObject(head)
+	if err != nil {
+		return "", fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+	upstreamCommit, err := r.CommitObject(upstream)
+	if err != nil {
+		return "", fmt.Errorf("failed to read upstream commit: %w", err)
+	}
+	fastForward, err := headCommit.IsAncestor(upstreamCommit)
+	if err != nil {
+		return "", fmt.Errorf("failed to compare with upstream: %w", err)
+	}
+	if !fastForward {
+		return "local commits are not upstream (non-fast-forward)", nil
+	}
+	return "", nil
+}
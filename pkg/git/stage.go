@@ -0,0 +1,149 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// Syncs never change a live checkout in place: the new content is prepared in a hidden staging
+// directory next to it, which then replaces the checkout, so readers (and the search index) never
+// see a half-updated repository and a failed sync leaves the previous content untouched.
+// Hidden directories are not scanned for skills, so staging directories never show up as skills.
+
+// stagingPattern returns the glob pattern of the staging directories of a checkout
+func stagingPattern(repoDir string) string {
+	return filepath.Join(filepath.Dir(repoDir), "."+filepath.Base(repoDir)+".sync-*")
+}
+
+// newStagingDir creates an empty staging directory for a checkout
+func newStagingDir(repoDir string) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(repoDir), 0755); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(filepath.Dir(repoDir), "."+filepath.Base(repoDir)+".sync-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	return dir, nil
+}
+
+// removeStagingDirs removes staging directories left behind by an interrupted sync of a checkout
+func removeStagingDirs(repoDir string) {
+	dirs, _ := filepath.Glob(stagingPattern(repoDir))
+	for _, dir := range dirs {
+		os.RemoveAll(dir)
+	}
+}
+
+// cloneStaged clones a repository into a staging directory, prepares it with prepare (e.g. checking
+// out a pinned ref) and only then moves it to targetDir
+func (g *GitSyncer) cloneStaged(ctx context.Context, repoURL, targetDir string, prepare func(stageDir string) error) error {
+	stageDir, err := newStagingDir(targetDir)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stageDir)
+
+	if err := g.cloneRepo(ctx, repoURL, stageDir); err != nil {
+		return err
+	}
+	if prepare != nil {
+		if err := prepare(stageDir); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(stageDir, targetDir); err != nil {
+		return fmt.Errorf("failed to move clone into place: %w", err)
+	}
+	return nil
+}
+
+// replaceWorktree updates a checkout by copying its repository to a staging directory, checking out
+// the new content there with update and swapping the staging directory in
+// Local changes to the live checkout are discarded, as the staging worktree is checked out from scratch
+func replaceWorktree(repoDir string, update func(stage *git.Repository) error) error {
+	stageDir, err := newStagingDir(repoDir)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stageDir)
+
+	if err := copyDir(filepath.Join(repoDir, git.GitDirName), filepath.Join(stageDir, git.GitDirName)); err != nil {
+		return fmt.Errorf("failed to copy repository: %w", err)
+	}
+	stage, err := git.PlainOpen(stageDir)
+	if err != nil {
+		return fmt.Errorf("failed to open staged repository: %w", err)
+	}
+	if err := update(stage); err != nil {
+		return err
+	}
+
+	// Move the live checkout aside, so it can be restored if the staged one cannot be moved in
+	oldDir := stageDir + ".old"
+	if err := os.Rename(repoDir, oldDir); err != nil {
+		return fmt.Errorf("failed to replace checkout: %w", err)
+	}
+	if err := os.Rename(stageDir, repoDir); err != nil {
+		os.Rename(oldDir, repoDir)
+		return fmt.Errorf("failed to replace checkout: %w", err)
+	}
+	os.RemoveAll(oldDir)
+	return nil
+}
+
+// copyDir recursively copies a directory, preserving file modes and symlinks
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, relPath)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			return copyFile(path, target, info.Mode().Perm())
+		}
+	})
+}
+
+// copyFile copies a regular file
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -2,8 +2,10 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,7 +13,19 @@ import (
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport"
+
+	"github.com/mudler/skillserver/pkg/domain"
+)
+
+const (
+	// SyncInterval is how often repositories are synchronized in the background
+	SyncInterval = 5 * time.Minute
+	// DefaultSyncTimeout is how long syncing a single repository may take by default
+	DefaultSyncTimeout = 2 * time.Minute
+	// DefaultSyncConcurrency is how many repositories are synced at a time by default
+	DefaultSyncConcurrency = 4
 )
 
 // GitSyncer handles synchronization with Git repositories
@@ -23,7 +37,26 @@ type GitSyncer struct {
 	cancel    context.CancelFunc
 	onUpdate  func() error // Callback to trigger re-indexing
 	progress  io.Writer    // Writer for git progress output (nil = disabled)
-	logger    io.Writer    // Writer for log messages (nil = disabled)
+	logger    *slog.Logger // Logger for sync messages (nil = disabled)
+
+	repoConfigs map[string]GitRepoConfig // Per-repository settings keyed by URL
+	proxyURL    string                   // Explicit proxy for HTTP(S) remotes (empty = use environment)
+	sshOptions  SSHOptions               // Key and host key verification for SSH remotes
+
+	httpCredentials HTTPCredentials // Credentials for HTTP(S) remotes
+	lastSync        time.Time       // Completion time of the last sync of all repositories
+
+	status      map[string]RepoStatus // Outcome of the last sync of each repository, keyed by URL
+	resetPolicy ResetPolicy           // What a pull does with local changes
+	syncTimeout time.Duration         // Longest a single repository sync may take (0 = unlimited)
+	concurrency int                   // Repositories synced at a time by SyncAll
+}
+
+// RepoStatus is the outcome of the last sync of a repository
+type RepoStatus struct {
+	LastSynced  time.Time // When the repository was last synced, successfully or not
+	LastSuccess time.Time // When the repository was last synced successfully
+	LastError   string    // Error of the last sync, with credentials masked (empty if it succeeded)
 }
 
 // NewGitSyncer creates a new GitSyncer
@@ -35,8 +68,12 @@ func NewGitSyncer(skillsDir string, repos []string, onUpdate func() error) *GitS
 		ctx:       ctx,
 		cancel:    cancel,
 		onUpdate:  onUpdate,
+		status:    make(map[string]RepoStatus),
 		progress:  nil, // Default to no progress output (to avoid interfering with MCP stdio)
 		logger:    nil, // Default to no logging
+
+		syncTimeout: DefaultSyncTimeout,
+		concurrency: DefaultSyncConcurrency,
 	}
 }
 
@@ -45,9 +82,60 @@ func (g *GitSyncer) SetProgressWriter(w io.Writer) {
 	g.progress = w
 }
 
-// SetLogger sets the writer for log messages
-func (g *GitSyncer) SetLogger(w io.Writer) {
-	g.logger = w
+// SetLogger sets the logger for sync messages
+func (g *GitSyncer) SetLogger(logger *slog.Logger) {
+	g.logger = logger
+}
+
+// logf logs a message at level to the configured logger, if any, with credentials masked
+func (g *GitSyncer) logf(level slog.Level, format string, args ...any) {
+	if g.logger == nil || !g.logger.Enabled(context.Background(), level) {
+		return
+	}
+	g.logger.Log(context.Background(), level, g.redact(fmt.Sprintf(format, args...)))
+}
+
+// redact masks URL credentials and every configured token in a message
+func (g *GitSyncer) redact(message string) string {
+	message = RedactCredentials(message)
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	tokens := []string{g.httpCredentials.Token}
+	for _, cfg := range g.repoConfigs {
+		tokens = append(tokens, cfg.Token)
+	}
+	for _, token := range tokens {
+		if token != "" {
+			message = strings.ReplaceAll(message, token, "xxxxx")
+		}
+	}
+	return message
+}
+
+// SetRepoConfigs sets the per-repository settings (such as a pinned ref or whether it is enabled), keyed by repository URL
+// Repositories without settings are synced as enabled
+func (g *GitSyncer) SetRepoConfigs(configs []GitRepoConfig) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.repoConfigs = make(map[string]GitRepoConfig, len(configs))
+	for _, cfg := range configs {
+		g.repoConfigs[cfg.URL] = cfg
+	}
+}
+
+// getRepoConfig returns the per-repository settings for a URL, if any
+func (g *GitSyncer) getRepoConfig(repoURL string) GitRepoConfig {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.repoConfigs[repoURL]
+}
+
+// isEnabled reports whether a repository should be synced
+func (g *GitSyncer) isEnabled(repoURL string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	cfg, ok := g.repoConfigs[repoURL]
+	return !ok || cfg.Enabled
 }
 
 // Start begins the Git synchronization process
@@ -63,11 +151,47 @@ func (g *GitSyncer) Start() error {
 	return nil
 }
 
-// Stop stops the Git synchronization
+// Stop stops the Git synchronization, cancelling any clone, pull or fetch in progress
 func (g *GitSyncer) Stop() {
 	g.cancel()
 }
 
+// LastSync returns when all repositories were last synced, or the zero time if the initial sync has not completed
+// Repositories that failed to sync do not hold it back
+func (g *GitSyncer) LastSync() time.Time {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.lastSync
+}
+
+// GetRepoStatus returns the outcome of the last sync of each repository that has been synced, keyed by URL
+func (g *GitSyncer) GetRepoStatus() map[string]RepoStatus {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	status := make(map[string]RepoStatus, len(g.status))
+	for repoURL, s := range g.status {
+		status[repoURL] = s
+	}
+	return status
+}
+
+// recordSync records the outcome of a sync of a repository
+func (g *GitSyncer) recordSync(repoURL string, err error) {
+	var message string
+	if err != nil {
+		message = g.redact(err.Error())
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s := g.status[repoURL]
+	s.LastSynced = time.Now()
+	s.LastError = message
+	if err == nil {
+		s.LastSuccess = s.LastSynced
+	}
+	g.status[repoURL] = s
+}
+
 // GetRepos returns a copy of the current repository list
 func (g *GitSyncer) GetRepos() []string {
 	g.mu.RLock()
@@ -78,11 +202,13 @@ func (g *GitSyncer) GetRepos() []string {
 }
 
 // AddRepo adds a new repository and syncs it
+// The URL is stored normalized (see NormalizeRepoURL), and variants of a configured URL are rejected as duplicates
 func (g *GitSyncer) AddRepo(repoURL string) error {
+	repoURL = NormalizeRepoURL(repoURL)
 	g.mu.Lock()
-	// Check if repo already exists
+	// Check if repo already exists, under any variant of its URL
 	for _, existing := range g.repos {
-		if existing == repoURL {
+		if SameRepoURL(existing, repoURL) {
 			g.mu.Unlock()
 			return fmt.Errorf("repository already exists: %s", repoURL)
 		}
@@ -91,7 +217,7 @@ func (g *GitSyncer) AddRepo(repoURL string) error {
 	g.mu.Unlock()
 
 	// Sync the new repo
-	if err := g.syncRepo(repoURL); err != nil {
+	if err := g.syncRepo(g.ctx, repoURL); err != nil {
 		// Remove from list if sync failed
 		g.mu.Lock()
 		for i, r := range g.repos {
@@ -100,6 +226,7 @@ func (g *GitSyncer) AddRepo(repoURL string) error {
 				break
 			}
 		}
+		delete(g.status, repoURL)
 		g.mu.Unlock()
 		return fmt.Errorf("failed to sync new repository: %w", err)
 	}
@@ -131,6 +258,7 @@ func (g *GitSyncer) RemoveRepo(repoURL string) error {
 	if !found {
 		return fmt.Errorf("repository not found: %s", repoURL)
 	}
+	delete(g.status, repoURL)
 
 	return nil
 }
@@ -160,53 +288,242 @@ func (g *GitSyncer) UpdateRepos(repos []string) error {
 	return nil
 }
 
+// SyncResult is the outcome of syncing a repository
+type SyncResult struct {
+	URL   string
+	Error string // Error of the sync, with credentials masked (empty if it succeeded)
+}
+
 // syncAll syncs all configured repositories
 func (g *GitSyncer) syncAll() error {
-	repos := g.GetRepos()
-	for _, repoURL := range repos {
-		if err := g.syncRepo(repoURL); err != nil {
-			// Log error but continue with other repos (only if logger is set)
-			if g.logger != nil {
-				fmt.Fprintf(g.logger, "Warning: failed to sync repo %s: %v\n", repoURL, err)
+	_, err := g.SyncAll(g.ctx)
+	return err
+}
+
+// SyncAll syncs all enabled repositories now, outside the periodic schedule, and re-indexes the skills once
+// Up to the sync concurrency repositories are synced at a time, and results are reported in repository order.
+// Once ctx is done, the syncs in progress are cancelled and the remaining repositories are reported as failed
+// without being synced; an error is returned only if the syncer is stopped or re-indexing fails
+func (g *GitSyncer) SyncAll(ctx context.Context) ([]SyncResult, error) {
+	// Stopping the syncer also cancels syncs started with another context
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(g.ctx, cancel)
+	defer stop()
+
+	// Disabled repositories are neither cloned nor pulled; their checkout is kept for when they are enabled again
+	repos := []string{}
+	for _, repoURL := range g.GetRepos() {
+		if g.isEnabled(repoURL) {
+			repos = append(repos, repoURL)
+		}
+	}
+
+	results := make([]SyncResult, len(repos))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(g.SyncConcurrency(), len(repos)) {
+		wg.Go(func() {
+			for i := range next {
+				results[i] = g.syncResult(ctx, repos[i])
 			}
+		})
+	}
+	started := 0
+	for ; started < len(repos); started++ {
+		// Don't start new syncs once the syncer is stopped
+		if g.ctx.Err() != nil {
+			break
 		}
+		next <- started
+	}
+	close(next)
+	wg.Wait()
+	if err := g.ctx.Err(); err != nil {
+		return results[:started], err
 	}
 
+	g.mu.Lock()
+	g.lastSync = time.Now()
+	g.mu.Unlock()
+
 	// Trigger re-indexing if callback is set
 	if g.onUpdate != nil {
 		if err := g.onUpdate(); err != nil {
-			return fmt.Errorf("failed to trigger re-indexing: %w", err)
+			return results, fmt.Errorf("failed to trigger re-indexing: %w", err)
 		}
 	}
 
-	return nil
+	return results, nil
+}
+
+// SetSyncTimeout sets how long syncing a single repository may take before it is cancelled, so an
+// unreachable host cannot hold up the other repositories (0 = unlimited)
+func (g *GitSyncer) SetSyncTimeout(timeout time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.syncTimeout = timeout
+}
+
+// SyncTimeout returns how long syncing a single repository may take (0 = unlimited)
+func (g *GitSyncer) SyncTimeout() time.Duration {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.syncTimeout
+}
+
+// syncResult syncs a repository for SyncAll and reports the outcome
+func (g *GitSyncer) syncResult(ctx context.Context, repoURL string) SyncResult {
+	result := SyncResult{URL: repoURL}
+	if err := ctx.Err(); err != nil {
+		result.Error = fmt.Sprintf("not synced: %v", err)
+	} else if err := g.syncRepo(ctx, repoURL); err != nil {
+		// Log error but continue with other repos
+		g.logf(slog.LevelWarn, "failed to sync repo %s: %v", repoURL, err)
+		result.Error = g.redact(err.Error())
+	} else {
+		g.logf(slog.LevelInfo, "synced repo %s", repoURL)
+	}
+	return result
+}
+
+// SetSyncConcurrency sets how many repositories SyncAll syncs at a time (at least 1)
+func (g *GitSyncer) SetSyncConcurrency(concurrency int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.concurrency = max(concurrency, 1)
+}
+
+// SyncConcurrency returns how many repositories SyncAll syncs at a time
+func (g *GitSyncer) SyncConcurrency() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.concurrency
 }
 
-// syncRepo syncs a single repository
-func (g *GitSyncer) syncRepo(repoURL string) error {
+// syncRepo syncs a single repository within the sync timeout and records the outcome
+func (g *GitSyncer) syncRepo(ctx context.Context, repoURL string) error {
+	repoCtx := ctx
+	timeout := g.SyncTimeout()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		repoCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := g.syncRepoFiles(repoCtx, repoURL)
+	if err != nil && ctx.Err() == nil && errors.Is(repoCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("sync timed out after %s: %w", timeout, err)
+	}
+	g.recordSync(repoURL, err)
+	return err
+}
+
+// syncRepoFiles clones, pulls or checks out a single repository
+func (g *GitSyncer) syncRepoFiles(ctx context.Context, repoURL string) error {
 	// Extract repo name from URL
-	repoName := g.extractRepoName(repoURL)
+	repoName := g.repoDirName(repoURL)
 	targetDir := filepath.Join(g.skillsDir, repoName)
 
+	cfg := g.getRepoConfig(repoURL)
+	removeStagingDirs(targetDir)
+
 	// Check if directory exists
 	_, err := os.Stat(targetDir)
 	if os.IsNotExist(err) {
-		// Clone the repository
-		return g.cloneRepo(repoURL, targetDir)
+		// Clone the repository, checking out the pinned ref before it goes live
+		g.logf(slog.LevelDebug, "cloning repo %s into %s", repoURL, repoName)
+		err = g.cloneStaged(ctx, repoURL, targetDir, func(stageDir string) error {
+			if cfg.Ref == "" {
+				return nil
+			}
+			r, err := git.PlainOpen(stageDir)
+			if err != nil {
+				return fmt.Errorf("failed to open repository: %w", err)
+			}
+			hash, err := g.resolveRef(ctx, repoURL, r, cfg.Ref)
+			if err != nil {
+				return err
+			}
+			return checkoutHash(r, *hash, cfg.Ref)
+		})
 	} else if err != nil {
 		return fmt.Errorf("failed to check directory: %w", err)
+	} else if cfg.Ref != "" {
+		// Pinned repositories are immutable, so there is nothing to pull
+		g.logf(slog.LevelDebug, "checking out %s of repo %s", cfg.Ref, repoURL)
+		err = g.checkoutRef(ctx, repoURL, targetDir, cfg.Ref)
+	} else {
+		// Pull updates
+		g.logf(slog.LevelDebug, "pulling repo %s", repoURL)
+		err = g.pullRepo(ctx, repoURL, targetDir)
+	}
+	if err != nil {
+		return err
 	}
 
-	// Pull updates
-	return g.pullRepo(targetDir)
+	g.reportLFSPointers(repoURL, targetDir)
+	return nil
+}
+
+// reportLFSPointers logs a warning for files in the repository that are unresolved Git LFS pointers.
+// go-git does not fetch LFS objects, so such files only contain the pointer text.
+func (g *GitSyncer) reportLFSPointers(repoURL, repoDir string) {
+	if g.logger == nil || !g.logger.Enabled(context.Background(), slog.LevelWarn) {
+		return
+	}
+	pointers := findLFSPointers(repoDir)
+	if len(pointers) == 0 {
+		return
+	}
+	g.logf(slog.LevelWarn, "repo %s contains %d unresolved Git LFS pointer file(s) (LFS content is not fetched): %s",
+		repoURL, len(pointers), strings.Join(pointers, ", "))
+}
+
+// findLFSPointers returns the paths (relative to dir) of files that are Git LFS pointer files
+func findLFSPointers(dir string) []string {
+	var pointers []string
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip errors, continue walking
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// The beginning of a file is enough to recognize a pointer
+		file, err := os.Open(path)
+		if err != nil {
+			return nil
+		}
+		defer file.Close()
+		buffer := make([]byte, 512)
+		n, _ := file.Read(buffer)
+		if domain.IsLFSPointer(info.Size(), buffer[:n]) {
+			relPath, _ := filepath.Rel(dir, path)
+			pointers = append(pointers, filepath.ToSlash(relPath))
+		}
+		return nil
+	})
+	return pointers
 }
 
 // cloneRepo clones a repository
-func (g *GitSyncer) cloneRepo(repoURL, targetDir string) error {
-	_, err := git.PlainClone(targetDir, false, &git.CloneOptions{
-		URL:      repoURL,
-		Progress: g.progress, // Use progress writer (nil = no output)
+func (g *GitSyncer) cloneRepo(ctx context.Context, repoURL, targetDir string) error {
+	auth, err := g.AuthFor(repoURL)
+	if err != nil {
+		return err
+	}
+	proxy := g.proxyFor(repoURL)
+	_, err = git.PlainCloneContext(ctx, targetDir, false, &git.CloneOptions{
+		URL:          repoURL,
+		Auth:         auth,
+		Progress:     g.progress, // Use progress writer (nil = no output)
+		ProxyOptions: proxy,
 	})
+	err = wrapProxyError(err, proxy)
 	if err != nil {
 		// Handle authentication errors gracefully
 		if err == transport.ErrAuthenticationRequired {
@@ -217,35 +534,144 @@ func (g *GitSyncer) cloneRepo(repoURL, targetDir string) error {
 	return nil
 }
 
-// pullRepo pulls updates from a repository
-func (g *GitSyncer) pullRepo(repoDir string) error {
+// pullRepo fetches updates from a repository and, if its branch moved, swaps in a checkout of the new commit
+// Fetching only adds objects to the repository, so the live worktree is untouched until the swap
+// Local changes or commits are discarded with the hard reset policy, and reported as ErrLocalChanges otherwise
+func (g *GitSyncer) pullRepo(ctx context.Context, repoURL, repoDir string) error {
 	r, err := git.PlainOpen(repoDir)
 	if err != nil {
 		return fmt.Errorf("failed to open repository: %w", err)
 	}
 
-	w, err := r.Worktree()
+	head, err := r.Head()
 	if err != nil {
-		return fmt.Errorf("failed to get worktree: %w", err)
+		return fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	if !head.Name().IsBranch() {
+		return fmt.Errorf("failed to pull: HEAD is not on a branch")
 	}
 
-	err = w.Pull(&git.PullOptions{
-		Progress: g.progress, // Use progress writer (nil = no output)
-	})
+	auth, err := g.AuthFor(repoURL)
 	if err != nil {
-		if err == git.NoErrAlreadyUpToDate {
-			// Not an error, just already up to date
-			return nil
-		}
+		return err
+	}
+	proxy := g.proxyFor(repoURL)
+	err = r.FetchContext(ctx, &git.FetchOptions{
+		Auth:         auth,
+		Progress:     g.progress, // Use progress writer (nil = no output)
+		ProxyOptions: proxy,
+	})
+	err = wrapProxyError(err, proxy)
+	if err != nil && err != git.NoErrAlreadyUpToDate {
 		if err == transport.ErrAuthenticationRequired {
 			return fmt.Errorf("authentication required")
 		}
 		return fmt.Errorf("failed to pull: %w", err)
 	}
 
+	upstream, err := r.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, head.Name().Short()), true)
+	if err != nil {
+		return fmt.Errorf("failed to pull: %w", err)
+	}
+	if upstream.Hash() == head.Hash() {
+		// Already up to date
+		return nil
+	}
+
+	// A checkout should only ever follow upstream, so local changes mean someone wrote into it
+	changes, err := localChanges(r, head.Hash(), upstream.Hash())
+	if err != nil {
+		return fmt.Errorf("failed to pull: %w", err)
+	}
+	if changes != "" {
+		if g.getResetPolicy() != ResetHard {
+			return fmt.Errorf("%w in %s: %s", ErrLocalChanges, filepath.Base(repoDir), changes)
+		}
+		g.logf(slog.LevelWarn, "discarding local changes in %s: %s", filepath.Base(repoDir), changes)
+	}
+
+	return replaceWorktree(repoDir, func(stage *git.Repository) error {
+		w, err := stage.Worktree()
+		if err != nil {
+			return fmt.Errorf("failed to get worktree: %w", err)
+		}
+		// Moves the branch to the upstream commit and checks it out, like a fast-forward pull
+		if err := w.Reset(&git.ResetOptions{Commit: upstream.Hash(), Mode: git.HardReset}); err != nil {
+			return fmt.Errorf("failed to pull: %w", err)
+		}
+		return nil
+	})
+}
+
+// checkoutRef checks out a pinned tag or commit, fetching from the remote only if the ref is not known locally
+func (g *GitSyncer) checkoutRef(ctx context.Context, repoURL, repoDir, ref string) error {
+	r, err := git.PlainOpen(repoDir)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	hash, err := g.resolveRef(ctx, repoURL, r, ref)
+	if err != nil {
+		return err
+	}
+
+	// Nothing to do if we are already at the pinned commit
+	if head, err := r.Head(); err == nil && head.Hash() == *hash {
+		return nil
+	}
+
+	return replaceWorktree(repoDir, func(stage *git.Repository) error {
+		return checkoutHash(stage, *hash, ref)
+	})
+}
+
+// checkoutHash checks out a commit, discarding any changes to the worktree
+func checkoutHash(r *git.Repository, hash plumbing.Hash, ref string) error {
+	w, err := r.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if err := w.Checkout(&git.CheckoutOptions{Hash: hash, Force: true}); err != nil {
+		return fmt.Errorf("failed to checkout %s: %w", ref, err)
+	}
 	return nil
 }
 
+// resolveRef resolves a tag or commit, fetching from the remote if it is not known locally
+func (g *GitSyncer) resolveRef(ctx context.Context, repoURL string, r *git.Repository, ref string) (*plumbing.Hash, error) {
+	hash, err := r.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		// The ref may not have been fetched yet (e.g. a newly pushed tag)
+		auth, err := g.AuthFor(repoURL)
+		if err != nil {
+			return nil, err
+		}
+		proxy := g.proxyFor(repoURL)
+		err = wrapProxyError(r.FetchContext(ctx, &git.FetchOptions{
+			Auth:         auth,
+			Tags:         git.AllTags,
+			Progress:     g.progress,
+			ProxyOptions: proxy,
+		}), proxy)
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			if err == transport.ErrAuthenticationRequired {
+				return nil, fmt.Errorf("authentication required")
+			}
+			return nil, fmt.Errorf("failed to fetch: %w", err)
+		}
+		hash, err = r.ResolveRevision(plumbing.Revision(ref))
+		if err != nil {
+			// A commit outside the fetched depth cannot be resolved
+			if shallow, _ := isShallow(r); shallow {
+				return nil, fmt.Errorf("ref %s not found: %w", ref, ErrShallowClone)
+			}
+			return nil, fmt.Errorf("ref %s not found: %w", ref, err)
+		}
+	}
+
+	return hash, nil
+}
+
 // SyncRepo manually syncs a specific repository by URL
 func (g *GitSyncer) SyncRepo(repoURL string) error {
 	// Check if repo is in the list
@@ -260,8 +686,11 @@ func (g *GitSyncer) SyncRepo(repoURL string) error {
 	if !found {
 		return fmt.Errorf("repository not configured: %s", repoURL)
 	}
+	if !g.isEnabled(repoURL) {
+		return fmt.Errorf("repository is disabled: %s", repoURL)
+	}
 
-	if err := g.syncRepo(repoURL); err != nil {
+	if err := g.syncRepo(g.ctx, repoURL); err != nil {
 		return err
 	}
 
@@ -275,34 +704,35 @@ func (g *GitSyncer) SyncRepo(repoURL string) error {
 	return nil
 }
 
-// extractRepoName extracts a repository name from a URL
-func (g *GitSyncer) extractRepoName(repoURL string) string {
-	// Remove protocol and .git suffix
-	name := strings.TrimSuffix(repoURL, ".git")
-
-	// Extract last part of path
-	parts := strings.Split(name, "/")
-	if len(parts) > 0 {
-		name = parts[len(parts)-1]
+// repoDirName returns the directory a repository is cloned into, below the skills directory:
+// the name in its settings, or one that no other configured repository uses (see RepoDirName)
+func (g *GitSyncer) repoDirName(repoURL string) string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if cfg, ok := g.repoConfigs[repoURL]; ok && cfg.Name != "" {
+		return cfg.Name
 	}
-
-	// Remove protocol prefix if present
-	if strings.Contains(name, "://") {
-		parts = strings.Split(name, "://")
-		if len(parts) > 1 {
-			parts = strings.Split(parts[1], "/")
-			if len(parts) > 0 {
-				name = parts[len(parts)-1]
-			}
+	others := make([]GitRepoConfig, 0, len(g.repoConfigs)+len(g.repos))
+	for _, cfg := range g.repoConfigs {
+		if cfg.URL != repoURL {
+			others = append(others, cfg)
 		}
 	}
-
-	return name
+	// Repositories without settings keep their name in the order they were configured
+	for _, url := range g.repos {
+		if url == repoURL {
+			break
+		}
+		if _, ok := g.repoConfigs[url]; !ok {
+			others = append(others, GitRepoConfig{URL: url})
+		}
+	}
+	return RepoDirName(repoURL, others)
 }
 
-// periodicSync runs periodic synchronization every 5 minutes
+// periodicSync runs periodic synchronization every SyncInterval
 func (g *GitSyncer) periodicSync() {
-	ticker := time.NewTicker(5 * time.Minute)
+	ticker := time.NewTicker(SyncInterval)
 	defer ticker.Stop()
 
 	for {
@@ -311,7 +741,7 @@ func (g *GitSyncer) periodicSync() {
 			return
 		case <-ticker.C:
 			if err := g.syncAll(); err != nil {
-				fmt.Printf("Warning: periodic sync failed: %v\n", err)
+				g.logf(slog.LevelError, "periodic sync failed: %v", err)
 			}
 		}
 	}
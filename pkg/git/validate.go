@@ -0,0 +1,124 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+
+	"github.com/mudler/skillserver/pkg/domain"
+)
+
+// RepoValidation is the result of a dry-run clone of a repository
+type RepoValidation struct {
+	Name   string   `json:"name"`   // Directory name the repository would be checked out to
+	Skills []string `json:"skills"` // Skills the repository would contribute, named like ListSkills names them (repoName/path/to/skill)
+}
+
+// ValidateRepo clones a repository to a temporary directory and reports the skills it contains,
+// without adding it to the syncer. branch and ref are optional; ref is checked out after cloning
+func (g *GitSyncer) ValidateRepo(repoURL, branch, ref string) (*RepoValidation, error) {
+	tempDir, err := os.MkdirTemp("", "skillserver-validate-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// A dry run is bounded by the sync timeout too
+	ctx := g.ctx
+	if timeout := g.SyncTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	auth, err := g.AuthFor(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	proxy := g.proxyFor(repoURL)
+	opts := &git.CloneOptions{
+		URL:          repoURL,
+		Auth:         auth,
+		ProxyOptions: proxy,
+	}
+	if branch != "" {
+		opts.ReferenceName = plumbing.NewBranchReferenceName(branch)
+		opts.SingleBranch = true
+	}
+	_, err = git.PlainCloneContext(ctx, tempDir, false, opts)
+	err = wrapProxyError(err, proxy)
+	if err != nil {
+		if err == transport.ErrAuthenticationRequired {
+			return nil, fmt.Errorf("authentication required for %s", repoURL)
+		}
+		return nil, fmt.Errorf("failed to clone repository: %w", err)
+	}
+
+	if ref != "" {
+		if err := g.checkoutRef(ctx, repoURL, tempDir, ref); err != nil {
+			return nil, err
+		}
+	}
+
+	repoName := g.repoDirName(repoURL)
+	skills, err := findSkills(tempDir, repoName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan repository: %w", err)
+	}
+
+	return &RepoValidation{Name: repoName, Skills: skills}, nil
+}
+
+// findSkills returns the repoName/path name of every directory below dir containing a SKILL.md,
+// path being the directory relative to dir
+// A repository with a skills manifest contributes the skills it lists instead
+func findSkills(dir, repoName string) ([]string, error) {
+	skills := []string{}
+	manifest, err := domain.ReadSkillsManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	listed := map[string]bool{}
+	if manifest != nil {
+		for _, skill := range manifest.Skills {
+			skills = append(skills, repoName+"/"+skill.Path)
+			listed[skill.Path] = true
+		}
+		if !manifest.Discover {
+			sort.Strings(skills)
+			return skills, nil
+		}
+	}
+
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != dir && domain.SkipSkillDiscovery(d.Name()) {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || d.Name() != "SKILL.md" || filepath.Dir(path) == dir {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		if relPath = filepath.ToSlash(relPath); !listed[relPath] {
+			skills = append(skills, repoName+"/"+relPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(skills)
+	return skills, nil
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// DefaultLevel is the level logged at when logging is enabled without choosing one
+const DefaultLevel = slog.LevelInfo
+
+// ParseLevel parses a log level name: "debug", "info", "warn" or "error"
+// An empty name is the default level
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "":
+		return DefaultLevel, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("invalid log level %q (expected debug, info, warn or error)", name)
+}
+
+// LevelName returns the name ParseLevel accepts for level, e.g. "warn"
+func LevelName(level slog.Level) string {
+	return strings.ToLower(level.String())
+}
+
+// New returns a logger writing text records of level and above to w
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// Discard returns a logger dropping every record, e.g. to keep stdout and stderr free for the MCP stdio transport
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
@@ -0,0 +1,56 @@
+package logging_test
+
+import (
+	"bytes"
+	"log/slog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/logging"
+)
+
+var _ = Describe("Logging", func() {
+	Describe("ParseLevel", func() {
+		It("should parse every level name, case-insensitively", func() {
+			for name, expected := range map[string]slog.Level{
+				"debug": slog.LevelDebug,
+				"INFO":  slog.LevelInfo,
+				"warn":  slog.LevelWarn,
+				"Error": slog.LevelError,
+			} {
+				level, err := logging.ParseLevel(name)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(level).To(Equal(expected))
+				Expect(logging.LevelName(level)).To(Equal(logging.LevelName(expected)))
+			}
+		})
+
+		It("should default to info", func() {
+			level, err := logging.ParseLevel("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(level).To(Equal(slog.LevelInfo))
+		})
+
+		It("should reject unknown levels", func() {
+			_, err := logging.ParseLevel("verbose")
+			Expect(err).To(MatchError(ContainSubstring("invalid log level")))
+		})
+	})
+
+	Describe("New", func() {
+		It("should suppress info messages at warn level", func() {
+			var logs bytes.Buffer
+			level, err := logging.ParseLevel("warn")
+			Expect(err).NotTo(HaveOccurred())
+			logger := logging.New(&logs, level)
+
+			logger.Info("synced repository")
+			logger.Warn("failed to sync repository")
+
+			Expect(logs.String()).NotTo(ContainSubstring("synced repository"))
+			Expect(logs.String()).To(ContainSubstring("level=WARN"))
+			Expect(logs.String()).To(ContainSubstring("failed to sync repository"))
+		})
+	})
+})
@@ -2,16 +2,27 @@ package mcp
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/mudler/skillserver/pkg/domain"
 )
 
+const (
+	// DefaultMaxExportSize is the largest skill archive export_skill returns by default, before base64 encoding
+	DefaultMaxExportSize = 5 * 1024 * 1024
+	// DefaultMaxReadSize is the largest resource content read_skill_resource returns in one call by default,
+	// before base64 encoding; larger files are read in chunks
+	DefaultMaxReadSize = 1024 * 1024
+)
+
 // Server wraps the MCP server and provides access to the skill manager
 type Server struct {
-	mcpServer    *mcp.Server
-	skillManager domain.SkillManager
+	mcpServer     *mcp.Server
+	skillManager  domain.SkillManager
+	maxExportSize int // Largest archive returned by export_skill, in bytes
+	maxReadSize   int // Largest content returned by a read_skill_resource call, in bytes
 }
 
 // NewServer creates a new MCP server for skills
@@ -22,6 +33,12 @@ func NewServer(skillManager domain.SkillManager) *Server {
 	}
 
 	mcpServer := mcp.NewServer(impl, nil)
+	server := &Server{
+		mcpServer:     mcpServer,
+		skillManager:  skillManager,
+		maxExportSize: DefaultMaxExportSize,
+		maxReadSize:   DefaultMaxReadSize,
+	}
 
 	// Register tools with closures that capture the skill manager
 	mcp.AddTool(mcpServer, &mcp.Tool{
@@ -48,7 +65,7 @@ func NewServer(skillManager domain.SkillManager) *Server {
 
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name:        "search_skills",
-		Description: "Search for skills by query string",
+		Description: "Search for skills by query string (returns snippets; use read_skill for the full content)",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input SearchSkillsInput) (
 		*mcp.CallToolResult,
 		SearchSkillsOutput,
@@ -70,13 +87,35 @@ func NewServer(skillManager domain.SkillManager) *Server {
 
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name:        "read_skill_resource",
-		Description: "Read the content of a skill resource file (scripts, references, or assets). Text files are returned as UTF-8, binary files as base64. Files larger than 1MB cannot be read via MCP.",
+		Description: "Read the content of a skill resource file (scripts, references, or assets). Text files are returned as UTF-8, binary files as base64. Large files are read in chunks: pass offset (and optionally length) and keep reading from next_offset until it is no longer returned.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input ReadSkillResourceInput) (
 		*mcp.CallToolResult,
 		ReadSkillResourceOutput,
 		error,
 	) {
-		return readSkillResource(ctx, req, input, skillManager)
+		return readSkillResource(ctx, req, input, skillManager, server.maxReadSize)
+	})
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "list_skill_files",
+		Description: "List every file of a skill except SKILL.md, including files outside the scripts, references and assets directories (typed 'other'), such as a README next to SKILL.md. Read them with read_skill_file.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListSkillFilesInput) (
+		*mcp.CallToolResult,
+		ListSkillFilesOutput,
+		error,
+	) {
+		return listSkillFiles(ctx, req, input, skillManager)
+	})
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "read_skill_file",
+		Description: "Read any file of a skill by its path relative to the skill root, such as documentation shipped next to SKILL.md (e.g. USAGE.md). Text files are returned as UTF-8, binary files as base64.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ReadSkillFileInput) (
+		*mcp.CallToolResult,
+		ReadSkillFileOutput,
+		error,
+	) {
+		return readSkillFile(ctx, req, input, skillManager, server.maxReadSize)
 	})
 
 	mcp.AddTool(mcpServer, &mcp.Tool{
@@ -90,10 +129,39 @@ func NewServer(skillManager domain.SkillManager) *Server {
 		return getSkillResourceInfo(ctx, req, input, skillManager)
 	})
 
-	return &Server{
-		mcpServer:    mcpServer,
-		skillManager: skillManager,
-	}
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "export_skill",
+		Description: "Export a skill directory (SKILL.md, scripts, references and assets) as a base64-encoded tar.gz archive, e.g. to hand it to another system",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ExportSkillInput) (
+		*mcp.CallToolResult,
+		ExportSkillOutput,
+		error,
+	) {
+		return exportSkill(ctx, req, input, skillManager, server.maxExportSize)
+	})
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name:        "get_stats",
+		Description: "Get an overview of the available skills: how many there are, how many come from each git repository and the total size of their resources",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetStatsInput) (
+		*mcp.CallToolResult,
+		GetStatsOutput,
+		error,
+	) {
+		return getStats(ctx, req, input, skillManager)
+	})
+
+	return server
+}
+
+// SetMaxExportSize sets the largest archive export_skill returns, in bytes before base64 encoding
+func (s *Server) SetMaxExportSize(size int) {
+	s.maxExportSize = size
+}
+
+// SetMaxReadSize sets the largest content read_skill_resource returns in one call, in bytes before base64 encoding
+func (s *Server) SetMaxReadSize(size int) {
+	s.maxReadSize = size
 }
 
 // Run starts the MCP server with stdio transport
@@ -105,3 +173,10 @@ func (s *Server) Run(ctx context.Context) error {
 func (s *Server) RunWithTransport(ctx context.Context, transport mcp.Transport) error {
 	return s.mcpServer.Run(ctx, transport)
 }
+
+// HTTPHandler returns an http.Handler serving this MCP server over the streamable HTTP transport
+func (s *Server) HTTPHandler() http.Handler {
+	return mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return s.mcpServer
+	}, nil)
+}
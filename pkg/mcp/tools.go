@@ -2,13 +2,22 @@ package mcp
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/mudler/skillserver/pkg/domain"
 )
 
+// searchSnippetLength is the number of characters of skill content included in search snippets
+const searchSnippetLength = 200
+
 // ListSkillsInput is the input for list_skills tool
 type ListSkillsInput struct{}
 
@@ -19,14 +28,21 @@ type ListSkillsOutput struct {
 
 // SkillInfo represents basic information about a skill
 type SkillInfo struct {
-	ID          string `json:"id"`   // Unique identifier to use when reading the skill (repoName/skillName or skillName)
-	Name        string `json:"name"` // Display name
-	Description string `json:"description,omitempty"`
+	ID            string    `json:"id"`   // Unique identifier to use when reading the skill (skillName, or its path such as repoName/skillName for git repo skills)
+	Name          string    `json:"name"` // Display name
+	Description   string    `json:"description,omitempty"`
+	Version       string    `json:"version,omitempty"`
+	Tags          []string  `json:"tags,omitempty"`
+	ContentLength int       `json:"content_length"` // Size of the content in bytes
+	WordCount     int       `json:"word_count"`
+	TokenEstimate int       `json:"token_estimate"` // Approximate number of tokens read_skill returns
+	Created       time.Time `json:"created"`        // Creation time of the skill directory
+	Modified      time.Time `json:"modified"`       // Last modification time of SKILL.md
 }
 
 // ReadSkillInput is the input for read_skill tool
 type ReadSkillInput struct {
-	ID string `json:"id" jsonschema:"The skill ID returned by list_skills or search_skills (format: 'skill-name' for local skills, or 'repoName/skill-name' for git repo skills)"`
+	ID string `json:"id" jsonschema:"The skill ID returned by list_skills or search_skills (format: 'skill-name' for local skills, or its path such as 'repoName/skill-name' or 'repoName/category/skill-name' for git repo skills)"`
 }
 
 // ReadSkillOutput is the output for read_skill tool
@@ -36,7 +52,11 @@ type ReadSkillOutput struct {
 
 // SearchSkillsInput is the input for search_skills tool
 type SearchSkillsInput struct {
-	Query string `json:"query" jsonschema:"The search query"`
+	Query          string `json:"query" jsonschema:"The search query"`
+	IncludeContent bool   `json:"include_content,omitempty" jsonschema:"Include the full skill content in each result (default false: only a snippet is returned, use read_skill for the full content)"`
+
+	FilterTags   []string `json:"filter_tags,omitempty" jsonschema:"Only return skills with these tags (case-insensitive); skills without tags are excluded"`
+	MatchAllTags bool     `json:"match_all_tags,omitempty" jsonschema:"Require every tag in filter_tags instead of any of them"`
 }
 
 // SearchSkillsOutput is the output for search_skills tool
@@ -46,10 +66,18 @@ type SearchSkillsOutput struct {
 
 // SearchResult represents a search result
 type SearchResult struct {
-	ID      string `json:"id"`   // Unique identifier to use when reading the skill (repoName/skillName or skillName)
-	Name    string `json:"name"` // Display name
-	Content string `json:"content"`
-	Snippet string `json:"snippet,omitempty"`
+	ID          string   `json:"id"`   // Unique identifier to use when reading the skill (skillName, or its path such as repoName/skillName for git repo skills)
+	Name        string   `json:"name"` // Display name
+	Description string   `json:"description,omitempty"`
+	Version     string   `json:"version,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Content     string   `json:"content,omitempty"` // Only set when include_content is requested
+	Snippet     string   `json:"snippet,omitempty"` // HTML-escaped, with matched terms in <mark>
+	Score       float64  `json:"score,omitempty"`   // Relevance; results are ordered by it, highest first
+
+	TokenEstimate int       `json:"token_estimate"` // Approximate number of tokens read_skill returns
+	Created       time.Time `json:"created"`        // Creation time of the skill directory
+	Modified      time.Time `json:"modified"`       // Last modification time of SKILL.md
 }
 
 // listSkills lists all available skills
@@ -68,9 +96,16 @@ func listSkills(ctx context.Context, req *mcp.CallToolRequest, input ListSkillsI
 		skillInfos[i] = SkillInfo{
 			ID: skill.ID,
 			//	Name: skill.Name,
+			ContentLength: skill.ContentLength,
+			WordCount:     skill.WordCount,
+			TokenEstimate: skill.TokenEstimate,
+			Created:       skill.Created,
+			Modified:      skill.Modified,
 		}
 		if skill.Metadata != nil {
 			skillInfos[i].Description = skill.Metadata.Description
+			skillInfos[i].Version = skill.Metadata.Version
+			skillInfos[i].Tags = skill.Metadata.Tags
 		}
 	}
 
@@ -97,24 +132,39 @@ func searchSkills(ctx context.Context, req *mcp.CallToolRequest, input SearchSki
 	SearchSkillsOutput,
 	error,
 ) {
-	skills, err := manager.SearchSkills(input.Query)
+	tags := domain.TagFilter{Tags: input.FilterTags, MatchAll: input.MatchAllTags}
+	var skills []domain.Skill
+	var err error
+	if searcher, ok := manager.(domain.SkillSearcher); ok {
+		skills, err = searcher.SearchSkillsWithOptions(input.Query, domain.SearchOptions{Tags: tags})
+	} else {
+		skills, err = manager.SearchSkills(input.Query)
+		skills = domain.FilterTags(skills, tags)
+	}
 	if err != nil {
 		return nil, SearchSkillsOutput{}, fmt.Errorf("failed to search skills: %w", err)
 	}
 
 	results := make([]SearchResult, len(skills))
 	for i, skill := range skills {
-		// Create a snippet (first 200 characters)
-		snippet := skill.Content
-		if len(snippet) > 200 {
-			snippet = snippet[:200] + "..."
-		}
-
 		results[i] = SearchResult{
 			ID:      skill.ID,
 			Name:    skill.Name,
-			Content: skill.Content,
-			Snippet: snippet,
+			Snippet: domain.SearchSnippet(skill, searchSnippetLength),
+			Score:   skill.Score,
+
+			TokenEstimate: skill.TokenEstimate,
+			Created:       skill.Created,
+			Modified:      skill.Modified,
+		}
+		if skill.Metadata != nil {
+			results[i].Description = skill.Metadata.Description
+			results[i].Version = skill.Metadata.Version
+			results[i].Tags = skill.Metadata.Tags
+		}
+		// Full content is opt-in to keep token usage down
+		if input.IncludeContent {
+			results[i].Content = skill.Content
 		}
 	}
 
@@ -123,7 +173,7 @@ func searchSkills(ctx context.Context, req *mcp.CallToolRequest, input SearchSki
 
 // ListSkillResourcesInput is the input for list_skill_resources tool
 type ListSkillResourcesInput struct {
-	SkillID string `json:"skill_id" jsonschema:"The skill ID returned by list_skills or search_skills (format: 'skill-name' for local skills, or 'repoName/skill-name' for git repo skills)"`
+	SkillID string `json:"skill_id" jsonschema:"The skill ID returned by list_skills or search_skills (format: 'skill-name' for local skills, or its path such as 'repoName/skill-name' or 'repoName/category/skill-name' for git repo skills)"`
 }
 
 // ListSkillResourcesOutput is the output for list_skill_resources tool
@@ -133,22 +183,86 @@ type ListSkillResourcesOutput struct {
 
 // SkillResourceInfo represents resource information in MCP responses
 type SkillResourceInfo struct {
-	Type     string `json:"type"`      // "script", "reference", or "asset"
-	Path     string `json:"path"`      // Relative path from skill root
-	Name     string `json:"name"`      // Filename only
-	Size     int64  `json:"size"`      // File size in bytes
-	MimeType string `json:"mime_type"` // MIME type
-	Readable bool   `json:"readable"`  // true if text file, false if binary
+	Type       string `json:"type"`                  // "script", "reference", "asset", or "other" in list_skill_files
+	Path       string `json:"path"`                  // Relative path from skill root
+	Name       string `json:"name"`                  // Filename only
+	Size       int64  `json:"size"`                  // File size in bytes
+	MimeType   string `json:"mime_type"`             // MIME type
+	Readable   bool   `json:"readable"`              // true if text file, false if binary
+	LFSPointer bool   `json:"lfs_pointer,omitempty"` // true if the file is an unresolved Git LFS pointer
 }
 
 // ReadSkillResourceInput is the input for read_skill_resource tool
 type ReadSkillResourceInput struct {
 	SkillID      string `json:"skill_id"`      // The skill ID
 	ResourcePath string `json:"resource_path"` // Relative path from skill root (e.g., "scripts/script.py")
+
+	Offset int64 `json:"offset,omitempty" jsonschema:"Byte offset to start reading at, to read a large file in chunks (default 0)"`
+	Length int64 `json:"length,omitempty" jsonschema:"Number of bytes to read from offset (default and maximum: the server's read limit)"`
 }
 
 // ReadSkillResourceOutput is the output for read_skill_resource tool
 type ReadSkillResourceOutput struct {
+	Content  string `json:"content"`  // UTF-8 for text, base64 for binary
+	Encoding string `json:"encoding"` // "utf-8" or "base64"
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"` // Number of bytes returned
+
+	TotalSize  int64  `json:"total_size"`            // Size of the whole file
+	Offset     int64  `json:"offset"`                // Byte offset of the returned content
+	NextOffset *int64 `json:"next_offset,omitempty"` // Offset to read the next chunk from, unset at the end of the file
+}
+
+// ListSkillFilesInput is the input for list_skill_files tool
+type ListSkillFilesInput struct {
+	SkillID string `json:"skill_id" jsonschema:"The skill ID returned by list_skills or search_skills"`
+}
+
+// ListSkillFilesOutput is the output for list_skill_files tool
+type ListSkillFilesOutput struct {
+	Files []SkillResourceInfo `json:"files"`
+}
+
+// listSkillFiles lists every file of a skill but SKILL.md, inside the resource directories or not
+func listSkillFiles(ctx context.Context, req *mcp.CallToolRequest, input ListSkillFilesInput, manager domain.SkillManager) (
+	*mcp.CallToolResult,
+	ListSkillFilesOutput,
+	error,
+) {
+	reader, ok := manager.(domain.SkillFileReader)
+	if !ok {
+		return nil, ListSkillFilesOutput{}, fmt.Errorf("reading skill files is not supported")
+	}
+
+	files, err := reader.ListSkillFiles(input.SkillID)
+	if err != nil {
+		return nil, ListSkillFilesOutput{}, fmt.Errorf("failed to list skill files: %w", err)
+	}
+
+	fileInfos := make([]SkillResourceInfo, len(files))
+	for i, file := range files {
+		fileInfos[i] = SkillResourceInfo{
+			Type:       string(file.Type),
+			Path:       file.Path,
+			Name:       file.Name,
+			Size:       file.Size,
+			MimeType:   file.MimeType,
+			Readable:   file.Readable,
+			LFSPointer: file.LFSPointer,
+		}
+	}
+
+	return nil, ListSkillFilesOutput{Files: fileInfos}, nil
+}
+
+// ReadSkillFileInput is the input for read_skill_file tool
+type ReadSkillFileInput struct {
+	SkillID  string `json:"skill_id"`  // The skill ID
+	FilePath string `json:"file_path"` // Relative path from skill root (e.g., "USAGE.md")
+}
+
+// ReadSkillFileOutput is the output for read_skill_file tool
+type ReadSkillFileOutput struct {
 	Content  string `json:"content"`  // UTF-8 for text, base64 for binary
 	Encoding string `json:"encoding"` // "utf-8" or "base64"
 	MimeType string `json:"mime_type"`
@@ -163,13 +277,14 @@ type GetSkillResourceInfoInput struct {
 
 // GetSkillResourceInfoOutput is the output for get_skill_resource_info tool
 type GetSkillResourceInfoOutput struct {
-	Exists   bool   `json:"exists"`
-	Type     string `json:"type"`
-	Path     string `json:"path"`
-	Name     string `json:"name"`
-	Size     int64  `json:"size"`
-	MimeType string `json:"mime_type"`
-	Readable bool   `json:"readable"`
+	Exists     bool   `json:"exists"`
+	Type       string `json:"type"`
+	Path       string `json:"path"`
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	MimeType   string `json:"mime_type"`
+	Readable   bool   `json:"readable"`
+	LFSPointer bool   `json:"lfs_pointer,omitempty"`
 }
 
 // listSkillResources lists all resources in a skill's optional directories
@@ -186,41 +301,76 @@ func listSkillResources(ctx context.Context, req *mcp.CallToolRequest, input Lis
 	resourceInfos := make([]SkillResourceInfo, len(resources))
 	for i, res := range resources {
 		resourceInfos[i] = SkillResourceInfo{
-			Type:     string(res.Type),
-			Path:     res.Path,
-			Name:     res.Name,
-			Size:     res.Size,
-			MimeType: res.MimeType,
-			Readable: res.Readable,
+			Type:       string(res.Type),
+			Path:       res.Path,
+			Name:       res.Name,
+			Size:       res.Size,
+			MimeType:   res.MimeType,
+			Readable:   res.Readable,
+			LFSPointer: res.LFSPointer,
 		}
 	}
 
 	return nil, ListSkillResourcesOutput{Resources: resourceInfos}, nil
 }
 
-// readSkillResource reads the content of a skill resource file
-func readSkillResource(ctx context.Context, req *mcp.CallToolRequest, input ReadSkillResourceInput, manager domain.SkillManager) (
+// readSkillResource reads the content of a skill resource file, whole or in chunks of at most maxSize bytes
+func readSkillResource(ctx context.Context, req *mcp.CallToolRequest, input ReadSkillResourceInput, manager domain.SkillManager, maxSize int) (
 	*mcp.CallToolResult,
 	ReadSkillResourceOutput,
 	error,
 ) {
-	// Check file size limit (1MB for MCP)
 	info, err := manager.GetSkillResourceInfo(input.SkillID, input.ResourcePath)
 	if err != nil {
 		return nil, ReadSkillResourceOutput{}, fmt.Errorf("failed to get resource info: %w", err)
 	}
+	if info.LFSPointer {
+		return nil, ReadSkillResourceOutput{}, fmt.Errorf("failed to read resource: %w", domain.ErrLFSPointer)
+	}
+
+	// Small files are read in one go unless a range is asked for
+	if input.Offset == 0 && input.Length == 0 {
+		if info.Size > int64(maxSize) {
+			return nil, ReadSkillResourceOutput{}, fmt.Errorf("file too large (%d bytes, max %d per read). Read it in chunks with offset and length", info.Size, maxSize)
+		}
 
-	const maxMCPFileSize = 1024 * 1024 // 1MB
-	if info.Size > maxMCPFileSize {
-		return nil, ReadSkillResourceOutput{}, fmt.Errorf("file too large (%d bytes, max %d). Use web UI to download", info.Size, maxMCPFileSize)
+		content, err := manager.ReadSkillResource(input.SkillID, input.ResourcePath)
+		if err != nil {
+			return nil, ReadSkillResourceOutput{}, fmt.Errorf("failed to read resource: %w", err)
+		}
+
+		return nil, ReadSkillResourceOutput{
+			Content:   content.Content,
+			Encoding:  content.Encoding,
+			MimeType:  content.MimeType,
+			Size:      content.Size,
+			TotalSize: content.Size,
+		}, nil
 	}
 
-	content, err := manager.ReadSkillResource(input.SkillID, input.ResourcePath)
+	return readResourceChunk(input, manager, info, maxSize)
+}
+
+// readSkillFile reads a file of a skill by its path relative to the skill root, of at most maxSize bytes
+func readSkillFile(ctx context.Context, req *mcp.CallToolRequest, input ReadSkillFileInput, manager domain.SkillManager, maxSize int) (
+	*mcp.CallToolResult,
+	ReadSkillFileOutput,
+	error,
+) {
+	reader, ok := manager.(domain.SkillFileReader)
+	if !ok {
+		return nil, ReadSkillFileOutput{}, fmt.Errorf("reading skill files is not supported")
+	}
+
+	content, err := reader.ReadSkillFile(input.SkillID, input.FilePath)
 	if err != nil {
-		return nil, ReadSkillResourceOutput{}, fmt.Errorf("failed to read resource: %w", err)
+		return nil, ReadSkillFileOutput{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	if content.Size > int64(maxSize) {
+		return nil, ReadSkillFileOutput{}, fmt.Errorf("file too large (%d bytes, max %d per read)", content.Size, maxSize)
 	}
 
-	return nil, ReadSkillResourceOutput{
+	return nil, ReadSkillFileOutput{
 		Content:  content.Content,
 		Encoding: content.Encoding,
 		MimeType: content.MimeType,
@@ -228,6 +378,87 @@ func readSkillResource(ctx context.Context, req *mcp.CallToolRequest, input Read
 	}, nil
 }
 
+// readResourceChunk reads the byte range of a resource asked for by input, of at most maxSize bytes
+func readResourceChunk(input ReadSkillResourceInput, manager domain.SkillManager, info *domain.SkillResource, maxSize int) (
+	*mcp.CallToolResult,
+	ReadSkillResourceOutput,
+	error,
+) {
+	if input.Offset < 0 || input.Length < 0 {
+		return nil, ReadSkillResourceOutput{}, fmt.Errorf("offset and length must not be negative")
+	}
+	if input.Offset > info.Size {
+		return nil, ReadSkillResourceOutput{}, fmt.Errorf("offset %d is past the end of the file (%d bytes)", input.Offset, info.Size)
+	}
+	opener, ok := manager.(domain.ResourceOpener)
+	if !ok {
+		return nil, ReadSkillResourceOutput{}, fmt.Errorf("chunked reads are not supported")
+	}
+
+	length := input.Length
+	if length == 0 || length > int64(maxSize) {
+		length = int64(maxSize)
+	}
+	length = min(length, info.Size-input.Offset)
+
+	file, err := opener.OpenSkillResource(input.SkillID, input.ResourcePath)
+	if err != nil {
+		return nil, ReadSkillResourceOutput{}, fmt.Errorf("failed to read resource: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(input.Offset, io.SeekStart); err != nil {
+		return nil, ReadSkillResourceOutput{}, fmt.Errorf("failed to read resource: %w", err)
+	}
+	chunk := make([]byte, length)
+	n, err := io.ReadFull(file, chunk)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, ReadSkillResourceOutput{}, fmt.Errorf("failed to read resource: %w", err)
+	}
+	chunk = chunk[:n]
+
+	output := ReadSkillResourceOutput{
+		Encoding:  "base64",
+		MimeType:  info.MimeType,
+		TotalSize: info.Size,
+		Offset:    input.Offset,
+	}
+	if info.Readable {
+		// Leave a character split by the end of the chunk to the next one, so every chunk is valid UTF-8
+		if input.Offset+int64(len(chunk)) < info.Size {
+			chunk = trimPartialRune(chunk)
+		}
+		if utf8.Valid(chunk) {
+			output.Encoding = "utf-8"
+		}
+	}
+	if output.Encoding == "utf-8" {
+		output.Content = string(chunk)
+	} else {
+		output.Content = base64.StdEncoding.EncodeToString(chunk)
+	}
+	output.Size = int64(len(chunk))
+	if next := input.Offset + output.Size; next < info.Size {
+		output.NextOffset = &next
+	}
+
+	return nil, output, nil
+}
+
+// trimPartialRune drops the bytes of a multi-byte UTF-8 character cut off at the end of b
+// b is returned as is if that would leave nothing
+func trimPartialRune(b []byte) []byte {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(b[i]) {
+			continue
+		}
+		if i > 0 && !utf8.FullRune(b[i:]) {
+			return b[:i]
+		}
+		return b
+	}
+	return b
+}
+
 // getSkillResourceInfo gets metadata about a specific resource without reading content
 func getSkillResourceInfo(ctx context.Context, req *mcp.CallToolRequest, input GetSkillResourceInfoInput, manager domain.SkillManager) (
 	*mcp.CallToolResult,
@@ -243,12 +474,94 @@ func getSkillResourceInfo(ctx context.Context, req *mcp.CallToolRequest, input G
 	}
 
 	return nil, GetSkillResourceInfoOutput{
-		Exists:   true,
-		Type:     string(info.Type),
-		Path:     info.Path,
-		Name:     info.Name,
-		Size:     info.Size,
-		MimeType: info.MimeType,
-		Readable: info.Readable,
+		Exists:     true,
+		Type:       string(info.Type),
+		Path:       info.Path,
+		Name:       info.Name,
+		Size:       info.Size,
+		MimeType:   info.MimeType,
+		Readable:   info.Readable,
+		LFSPointer: info.LFSPointer,
+	}, nil
+}
+
+// ExportSkillInput is the input for export_skill tool
+type ExportSkillInput struct {
+	ID string `json:"id" jsonschema:"The skill ID returned by list_skills or search_skills"`
+}
+
+// ExportSkillOutput is the output for export_skill tool
+type ExportSkillOutput struct {
+	Filename string `json:"filename"` // Suggested file name, e.g. "my-skill.tar.gz"
+	Size     int    `json:"size"`     // Size of the archive in bytes, before base64 encoding
+	Encoding string `json:"encoding"` // Always "base64"
+	Archive  string `json:"archive"`  // The tar.gz archive
+}
+
+// exportSkill exports a skill as a base64-encoded tar.gz archive of at most maxSize bytes
+func exportSkill(ctx context.Context, req *mcp.CallToolRequest, input ExportSkillInput, manager domain.SkillManager, maxSize int) (
+	*mcp.CallToolResult,
+	ExportSkillOutput,
+	error,
+) {
+	exporter, ok := manager.(domain.SkillExporter)
+	if !ok {
+		return nil, ExportSkillOutput{}, fmt.Errorf("skill export is not supported")
+	}
+
+	skill, err := manager.ReadSkill(input.ID)
+	if err != nil {
+		return nil, ExportSkillOutput{}, fmt.Errorf("failed to read skill: %w", err)
+	}
+
+	archive, err := exporter.ExportSkill(input.ID)
+	if err != nil {
+		return nil, ExportSkillOutput{}, fmt.Errorf("failed to export skill: %w", err)
+	}
+	if len(archive) > maxSize {
+		return nil, ExportSkillOutput{}, fmt.Errorf("skill archive too large (%d bytes, max %d). Use the web UI to download it", len(archive), maxSize)
+	}
+
+	return nil, ExportSkillOutput{
+		Filename: strings.ReplaceAll(skill.Name, "/", "-") + ".tar.gz",
+		Size:     len(archive),
+		Encoding: "base64",
+		Archive:  base64.StdEncoding.EncodeToString(archive),
+	}, nil
+}
+
+// GetStatsInput is the input for get_stats tool
+type GetStatsInput struct{}
+
+// GetStatsOutput is the output for get_stats tool
+type GetStatsOutput struct {
+	Skills        int            `json:"skills"`
+	LocalSkills   int            `json:"local_skills"`
+	GitSkills     int            `json:"git_skills"`
+	GitRepos      int            `json:"git_repos"`   // Git repositories contributing skills
+	RepoSkills    map[string]int `json:"repo_skills"` // Number of skills per git repository
+	Resources     int            `json:"resources"`
+	ResourceBytes int64          `json:"resource_bytes"` // Total size of the resources of every skill
+}
+
+// getStats summarizes the available skills and their resources
+func getStats(ctx context.Context, req *mcp.CallToolRequest, input GetStatsInput, manager domain.SkillManager) (
+	*mcp.CallToolResult,
+	GetStatsOutput,
+	error,
+) {
+	stats, err := domain.CollectStats(manager)
+	if err != nil {
+		return nil, GetStatsOutput{}, err
+	}
+
+	return nil, GetStatsOutput{
+		Skills:        stats.Skills,
+		LocalSkills:   stats.LocalSkills,
+		GitSkills:     stats.GitSkills,
+		GitRepos:      len(stats.RepoSkills),
+		RepoSkills:    stats.RepoSkills,
+		Resources:     stats.Resources,
+		ResourceBytes: stats.ResourceBytes,
 	}, nil
 }
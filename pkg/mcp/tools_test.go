@@ -0,0 +1,253 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/mcp"
+)
+
+var _ = Describe("MCP tools", func() {
+	var (
+		tempDir   string
+		skillsDir string
+		manager   *domain.FileSystemManager
+		server    *mcp.Server
+		session   *gomcp.ClientSession
+		ctx       context.Context
+		cancel    context.CancelFunc
+		err       error
+	)
+
+	BeforeEach(func() {
+		tempDir, err = os.MkdirTemp("", "skillserver-mcp-test")
+		Expect(err).NotTo(HaveOccurred())
+		skillsDir = filepath.Join(tempDir, "skills")
+
+		skillDir := filepath.Join(skillsDir, "my-skill")
+		Expect(os.MkdirAll(filepath.Join(skillDir, "scripts"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# My Skill"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "scripts", "run.sh"), []byte("#!/bin/sh\necho hi"), 0755)).To(Succeed())
+
+		manager, err = domain.NewFileSystemManager(skillsDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = mcp.NewServer(manager)
+	})
+
+	JustBeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		serverTransport, clientTransport := gomcp.NewInMemoryTransports()
+		go server.RunWithTransport(ctx, serverTransport)
+
+		client := gomcp.NewClient(&gomcp.Implementation{Name: "test", Version: "v1.0.0"}, nil)
+		session, err = client.Connect(ctx, clientTransport, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		session.Close()
+		cancel()
+		os.RemoveAll(tempDir)
+	})
+
+	callTool := func(name string, args map[string]any) *gomcp.CallToolResult {
+		result, err := session.CallTool(ctx, &gomcp.CallToolParams{Name: name, Arguments: args})
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	Context("export_skill", func() {
+		It("should export a skill that round-trips through ImportSkill", func() {
+			result := callTool("export_skill", map[string]any{"id": "my-skill"})
+			Expect(result.IsError).To(BeFalse())
+
+			data, err := json.Marshal(result.StructuredContent)
+			Expect(err).NotTo(HaveOccurred())
+			var output mcp.ExportSkillOutput
+			Expect(json.Unmarshal(data, &output)).To(Succeed())
+			Expect(output.Filename).To(Equal("my-skill.tar.gz"))
+			Expect(output.Encoding).To(Equal("base64"))
+
+			archive, err := base64.StdEncoding.DecodeString(output.Archive)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(archive).To(HaveLen(output.Size))
+
+			importDir := filepath.Join(tempDir, "imported")
+			Expect(os.MkdirAll(importDir, 0755)).To(Succeed())
+			name, err := domain.ImportSkill(archive, importDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(name).To(Equal("my-skill"))
+
+			script, err := os.ReadFile(filepath.Join(importDir, "my-skill", "scripts", "run.sh"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(script)).To(Equal("#!/bin/sh\necho hi"))
+		})
+
+		Context("with a small size limit", func() {
+			BeforeEach(func() {
+				server.SetMaxExportSize(10)
+			})
+
+			It("should reject archives over the limit", func() {
+				result := callTool("export_skill", map[string]any{"id": "my-skill"})
+				Expect(result.IsError).To(BeTrue())
+				Expect(result.Content[0].(*gomcp.TextContent).Text).To(ContainSubstring("skill archive too large"))
+			})
+		})
+
+		It("should report unknown skills", func() {
+			result := callTool("export_skill", map[string]any{"id": "missing"})
+			Expect(result.IsError).To(BeTrue())
+		})
+	})
+
+	Context("read_skill_resource", func() {
+		var document string
+
+		readChunk := func(args map[string]any) mcp.ReadSkillResourceOutput {
+			result := callTool("read_skill_resource", args)
+			Expect(result.IsError).To(BeFalse())
+			data, err := json.Marshal(result.StructuredContent)
+			Expect(err).NotTo(HaveOccurred())
+			var output mcp.ReadSkillResourceOutput
+			Expect(json.Unmarshal(data, &output)).To(Succeed())
+			return output
+		}
+
+		BeforeEach(func() {
+			// Multi-byte characters make some chunk boundaries fall inside a character
+			document = strings.Repeat("# Guide: café ☕ naïve\n", 150)
+			referencesDir := filepath.Join(skillsDir, "my-skill", "references")
+			Expect(os.MkdirAll(referencesDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(referencesDir, "guide.md"), []byte(document), 0644)).To(Succeed())
+			server.SetMaxReadSize(1000)
+		})
+
+		It("should read small files in one go", func() {
+			server.SetMaxReadSize(mcp.DefaultMaxReadSize)
+			output := readChunk(map[string]any{"skill_id": "my-skill", "resource_path": "references/guide.md"})
+			Expect(output.Content).To(Equal(document))
+			Expect(output.TotalSize).To(Equal(int64(len(document))))
+			Expect(output.NextOffset).To(BeNil())
+		})
+
+		It("should ask for chunks when a file is over the limit", func() {
+			result := callTool("read_skill_resource", map[string]any{"skill_id": "my-skill", "resource_path": "references/guide.md"})
+			Expect(result.IsError).To(BeTrue())
+			Expect(result.Content[0].(*gomcp.TextContent).Text).To(ContainSubstring("Read it in chunks"))
+		})
+
+		It("should read a large file in chunks that reassemble into it", func() {
+			var reassembled strings.Builder
+			var chunks int
+			offset := int64(0)
+			for {
+				output := readChunk(map[string]any{"skill_id": "my-skill", "resource_path": "references/guide.md", "offset": offset, "length": 700})
+				Expect(output.Encoding).To(Equal("utf-8"))
+				Expect(output.Offset).To(Equal(offset))
+				Expect(output.TotalSize).To(Equal(int64(len(document))))
+				Expect(output.Size).To(BeNumerically("<=", 700))
+				reassembled.WriteString(output.Content)
+				chunks++
+				if output.NextOffset == nil {
+					break
+				}
+				offset = *output.NextOffset
+			}
+			Expect(chunks).To(BeNumerically(">", 1))
+			Expect(reassembled.String()).To(Equal(document))
+		})
+
+		It("should cap chunks at the read limit", func() {
+			output := readChunk(map[string]any{"skill_id": "my-skill", "resource_path": "references/guide.md", "offset": 0, "length": 5000})
+			Expect(output.Size).To(BeNumerically("<=", 1000))
+			Expect(output.NextOffset).NotTo(BeNil())
+		})
+
+		It("should reject offsets past the end of the file", func() {
+			result := callTool("read_skill_resource", map[string]any{"skill_id": "my-skill", "resource_path": "references/guide.md", "offset": len(document) + 1})
+			Expect(result.IsError).To(BeTrue())
+		})
+	})
+
+	Context("list_skill_files", func() {
+		It("should list files inside and outside the resource directories", func() {
+			Expect(os.WriteFile(filepath.Join(skillsDir, "my-skill", "README.md"), []byte("# Readme"), 0644)).To(Succeed())
+
+			result := callTool("list_skill_files", map[string]any{"skill_id": "my-skill"})
+			Expect(result.IsError).To(BeFalse())
+			data, err := json.Marshal(result.StructuredContent)
+			Expect(err).NotTo(HaveOccurred())
+			var output mcp.ListSkillFilesOutput
+			Expect(json.Unmarshal(data, &output)).To(Succeed())
+			types := map[string]string{}
+			for _, file := range output.Files {
+				types[file.Path] = file.Type
+			}
+			Expect(types).To(Equal(map[string]string{"README.md": "other", "scripts/run.sh": "script"}))
+		})
+	})
+
+	Context("read_skill_file", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(filepath.Join(skillsDir, "my-skill", "USAGE.md"), []byte("# Usage\nRun it."), 0644)).To(Succeed())
+		})
+
+		It("should read a file next to SKILL.md", func() {
+			result := callTool("read_skill_file", map[string]any{"skill_id": "my-skill", "file_path": "USAGE.md"})
+			Expect(result.IsError).To(BeFalse())
+			data, err := json.Marshal(result.StructuredContent)
+			Expect(err).NotTo(HaveOccurred())
+			var output mcp.ReadSkillFileOutput
+			Expect(json.Unmarshal(data, &output)).To(Succeed())
+			Expect(output.Content).To(Equal("# Usage\nRun it."))
+			Expect(output.Encoding).To(Equal("utf-8"))
+		})
+
+		It("should refuse paths outside the skill", func() {
+			result := callTool("read_skill_file", map[string]any{"skill_id": "my-skill", "file_path": "../other/SKILL.md"})
+			Expect(result.IsError).To(BeTrue())
+		})
+	})
+
+	Context("get_stats", func() {
+		BeforeEach(func() {
+			for _, name := range []string{"docker", "helm"} {
+				skillDir := filepath.Join(skillsDir, "repo", name)
+				Expect(os.MkdirAll(filepath.Join(skillDir, "assets"), 0755)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: "+name+"\ndescription: A skill\n---\n# Skill"), 0644)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(skillDir, "assets", "logo.txt"), []byte("logo"), 0644)).To(Succeed())
+			}
+			otherDir := filepath.Join(skillsDir, "other", "linux")
+			Expect(os.MkdirAll(otherDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(otherDir, "SKILL.md"), []byte("---\nname: linux\ndescription: A skill\n---\n# Linux"), 0644)).To(Succeed())
+			manager.UpdateGitRepos([]string{"repo", "other"})
+		})
+
+		It("should count local and git repository skills and their resources", func() {
+			result := callTool("get_stats", map[string]any{})
+			Expect(result.IsError).To(BeFalse())
+
+			data, err := json.Marshal(result.StructuredContent)
+			Expect(err).NotTo(HaveOccurred())
+			var output mcp.GetStatsOutput
+			Expect(json.Unmarshal(data, &output)).To(Succeed())
+			Expect(output.Skills).To(Equal(4))
+			Expect(output.LocalSkills).To(Equal(1))
+			Expect(output.GitSkills).To(Equal(3))
+			Expect(output.GitRepos).To(Equal(2))
+			Expect(output.RepoSkills).To(Equal(map[string]int{"repo": 2, "other": 1}))
+			Expect(output.Resources).To(Equal(3))
+			Expect(output.ResourceBytes).To(Equal(int64(len("#!/bin/sh\necho hi") + 2*len("logo"))))
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package web
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// SetAPIKey requires "Authorization: Bearer <key>" on requests that create, update or delete anything
+// through the REST API and on the configuration dump, and with forReads on every REST API request and on
+// the MCP HTTP endpoints too
+// The UI, health probes, CORS preflight requests and signed webhooks stay open; an empty key disables authentication
+func (s *Server) SetAPIKey(key string, forReads bool) {
+	s.apiKey = key
+	s.apiKeyForReads = forReads
+}
+
+// configPath is the REST API endpoint dumping the server configuration
+const configPath = "/api/config"
+
+// requireAPIKey rejects protected requests without the configured API key
+func (s *Server) requireAPIKey(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		if s.apiKey == "" || !s.requiresAPIKey(c.Request()) {
+			return next(c)
+		}
+
+		token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(s.apiKey)) != 1 {
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+			return c.JSON(http.StatusUnauthorized, map[string]string{
+				"error": "missing or invalid API key",
+			})
+		}
+		return next(c)
+	}
+}
+
+// requiresAPIKey reports whether a request needs the API key
+func (s *Server) requiresAPIKey(req *http.Request) bool {
+	path := req.URL.Path
+	switch {
+	case req.Method == http.MethodOptions:
+		return false
+	case s.webhookSecret != "" && isWebhookPath(path):
+		// Git hosts cannot send the API key, so signed webhooks are checked against the webhook secret instead
+		return false
+	case path == configPath:
+		// The configuration reveals directories, repository URLs and limits, which are not skills to read
+		return true
+	case strings.HasPrefix(path, "/api/"):
+		if req.Method == http.MethodGet || req.Method == http.MethodHead {
+			return s.apiKeyForReads
+		}
+		return true
+	case s.isMCPPath(path):
+		// The MCP tools only read skills
+		return s.apiKeyForReads
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/web"
+)
+
+var _ = Describe("API key authentication", func() {
+	const apiKey = "s3cret"
+
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	request := func(method, path, authorization string) *httptest.ResponseRecorder {
+		var body *strings.Reader
+		if method == http.MethodPost {
+			body = strings.NewReader(`{"name":"my-skill","description":"A skill","content":"# My Skill"}`)
+		} else {
+			body = strings.NewReader("")
+		}
+		req := httptest.NewRequest(method, path, body)
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-auth-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should be disabled by default", func() {
+		rec := request(http.MethodPost, "/api/skills", "")
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+	})
+
+	It("should require the key on writes only", func() {
+		server.SetAPIKey(apiKey, false)
+
+		rec := request(http.MethodPost, "/api/skills", "")
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		Expect(rec.Header().Get("WWW-Authenticate")).To(Equal("Bearer"))
+
+		rec = request(http.MethodPost, "/api/skills", "Bearer wrong")
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+
+		rec = request(http.MethodDelete, "/api/skills/my-skill", "")
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+
+		rec = request(http.MethodPost, "/api/skills", "Bearer "+apiKey)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+
+		rec = request(http.MethodGet, "/api/skills/my-skill", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		rec = request(http.MethodDelete, "/api/skills/my-skill", "Bearer "+apiKey)
+		Expect(rec.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("should require the key on reads when asked to", func() {
+		server.SetAPIKey(apiKey, true)
+
+		rec := request(http.MethodGet, "/api/skills", "")
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+
+		rec = request(http.MethodGet, "/api/skills", "Bearer "+apiKey)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+
+	It("should always require the key on the configuration", func() {
+		server.SetAPIKey(apiKey, false)
+
+		rec := request(http.MethodGet, "/api/config", "")
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+
+		rec = request(http.MethodGet, "/api/config", "Bearer "+apiKey)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+
+	It("should leave health probes open", func() {
+		server.SetAPIKey(apiKey, true)
+
+		rec := request(http.MethodGet, "/healthz", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+})
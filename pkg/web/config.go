@@ -0,0 +1,147 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v5"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/git"
+)
+
+// RuntimeConfig holds the startup settings the server cannot derive from its own state
+type RuntimeConfig struct {
+	Host             string // Host the web server listens on (empty = all interfaces)
+	Port             string
+	TLS              bool // Whether the web server serves HTTPS
+	GitProxy         string
+	MCPScopes        []string // Names of the MCP scopes served over HTTP
+	MCPTransport     string   // Transport of the main MCP server ("stdio" or "http")
+	MCPPath          string   // Path the main MCP server is served at (empty with the stdio transport)
+	CORSOrigins      []string // Origins allowed to call the REST API from a browser (empty = CORS disabled)
+	ShutdownTimeout  time.Duration
+	EnableLogging    bool
+	LogLevel         string // Minimum level logged, e.g. "warn" (empty when logging is disabled)
+	MCPMaxExportSize int    // Largest archive returned by the export_skill MCP tool
+	MCPMaxReadSize   int    // Largest content returned by a read_skill_resource MCP call
+	Watch            bool   // Whether skill changes on disk are re-indexed automatically
+}
+
+// ConfigResponse is the resolved configuration the server is running with
+// Credentials embedded in URLs are redacted
+type ConfigResponse struct {
+	Dir                       string       `json:"dir"`
+	IndexDir                  string       `json:"indexDir"`
+	OverlayDir                string       `json:"overlayDir,omitempty"` // Empty when the local overlay is disabled
+	Host                      string       `json:"host"`                 // Empty when listening on all interfaces
+	Port                      string       `json:"port"`
+	TLS                       bool         `json:"tls"`
+	GitRepos                  []string     `json:"gitRepos"`
+	GitProxy                  string       `json:"gitProxy,omitempty"` // Empty when the proxy environment variables apply
+	SyncInterval              string       `json:"syncInterval"`
+	SyncTimeout               string       `json:"syncTimeout"`     // Longest a single repository sync may take
+	SyncConcurrency           int          `json:"syncConcurrency"` // Repositories synced at a time
+	ShutdownTimeout           string       `json:"shutdownTimeout"`
+	EnableLogging             bool         `json:"enableLogging"`
+	LogLevel                  string       `json:"logLevel,omitempty"` // Empty when logging is disabled
+	Watch                     bool         `json:"watch"`
+	SearchFuzziness           int          `json:"searchFuzziness"` // Edit distance within which search terms match
+	StrictNames               bool         `json:"strictNames"`     // Whether skills named differently from their directory are skipped
+	ReadOnly                  bool         `json:"readOnly"`        // Whether writes to the skills directory are refused
+	MCPTransport              string       `json:"mcpTransport"`
+	MCPPath                   string       `json:"mcpPath,omitempty"` // Empty with the stdio transport
+	MCPScopes                 []string     `json:"mcpScopes"`
+	CORSOrigins               []string     `json:"corsOrigins"`               // Empty when CORS is disabled
+	AllowedResourceExtensions []string     `json:"allowedResourceExtensions"` // Empty allows all extensions
+	DeniedResourceExtensions  []string     `json:"deniedResourceExtensions"`
+	ImportDirMode             string       `json:"importDirMode"`
+	ImportFileMode            string       `json:"importFileMode"`
+	ResourceDirs              string       `json:"resourceDirs"` // Resource directories as directory=type pairs
+	Limits                    ConfigLimits `json:"limits"`
+	Auth                      bool         `json:"auth"`      // Whether API authentication is enabled
+	AuthReads                 bool         `json:"authReads"` // Whether reads require the API key too
+}
+
+// ConfigLimits are the size and count limits enforced by the API
+type ConfigLimits struct {
+	MaxResourceSize  int `json:"maxResourceSize"`
+	MaxArchiveSize   int `json:"maxArchiveSize"`
+	MaxHistoryLimit  int `json:"maxHistoryLimit"`
+	MaxPageSize      int `json:"maxPageSize"`
+	MaxThumbnailSize int `json:"maxThumbnailSize"`
+	MaxMCPExportSize int `json:"maxMcpExportSize"`
+	MaxMCPReadSize   int `json:"maxMcpReadSize"`
+	MaxContentLength int `json:"maxContentLength"` // Longest SKILL.md body, in bytes (0 = unlimited)
+
+	RateLimit float64 `json:"rateLimit"` // Requests per second allowed per client IP (0 = unlimited)
+
+	MaxImportExtractedSize int64 `json:"maxImportExtractedSize"`
+	MaxImportEntries       int   `json:"maxImportEntries"`
+
+	MaxResourceArchiveFileSize int64 `json:"maxResourceArchiveFileSize"` // Largest file in an uploaded resource archive
+}
+
+// getConfig returns the resolved configuration, without secrets
+func (s *Server) getConfig(c *echo.Context) error {
+	config := ConfigResponse{
+		Host:                      s.runtime.Host,
+		Port:                      s.runtime.Port,
+		TLS:                       s.runtime.TLS,
+		GitRepos:                  []string{},
+		GitProxy:                  git.RedactURL(s.runtime.GitProxy),
+		SyncInterval:              git.SyncInterval.String(),
+		ShutdownTimeout:           s.runtime.ShutdownTimeout.String(),
+		EnableLogging:             s.runtime.EnableLogging,
+		LogLevel:                  s.runtime.LogLevel,
+		Watch:                     s.runtime.Watch,
+		MCPTransport:              s.runtime.MCPTransport,
+		MCPPath:                   s.runtime.MCPPath,
+		MCPScopes:                 append([]string{}, s.runtime.MCPScopes...),
+		CORSOrigins:               append([]string{}, s.runtime.CORSOrigins...),
+		AllowedResourceExtensions: append([]string{}, s.importOptions.Extensions.Allowed...),
+		DeniedResourceExtensions:  append([]string{}, s.importOptions.Extensions.Denied...),
+		ImportDirMode:             fmt.Sprintf("%04o", s.importOptions.DirMode.Perm()),
+		ImportFileMode:            fmt.Sprintf("%04o", s.importOptions.FileMode.Perm()),
+		ResourceDirs:              s.resourceDirs().String(),
+		Limits: ConfigLimits{
+			MaxResourceSize:  s.maxResourceSize,
+			MaxArchiveSize:   s.maxArchiveSize,
+			MaxHistoryLimit:  maxHistoryLimit,
+			MaxPageSize:      maxPageSize,
+			MaxThumbnailSize: domain.MaxThumbnailSize,
+			MaxMCPExportSize: s.runtime.MCPMaxExportSize,
+			MaxMCPReadSize:   s.runtime.MCPMaxReadSize,
+			MaxContentLength: s.maxContentLength(),
+
+			RateLimit: s.rateLimit,
+
+			MaxImportExtractedSize: s.importOptions.MaxExtractedSize,
+			MaxImportEntries:       s.importOptions.MaxEntries,
+
+			MaxResourceArchiveFileSize: int64(s.maxResourceSize),
+		},
+	}
+	config.Auth = s.apiKey != ""
+	config.AuthReads = config.Auth && s.apiKeyForReads
+	config.ReadOnly = s.readOnly
+	if s.fsManager != nil {
+		config.Dir = s.fsManager.GetSkillsDir()
+		config.IndexDir = s.fsManager.GetIndexDir()
+		config.OverlayDir = s.fsManager.OverlayDir()
+		config.SearchFuzziness = s.fsManager.SearchFuzziness()
+		config.StrictNames = s.fsManager.StrictNames()
+	}
+	config.SyncTimeout = git.DefaultSyncTimeout.String()
+	config.SyncConcurrency = git.DefaultSyncConcurrency
+	if s.gitSyncer != nil {
+		config.SyncTimeout = s.gitSyncer.SyncTimeout().String()
+		config.SyncConcurrency = s.gitSyncer.SyncConcurrency()
+		for _, repo := range s.gitSyncer.GetRepos() {
+			config.GitRepos = append(config.GitRepos, git.RedactURL(repo))
+		}
+	}
+
+	return c.JSON(http.StatusOK, config)
+}
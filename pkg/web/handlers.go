@@ -1,12 +1,17 @@
 package web
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,27 +21,90 @@ import (
 	"github.com/mudler/skillserver/pkg/git"
 )
 
+const (
+	// defaultHistoryLimit is the number of commits returned by the history endpoint by default
+	defaultHistoryLimit = 20
+	// maxHistoryLimit is the maximum number of commits the history endpoint returns
+	maxHistoryLimit = 100
+	// syncAllTimeout bounds how long the sync-all endpoint waits for the repositories to sync
+	syncAllTimeout = 5 * time.Minute
+	// defaultPageSize is the number of skills returned by the list endpoint by default
+	defaultPageSize = 50
+	// maxPageSize is the maximum number of skills the list endpoint returns
+	maxPageSize = 500
+	// searchStatusHeader is the response header of search requests carrying the search status
+	searchStatusHeader = "X-Search-Status"
+	// searchSnippetLength is the number of characters of skill content included in search snippets
+	searchSnippetLength = 200
+	// uncompressedSizeHeader is the export response header carrying the total size of the archived files
+	uncompressedSizeHeader = "X-Uncompressed-Size"
+	// fileCountHeader is the export response header carrying the number of archived files
+	fileCountHeader = "X-File-Count"
+	// markdownContentType is the content type of skills served as markdown
+	markdownContentType = "text/markdown; charset=utf-8"
+	// thumbnailSuffix is appended to a resource path to request its thumbnail
+	thumbnailSuffix = "thumbnail"
+)
+
+// SkillListResponse is a page of skills returned by the list endpoint
+type SkillListResponse struct {
+	Items      []SkillResponse `json:"items"`
+	Total      int             `json:"total"`       // Number of skills matching the filters, across all pages
+	NextOffset *int            `json:"next_offset"` // Offset of the next page, null on the last page
+}
+
 // SkillResponse represents a skill in API responses
 type SkillResponse struct {
-	Name          string            `json:"name"`
-	Content       string            `json:"content"`
-	Description   string            `json:"description,omitempty"`
-	License       string            `json:"license,omitempty"`
-	Compatibility string            `json:"compatibility,omitempty"`
-	Metadata      map[string]string `json:"metadata,omitempty"`
-	AllowedTools  string            `json:"allowed-tools,omitempty"`
-	ReadOnly      bool              `json:"readOnly"`
+	ID               string            `json:"id"` // Stable ID if set in frontmatter, otherwise the same as name
+	Name             string            `json:"name"`
+	Content          string            `json:"content,omitempty"`
+	Snippet          string            `json:"snippet,omitempty"` // Only set in search results
+	Score            float64           `json:"score,omitempty"`   // Only set in search results served by the index
+	Description      string            `json:"description,omitempty"`
+	License          string            `json:"license,omitempty"`
+	Compatibility    string            `json:"compatibility,omitempty"`
+	Version          string            `json:"version,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+	AllowedTools     string            `json:"allowed-tools,omitempty"`
+	AllowedToolsList []string          `json:"allowedToolsList,omitempty"` // allowed-tools split into tool names
+	Extra            map[string]any    `json:"extra,omitempty"`            // Author-defined frontmatter keys, e.g. "author"
+	Icon             string            `json:"icon,omitempty"`
+	Color            string            `json:"color,omitempty"`
+	ReadOnly         bool              `json:"readOnly"`
+	Created          time.Time         `json:"created"`             // Creation time of the skill directory
+	Modified         time.Time         `json:"modified"`            // Last modification time of SKILL.md
+	Overlaid         bool              `json:"overlaid,omitempty"`  // Some files of the git repository skill are shadowed by the local overlay
+	Ambiguous        bool              `json:"ambiguous,omitempty"` // Only set in listings: another skill has the same directory name or ID
+	ContentLength    int               `json:"contentLength"`       // Size of the content in bytes
+	WordCount        int               `json:"wordCount"`
+	TokenEstimate    int               `json:"tokenEstimate"` // Approximate number of LLM tokens in the content
 }
 
 // CreateSkillRequest represents a request to create a skill
 type CreateSkillRequest struct {
+	ID            string            `json:"id,omitempty"` // Optional stable ID
 	Name          string            `json:"name"`
 	Description   string            `json:"description"`
 	Content       string            `json:"content"`
 	License       string            `json:"license,omitempty"`
 	Compatibility string            `json:"compatibility,omitempty"`
+	Version       string            `json:"version,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
 	Metadata      map[string]string `json:"metadata,omitempty"`
 	AllowedTools  string            `json:"allowed-tools,omitempty"`
+	Icon          string            `json:"icon,omitempty"`
+	Color         string            `json:"color,omitempty"`
+}
+
+// CopySkillRequest represents a request to copy a skill to a new local skill
+type CopySkillRequest struct {
+	Name string `json:"name"` // Name of the copy
+}
+
+// RenameSkillRequest represents a request to rename a local skill
+type RenameSkillRequest struct {
+	Name string `json:"name"` // New name of the skill
 }
 
 // UpdateSkillRequest represents a request to update a skill
@@ -45,41 +113,241 @@ type UpdateSkillRequest struct {
 	Content       string            `json:"content"`
 	License       string            `json:"license,omitempty"`
 	Compatibility string            `json:"compatibility,omitempty"`
+	Version       string            `json:"version,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
 	Metadata      map[string]string `json:"metadata,omitempty"`
 	AllowedTools  string            `json:"allowed-tools,omitempty"`
+	Icon          string            `json:"icon,omitempty"`
+	Color         string            `json:"color,omitempty"`
+}
+
+// validateAllowedTools validates the allowed-tools field of a skill, logging tools listed more than once
+func (s *Server) validateAllowedTools(name, allowedTools string) error {
+	_, duplicates, err := domain.ParseAllowedTools(allowedTools)
+	if err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		s.logf(slog.LevelWarn, "skill %s lists allowed tools more than once: %s", name, strings.Join(duplicates, ", "))
+	}
+	return nil
+}
+
+// freshRead handles the ?fresh=true query parameter by dropping any cached copy of skillID
+// (or of every skill if skillID is empty) so the following read comes from disk
+func (s *Server) freshRead(c *echo.Context, skillID string) error {
+	param := c.QueryParam("fresh")
+	if param == "" {
+		return nil
+	}
+	fresh, err := strconv.ParseBool(param)
+	if err != nil {
+		return fmt.Errorf("query parameter 'fresh' must be a boolean")
+	}
+	if invalidator, ok := s.skillManager.(domain.CacheInvalidator); ok && fresh {
+		invalidator.InvalidateCache(skillID)
+	}
+	return nil
+}
+
+// parseModifiedRange parses the modifiedAfter and modifiedBefore query parameters
+// Each accepts an RFC3339 timestamp or a duration before now such as "7d"
+func parseModifiedRange(c *echo.Context) (domain.ModifiedRange, error) {
+	var modified domain.ModifiedRange
+	now := time.Now()
+	if param := c.QueryParam("modifiedAfter"); param != "" {
+		t, err := domain.ParseTimeFilter(param, now)
+		if err != nil {
+			return modified, fmt.Errorf("modifiedAfter: %w", err)
+		}
+		modified.After = t
+	}
+	if param := c.QueryParam("modifiedBefore"); param != "" {
+		t, err := domain.ParseTimeFilter(param, now)
+		if err != nil {
+			return modified, fmt.Errorf("modifiedBefore: %w", err)
+		}
+		modified.Before = t
+	}
+	return modified, nil
+}
+
+// parseTagFilter parses the tag and tagMatch query parameters
+// tag may be repeated or hold a comma-separated list; tagMatch is "any" (the default) or "all"
+func parseTagFilter(c *echo.Context) (domain.TagFilter, error) {
+	var filter domain.TagFilter
+	for _, param := range c.QueryParams()["tag"] {
+		for _, tag := range strings.Split(param, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+	switch c.QueryParam("tagMatch") {
+	case "", "any":
+	case "all":
+		filter.MatchAll = true
+	default:
+		return filter, fmt.Errorf("tagMatch must be \"any\" or \"all\"")
+	}
+	return filter, nil
 }
 
 // listSkills lists all skills
 func (s *Server) listSkills(c *echo.Context) error {
+	if err := s.freshRead(c, ""); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	modified, err := parseModifiedRange(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	tags, err := parseTagFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	order, err := domain.ParseSkillSort(c.QueryParam("sort"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	limit := defaultPageSize
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("limit must be between 1 and %d", maxPageSize),
+			})
+		}
+	}
+	includeContent := true
+	if param := c.QueryParam("content"); param != "" {
+		includeContent, err = strconv.ParseBool(param)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "query parameter 'content' must be a boolean",
+			})
+		}
+	}
+	offset := 0
+	if offsetParam := c.QueryParam("offset"); offsetParam != "" {
+		offset, err = strconv.Atoi(offsetParam)
+		if err != nil || offset < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "offset must be a non-negative integer",
+			})
+		}
+	}
+
 	skills, err := s.skillManager.ListSkills()
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
 		})
 	}
+	skills = domain.FilterModified(skills, modified)
+	skills = domain.FilterTags(skills, tags)
+	domain.SortSkills(skills, order)
+
+	total := len(skills)
+	var nextOffset *int
+	if end := offset + limit; end < total {
+		skills = skills[offset:end]
+		nextOffset = &end
+	} else if offset < total {
+		skills = skills[offset:]
+	} else {
+		skills = nil
+	}
 
 	responses := make([]SkillResponse, len(skills))
 	for i, skill := range skills {
-		responses[i] = SkillResponse{
-			Name:     skill.Name,
-			Content:  skill.Content,
-			ReadOnly: skill.ReadOnly,
+		responses[i] = skillResponse(skill, includeContent)
+	}
+
+	return c.JSON(http.StatusOK, SkillListResponse{
+		Items:      responses,
+		Total:      total,
+		NextOffset: nextOffset,
+	})
+}
+
+// skillResponse converts a skill to its API representation, with its content if includeContent is set
+func skillResponse(skill domain.Skill, includeContent bool) SkillResponse {
+	response := SkillResponse{
+		ID:            skill.ID,
+		Name:          skill.Name,
+		ReadOnly:      skill.ReadOnly,
+		Overlaid:      skill.Overlaid,
+		Created:       skill.Created,
+		Modified:      skill.Modified,
+		Ambiguous:     skill.Ambiguous,
+		ContentLength: skill.ContentLength,
+		WordCount:     skill.WordCount,
+		TokenEstimate: skill.TokenEstimate,
+	}
+	if includeContent {
+		response.Content = skill.Content
+	}
+	if skill.Metadata != nil {
+		response.Description = skill.Metadata.Description
+		response.License = skill.Metadata.License
+		response.Compatibility = skill.Metadata.Compatibility
+		response.Version = skill.Metadata.Version
+		response.Tags = skill.Metadata.Tags
+		response.Metadata = skill.Metadata.Metadata
+		response.AllowedTools = skill.Metadata.AllowedTools
+		response.AllowedToolsList = skill.Metadata.AllowedToolsList()
+		response.Extra = skill.Metadata.Extra
+		response.Icon = skill.Metadata.Icon
+		response.Color = skill.Metadata.Color
+	}
+	return response
+}
+
+// prefersMarkdown reports whether an Accept header prefers text/markdown over JSON
+// Without an Accept header, or when both are equally acceptable, JSON is preferred
+func prefersMarkdown(accept string) bool {
+	markdownQ, jsonQ := 0.0, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
 		}
-		if skill.Metadata != nil {
-			responses[i].Description = skill.Metadata.Description
-			responses[i].License = skill.Metadata.License
-			responses[i].Compatibility = skill.Metadata.Compatibility
-			responses[i].Metadata = skill.Metadata.Metadata
-			responses[i].AllowedTools = skill.Metadata.AllowedTools
+		switch mediaType {
+		case "text/markdown", "text/*":
+			markdownQ = max(markdownQ, q)
+		case "application/json", "application/*", "*/*":
+			jsonQ = max(jsonQ, q)
 		}
 	}
-
-	return c.JSON(http.StatusOK, responses)
+	return markdownQ > jsonQ
 }
 
 // getSkill gets a single skill by name
 func (s *Server) getSkill(c *echo.Context) error {
 	name := c.Param("name")
+	if err := s.freshRead(c, name); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
 	skill, err := s.skillManager.ReadSkill(name)
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{
@@ -87,20 +355,23 @@ func (s *Server) getSkill(c *echo.Context) error {
 		})
 	}
 
-	response := SkillResponse{
-		Name:     skill.Name,
-		Content:  skill.Content,
-		ReadOnly: skill.ReadOnly,
-	}
-	if skill.Metadata != nil {
-		response.Description = skill.Metadata.Description
-		response.License = skill.Metadata.License
-		response.Compatibility = skill.Metadata.Compatibility
-		response.Metadata = skill.Metadata.Metadata
-		response.AllowedTools = skill.Metadata.AllowedTools
+	// Serve the SKILL.md file itself to clients asking for markdown
+	c.Response().Header().Add("Vary", "Accept")
+	if prefersMarkdown(c.Request().Header.Get("Accept")) {
+		skillMdPath := filepath.Join(skill.SourcePath, "SKILL.md")
+		if s.fsManager != nil {
+			skillMdPath = s.fsManager.SkillMdPath(skill)
+		}
+		content, err := os.ReadFile(skillMdPath)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.Blob(http.StatusOK, markdownContentType, content)
 	}
 
-	return c.JSON(http.StatusOK, response)
+	return c.JSON(http.StatusOK, skillResponse(*skill, true))
 }
 
 // createSkill creates a new skill
@@ -114,37 +385,82 @@ func (s *Server) createSkill(c *echo.Context) error {
 
 	// Validate name
 	if req.Name == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": "name is required",
 		})
 	}
 
 	// Validate name according to Agent Skills spec
 	if err := domain.ValidateSkillName(req.Name); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Validate stable ID if provided
+	if err := domain.ValidateSkillID(req.ID); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": err.Error(),
 		})
 	}
 
 	// Validate description
 	if req.Description == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": "description is required",
 		})
 	}
 	if len(req.Description) > 1024 {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": "description must be 1-1024 characters",
 		})
 	}
 
 	// Validate compatibility if provided
 	if req.Compatibility != "" && len(req.Compatibility) > 500 {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": "compatibility must be max 500 characters",
 		})
 	}
 
+	// Validate version and tags if provided
+	if err := domain.ValidateSkillVersion(req.Version); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err := domain.ValidateSkillTags(req.Tags); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Validate allowed tools if provided
+	if err := s.validateAllowedTools(req.Name, req.AllowedTools); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Validate presentation hints if provided
+	if err := domain.ValidateSkillIcon(req.Icon); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err := domain.ValidateSkillColor(req.Color); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Refuse content the skill could not be read back with
+	if err := domain.ValidateContentLength(req.Content, s.maxContentLength()); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
 	// Get the skills directory from the manager
 	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
 	if !ok {
@@ -163,23 +479,25 @@ func (s *Server) createSkill(c *echo.Context) error {
 	}
 
 	// Build frontmatter
-	frontmatter := fmt.Sprintf("---\nname: %s\ndescription: %s\n", req.Name, req.Description)
-	if req.License != "" {
-		frontmatter += fmt.Sprintf("license: %s\n", req.License)
-	}
-	if req.Compatibility != "" {
-		frontmatter += fmt.Sprintf("compatibility: %s\n", req.Compatibility)
-	}
-	if len(req.Metadata) > 0 {
-		frontmatter += "metadata:\n"
-		for k, v := range req.Metadata {
-			frontmatter += fmt.Sprintf("  %s: %s\n", k, v)
-		}
-	}
-	if req.AllowedTools != "" {
-		frontmatter += fmt.Sprintf("allowed-tools: %s\n", req.AllowedTools)
+	frontmatter, err := domain.FormatFrontmatter(&domain.SkillMetadata{
+		ID:            req.ID,
+		Name:          req.Name,
+		Description:   req.Description,
+		License:       req.License,
+		Compatibility: req.Compatibility,
+		Version:       req.Version,
+		Tags:          req.Tags,
+		Metadata:      req.Metadata,
+		AllowedTools:  req.AllowedTools,
+		Icon:          req.Icon,
+		Color:         req.Color,
+	})
+	if err != nil {
+		os.RemoveAll(skillDir) // Clean up on error
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
 	}
-	frontmatter += "---\n\n"
 
 	// Write SKILL.md file
 	skillMdPath := filepath.Join(skillDir, "SKILL.md")
@@ -191,10 +509,10 @@ func (s *Server) createSkill(c *echo.Context) error {
 		})
 	}
 
-	// Rebuild index
-	if err := s.skillManager.RebuildIndex(); err != nil {
+	// Index the skill
+	if err := s.reindexSkill(req.Name); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "failed to rebuild index",
+			"error": "failed to update index",
 		})
 	}
 
@@ -205,20 +523,114 @@ func (s *Server) createSkill(c *echo.Context) error {
 		})
 	}
 
-	response := SkillResponse{
-		Name:     skill.Name,
-		Content:  skill.Content,
-		ReadOnly: skill.ReadOnly,
+	return c.JSON(http.StatusCreated, skillResponse(*skill, true))
+}
+
+// copySkill copies a skill, local or from a git repository, to a new editable local skill
+func (s *Server) copySkill(c *echo.Context) error {
+	var req CopySkillRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request",
+		})
 	}
-	if skill.Metadata != nil {
-		response.Description = skill.Metadata.Description
-		response.License = skill.Metadata.License
-		response.Compatibility = skill.Metadata.Compatibility
-		response.Metadata = skill.Metadata.Metadata
-		response.AllowedTools = skill.Metadata.AllowedTools
+	if err := domain.ValidateSkillName(req.Name); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
 	}
 
-	return c.JSON(http.StatusCreated, response)
+	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "unsupported manager type",
+		})
+	}
+	if _, err := s.skillManager.ReadSkill(c.Param("name")); err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "skill not found",
+		})
+	}
+
+	skill, err := fsManager.CopySkill(c.Param("name"), req.Name)
+	if errors.Is(err, domain.ErrSkillExists) {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Index the copy
+	if err := s.reindexSkill(skill.Name); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to update index",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, skillResponse(*skill, true))
+}
+
+// renameSkill renames a local skill, keeping its resources
+func (s *Server) renameSkill(c *echo.Context) error {
+	var req RenameSkillRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request",
+		})
+	}
+	if err := domain.ValidateSkillName(req.Name); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "unsupported manager type",
+		})
+	}
+	existingSkill, err := s.skillManager.ReadSkill(c.Param("name"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "skill not found",
+		})
+	}
+	if existingSkill.ReadOnly {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": domain.ErrRenameReadOnly.Error(),
+		})
+	}
+
+	skill, err := fsManager.RenameSkill(existingSkill.ID, req.Name)
+	if errors.Is(err, domain.ErrSkillExists) {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Replace the old index entry with the renamed skill
+	if err := fsManager.RemoveSkillIndex(*existingSkill); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to update index",
+		})
+	}
+	if err := s.reindexSkill(skill.ID); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to update index",
+		})
+	}
+
+	return c.JSON(http.StatusOK, skillResponse(*skill, true))
 }
 
 // updateSkill updates an existing skill
@@ -232,11 +644,13 @@ func (s *Server) updateSkill(c *echo.Context) error {
 			"error": "skill not found",
 		})
 	}
-	if existingSkill.ReadOnly {
+	if existingSkill.ReadOnly && !s.overlayEnabled() {
 		return c.JSON(http.StatusForbidden, map[string]string{
 			"error": "cannot update read-only skill from git repository",
 		})
 	}
+	// The request may address the skill by its stable ID, the directory is named after the skill
+	name = existingSkill.Name
 
 	var req UpdateSkillRequest
 	if err := c.Bind(&req); err != nil {
@@ -247,23 +661,61 @@ func (s *Server) updateSkill(c *echo.Context) error {
 
 	// Validate description
 	if req.Description == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": "description is required",
 		})
 	}
 	if len(req.Description) > 1024 {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": "description must be 1-1024 characters",
 		})
 	}
 
 	// Validate compatibility if provided
 	if req.Compatibility != "" && len(req.Compatibility) > 500 {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": "compatibility must be max 500 characters",
 		})
 	}
 
+	// Validate version and tags if provided
+	if err := domain.ValidateSkillVersion(req.Version); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err := domain.ValidateSkillTags(req.Tags); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Validate allowed tools if provided
+	if err := s.validateAllowedTools(name, req.AllowedTools); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Validate presentation hints if provided
+	if err := domain.ValidateSkillIcon(req.Icon); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	if err := domain.ValidateSkillColor(req.Color); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Refuse content the skill could not be read back with
+	if err := domain.ValidateContentLength(req.Content, s.maxContentLength()); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
 	// Get the skills directory from the manager
 	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
 	if !ok {
@@ -272,39 +724,52 @@ func (s *Server) updateSkill(c *echo.Context) error {
 		})
 	}
 
-	// Build frontmatter (name must match directory name)
-	skillDir := filepath.Join(fsManager.GetSkillsDir(), name)
-	frontmatter := fmt.Sprintf("---\nname: %s\ndescription: %s\n", name, req.Description)
-	if req.License != "" {
-		frontmatter += fmt.Sprintf("license: %s\n", req.License)
-	}
-	if req.Compatibility != "" {
-		frontmatter += fmt.Sprintf("compatibility: %s\n", req.Compatibility)
-	}
-	if len(req.Metadata) > 0 {
-		frontmatter += "metadata:\n"
-		for k, v := range req.Metadata {
-			frontmatter += fmt.Sprintf("  %s: %s\n", k, v)
-		}
+	// Edit the frontmatter in place, so keys the request has no fields for (such as the stable ID and
+	// author-defined keys), their order and comments are kept (the name must stay the directory name)
+	// Git repository skills are edited in the local overlay, starting from the git SKILL.md
+	skillDir, err := fsManager.WritableSkillDir(existingSkill)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
 	}
-	if req.AllowedTools != "" {
-		frontmatter += fmt.Sprintf("allowed-tools: %s\n", req.AllowedTools)
+	skillMdPath := filepath.Join(skillDir, "SKILL.md")
+	existingContent, err := os.ReadFile(fsManager.SkillMdPath(existingSkill))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read skill: %v", err),
+		})
+	}
+	fields := []domain.FrontmatterField{
+		{Key: "name", Value: existingSkill.Metadata.Name},
+		{Key: "description", Value: req.Description},
+		{Key: "license", Value: optionalField(req.License)},
+		{Key: "compatibility", Value: optionalField(req.Compatibility)},
+		{Key: "version", Value: optionalField(req.Version)},
+		{Key: "tags", Value: optionalList(req.Tags)},
+		{Key: "metadata", Value: optionalMap(req.Metadata)},
+		{Key: "allowed-tools", Value: optionalField(req.AllowedTools)},
+		{Key: "icon", Value: optionalField(req.Icon)},
+		{Key: "color", Value: optionalField(req.Color)},
+	}
+	fullContent, err := domain.UpdateFrontmatter(string(existingContent), req.Content, fields)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
 	}
-	frontmatter += "---\n\n"
 
 	// Write SKILL.md file
-	skillMdPath := filepath.Join(skillDir, "SKILL.md")
-	fullContent := frontmatter + req.Content
 	if err := writeFile(skillMdPath, fullContent); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
 		})
 	}
 
-	// Rebuild index
-	if err := s.skillManager.RebuildIndex(); err != nil {
+	// Index the skill
+	if err := s.reindexSkill(name); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "failed to rebuild index",
+			"error": "failed to update index",
 		})
 	}
 
@@ -315,40 +780,195 @@ func (s *Server) updateSkill(c *echo.Context) error {
 		})
 	}
 
-	response := SkillResponse{
-		Name:     skill.Name,
-		Content:  skill.Content,
-		ReadOnly: skill.ReadOnly,
+	return c.JSON(http.StatusOK, skillResponse(*skill, true))
+}
+
+// removeSkill deletes a local skill directory, removing it from the index unless reindex is false,
+// in which case the caller rebuilds the index afterwards
+// Returns the HTTP status to report on failure
+func (s *Server) removeSkill(name string, reindex bool) (int, error) {
+	// Check if skill exists and is read-only
+	existingSkill, err := s.skillManager.ReadSkill(name)
+	if err != nil {
+		return http.StatusNotFound, fmt.Errorf("skill not found")
 	}
-	if skill.Metadata != nil {
-		response.Description = skill.Metadata.Description
-		response.License = skill.Metadata.License
-		response.Compatibility = skill.Metadata.Compatibility
-		response.Metadata = skill.Metadata.Metadata
-		response.AllowedTools = skill.Metadata.AllowedTools
+	if existingSkill.ReadOnly {
+		return http.StatusForbidden, fmt.Errorf("cannot delete read-only skill from git repository")
 	}
 
-	return c.JSON(http.StatusOK, response)
+	// Get the skills directory from the manager
+	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
+	if !ok {
+		return http.StatusInternalServerError, fmt.Errorf("unsupported manager type")
+	}
+
+	// Delete the skill directory
+	skillsDir := fsManager.GetSkillsDir()
+	skillDir := filepath.Join(skillsDir, existingSkill.Name)
+	if err := os.RemoveAll(skillDir); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !reindex {
+		return http.StatusNoContent, nil
+	}
+	if err := fsManager.RemoveSkillIndex(*existingSkill); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to update index")
+	}
+
+	return http.StatusNoContent, nil
+}
+
+// reindexSkill updates the index entry of a created or updated skill
+func (s *Server) reindexSkill(name string) error {
+	if fsManager, ok := s.skillManager.(*domain.FileSystemManager); ok {
+		return fsManager.UpdateSkillIndex(name)
+	}
+	return s.skillManager.RebuildIndex()
 }
 
 // deleteSkill deletes a skill
 func (s *Server) deleteSkill(c *echo.Context) error {
 	name := c.Param("name")
 
-	// Check if skill exists and is read-only
-	existingSkill, err := s.skillManager.ReadSkill(name)
+	if status, err := s.removeSkill(name, true); err != nil {
+		return c.JSON(status, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// BulkDeleteRequest represents a request to delete several skills at once
+type BulkDeleteRequest struct {
+	Names []string `json:"names"`
+}
+
+// BulkDeleteResult represents the outcome of deleting a single skill in a bulk delete
+type BulkDeleteResult struct {
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted"`
+	Status  int    `json:"status"` // HTTP status the single-skill DELETE would have returned
+	Error   string `json:"error,omitempty"`
+}
+
+// bulkDeleteSkills deletes several skills, rebuilding the index once at the end
+func (s *Server) bulkDeleteSkills(c *echo.Context) error {
+	var req BulkDeleteRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request",
+		})
+	}
+	if len(req.Names) == 0 {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": "names is required",
+		})
+	}
+
+	results := make([]BulkDeleteResult, 0, len(req.Names))
+	deleted := 0
+	for _, name := range req.Names {
+		status, err := s.removeSkill(name, false)
+		result := BulkDeleteResult{Name: name, Status: status, Deleted: err == nil}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			deleted++
+		}
+		results = append(results, result)
+	}
+
+	if deleted > 0 {
+		if err := s.skillManager.RebuildIndex(); err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": fmt.Sprintf("failed to rebuild index: %v", err),
+			})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"deleted": deleted,
+		"results": results,
+	})
+}
+
+// searchSkills searches for skills
+func (s *Server) searchSkills(c *echo.Context) error {
+	query := c.QueryParam("q")
+	if query == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "query parameter 'q' is required",
+		})
+	}
+
+	includeContent := false
+	if param := c.QueryParam("includeContent"); param != "" {
+		var err error
+		includeContent, err = strconv.ParseBool(param)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "query parameter 'includeContent' must be a boolean",
+			})
+		}
+	}
+
+	modified, err := parseModifiedRange(c)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, map[string]string{
-			"error": "skill not found",
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
 		})
 	}
-	if existingSkill.ReadOnly {
-		return c.JSON(http.StatusForbidden, map[string]string{
-			"error": "cannot delete read-only skill from git repository",
+
+	tags, err := parseTagFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
 		})
 	}
 
-	// Get the skills directory from the manager
+	opts := domain.SearchOptions{Modified: modified, Tags: tags}
+	if param := c.QueryParam("fields"); param != "" {
+		for _, field := range strings.Split(param, ",") {
+			opts.Fields = append(opts.Fields, strings.TrimSpace(field))
+		}
+		if err := opts.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	var skills []domain.Skill
+	if fsManager, ok := s.skillManager.(*domain.FileSystemManager); ok {
+		skills, err = fsManager.SearchSkillsWithOptions(query, opts)
+	} else {
+		skills, err = s.skillManager.SearchSkills(query)
+		skills = domain.FilterTags(domain.FilterModified(skills, modified), tags)
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Let clients know when results come from the fallback scan rather than the index
+	if fsManager, ok := s.skillManager.(*domain.FileSystemManager); ok {
+		c.Response().Header().Set(searchStatusHeader, string(fsManager.SearchStatus().Status))
+	}
+
+	responses := make([]SkillResponse, len(skills))
+	for i, skill := range skills {
+		responses[i] = skillResponse(skill, includeContent)
+		responses[i].Snippet = domain.SearchSnippet(skill, searchSnippetLength)
+		responses[i].Score = skill.Score
+	}
+
+	return c.JSON(http.StatusOK, responses)
+}
+
+// getSearchStatus reports whether search is served by the index or degraded
+func (s *Server) getSearchStatus(c *echo.Context) error {
 	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
 	if !ok {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -356,58 +976,168 @@ func (s *Server) deleteSkill(c *echo.Context) error {
 		})
 	}
 
-	// Delete the skill directory
-	skillsDir := fsManager.GetSkillsDir()
-	skillDir := filepath.Join(skillsDir, name)
-	if err := os.RemoveAll(skillDir); err != nil {
+	return c.JSON(http.StatusOK, fsManager.SearchStatus())
+}
+
+// SkillIssueResponse represents a skill left out of listings in API responses
+type SkillIssueResponse struct {
+	Path  string `json:"path"`  // Skill directory, relative to the skills directory
+	Name  string `json:"name"`  // Name the skill would have been listed under
+	Error string `json:"error"` // Why the skill was skipped, e.g. a frontmatter name not matching the directory
+}
+
+// listSkillIssues lists the skills that cannot be read, so authors can find out why a skill is missing
+func (s *Server) listSkillIssues(c *echo.Context) error {
+	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "unsupported manager type",
+		})
+	}
+
+	issues, err := fsManager.SkillIssues()
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
 		})
 	}
 
-	// Rebuild index
+	responses := make([]SkillIssueResponse, len(issues))
+	for i, issue := range issues {
+		responses[i] = SkillIssueResponse{Path: issue.Path, Name: issue.Name, Error: issue.Error}
+	}
+	return c.JSON(http.StatusOK, responses)
+}
+
+// StatsResponse represents server statistics in API responses
+type StatsResponse struct {
+	Skills        int                     `json:"skills"`
+	LocalSkills   int                     `json:"local_skills"`
+	GitSkills     int                     `json:"git_skills"`
+	GitRepos      int                     `json:"git_repos"`
+	RepoSkills    map[string]int          `json:"repo_skills"` // Number of skills per git repository
+	Resources     int                     `json:"resources"`
+	ResourceBytes int64                   `json:"resource_bytes"` // Total size of the resources of every skill
+	Search        domain.SearchStatusInfo `json:"search"`
+}
+
+// getStats returns server statistics
+func (s *Server) getStats(c *echo.Context) error {
+	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "unsupported manager type",
+		})
+	}
+
+	skillStats, err := domain.CollectStats(s.skillManager)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	stats := StatsResponse{
+		Skills:        skillStats.Skills,
+		LocalSkills:   skillStats.LocalSkills,
+		GitSkills:     skillStats.GitSkills,
+		GitRepos:      len(skillStats.RepoSkills),
+		RepoSkills:    skillStats.RepoSkills,
+		Resources:     skillStats.Resources,
+		ResourceBytes: skillStats.ResourceBytes,
+		Search:        fsManager.SearchStatus(),
+	}
+	// Count configured repositories, including those without skills
+	if s.gitSyncer != nil {
+		stats.GitRepos = len(s.gitSyncer.GetRepos())
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// ReindexResponse reports the outcome of a full rebuild of the search index
+type ReindexResponse struct {
+	Skills   int    `json:"skills"`   // Number of skills indexed
+	Duration string `json:"duration"` // How long the rebuild took
+}
+
+// reindex rebuilds the search index from the skills directory, e.g. after editing it by hand
+func (s *Server) reindex(c *echo.Context) error {
+	start := time.Now()
 	if err := s.skillManager.RebuildIndex(); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "failed to rebuild index",
+			"error": fmt.Sprintf("failed to rebuild index: %v", err),
 		})
 	}
+	duration := time.Since(start)
 
-	return c.NoContent(http.StatusNoContent)
+	skills, err := s.skillManager.ListSkills()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, ReindexResponse{
+		Skills:   len(skills),
+		Duration: duration.String(),
+	})
 }
 
-// searchSkills searches for skills
-func (s *Server) searchSkills(c *echo.Context) error {
-	query := c.QueryParam("q")
-	if query == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": "query parameter 'q' is required",
+// getSkillHistory returns the recent upstream commits touching a git repo skill
+func (s *Server) getSkillHistory(c *echo.Context) error {
+	name := c.Param("name")
+	skill, err := s.skillManager.ReadSkill(name)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "skill not found",
+		})
+	}
+
+	limit := defaultHistoryLimit
+	if limitParam := c.QueryParam("limit"); limitParam != "" {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit < 1 || limit > maxHistoryLimit {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("limit must be between 1 and %d", maxHistoryLimit),
+			})
+		}
+	}
+
+	// Local skills are not versioned
+	repoName := domain.SkillRepo(*skill)
+	if repoName == "" {
+		return c.JSON(http.StatusOK, []git.CommitInfo{})
+	}
+
+	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "unsupported manager type",
 		})
 	}
 
-	skills, err := s.skillManager.SearchSkills(query)
+	repoDir := filepath.Join(fsManager.GetSkillsDir(), repoName)
+	skillPath, err := filepath.Rel(repoDir, skill.SourcePath)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
 		})
 	}
 
-	responses := make([]SkillResponse, len(skills))
-	for i, skill := range skills {
-		responses[i] = SkillResponse{
-			Name:     skill.Name,
-			Content:  skill.Content,
-			ReadOnly: skill.ReadOnly,
-		}
-		if skill.Metadata != nil {
-			responses[i].Description = skill.Metadata.Description
-			responses[i].License = skill.Metadata.License
-			responses[i].Compatibility = skill.Metadata.Compatibility
-			responses[i].Metadata = skill.Metadata.Metadata
-			responses[i].AllowedTools = skill.Metadata.AllowedTools
-		}
+	history, err := git.PathHistory(repoDir, skillPath, limit)
+	if errors.Is(err, git.ErrShallowClone) {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": fmt.Sprintf("history %v", err),
+		})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to read history: %v", err),
+		})
 	}
 
-	return c.JSON(http.StatusOK, responses)
+	return c.JSON(http.StatusOK, history)
 }
 
 // Resource management handlers
@@ -445,6 +1175,9 @@ func (s *Server) listSkillResources(c *echo.Context) error {
 			"readable":  res.Readable,
 			"modified":  res.Modified.Format(time.RFC3339),
 		}
+		if res.LFSPointer {
+			resourceMap["lfs_pointer"] = true
+		}
 
 		switch res.Type {
 		case domain.ResourceTypeScript:
@@ -486,11 +1219,38 @@ func (s *Server) getSkillResource(c *echo.Context) error {
 	// Get resource info first
 	info, err := s.skillManager.GetSkillResourceInfo(skill.ID, resourcePath)
 	if err != nil {
+		// <resource>/thumbnail addresses the thumbnail of an existing resource
+		if imagePath, ok := strings.CutSuffix(resourcePath, "/"+thumbnailSuffix); ok {
+			return s.getSkillResourceThumbnail(c, skill, imagePath)
+		}
 		return c.JSON(http.StatusNotFound, map[string]string{
 			"error": "resource not found",
 		})
 	}
 
+	// Unresolved Git LFS pointers have no real content to serve
+	if info.LFSPointer {
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": domain.ErrLFSPointer.Error(),
+		})
+	}
+
+	// Stream the file unless the client wants it base64-encoded in JSON, supporting Range requests
+	encoding := c.QueryParam("encoding")
+	if opener, ok := s.skillManager.(domain.ResourceOpener); ok && encoding != "base64" {
+		file, err := opener.OpenSkillResource(skill.ID, resourcePath)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		defer file.Close()
+
+		c.Response().Header().Set("Content-Type", info.MimeType)
+		http.ServeContent(c.Response(), c.Request(), info.Name, info.Modified, file)
+		return nil
+	}
+
 	// Read resource content
 	content, err := s.skillManager.ReadSkillResource(skill.ID, resourcePath)
 	if err != nil {
@@ -500,7 +1260,6 @@ func (s *Server) getSkillResource(c *echo.Context) error {
 	}
 
 	// Check if client wants base64 encoding
-	encoding := c.QueryParam("encoding")
 	if encoding == "base64" || !info.Readable {
 		return c.JSON(http.StatusOK, map[string]any{
 			"content":   content.Content,
@@ -515,6 +1274,157 @@ func (s *Server) getSkillResource(c *echo.Context) error {
 	return c.String(http.StatusOK, content.Content)
 }
 
+// listSkillFiles lists every file of a skill but SKILL.md, inside the resource directories or not
+func (s *Server) listSkillFiles(c *echo.Context) error {
+	// Check if skill exists
+	skill, err := s.skillManager.ReadSkill(c.Param("name"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "skill not found",
+		})
+	}
+
+	reader, ok := s.skillManager.(domain.SkillFileReader)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "unsupported manager type",
+		})
+	}
+	files, err := reader.ListSkillFiles(skill.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	fileMaps := make([]map[string]any, len(files))
+	for i, file := range files {
+		fileMaps[i] = map[string]any{
+			"type":      file.Type,
+			"path":      file.Path,
+			"name":      file.Name,
+			"size":      file.Size,
+			"mime_type": file.MimeType,
+			"readable":  file.Readable,
+			"modified":  file.Modified.Format(time.RFC3339),
+		}
+		if file.LFSPointer {
+			fileMaps[i]["lfs_pointer"] = true
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"files":    fileMaps,
+		"readOnly": skill.ReadOnly,
+	})
+}
+
+// getSkillFile gets any file of a skill by its path relative to the skill root, such as a USAGE.md
+func (s *Server) getSkillFile(c *echo.Context) error {
+	filePath := c.Param("*")
+	if err := domain.ValidateSkillFilePath(filePath); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Check if skill exists
+	skill, err := s.skillManager.ReadSkill(c.Param("name"))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "skill not found",
+		})
+	}
+
+	reader, ok := s.skillManager.(domain.SkillFileReader)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "unsupported manager type",
+		})
+	}
+	content, err := reader.ReadSkillFile(skill.ID, filePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "file not found",
+		})
+	case errors.Is(err, domain.ErrResourceOutsideSkill):
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	case errors.Is(err, domain.ErrLFSPointer):
+		return c.JSON(http.StatusConflict, map[string]string{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Binary files, or any file when asked for, are returned base64-encoded in JSON
+	if c.QueryParam("encoding") == "base64" || content.Encoding == "base64" {
+		if content.Encoding != "base64" {
+			content.Content = base64.StdEncoding.EncodeToString([]byte(content.Content))
+			content.Encoding = "base64"
+		}
+		return c.JSON(http.StatusOK, map[string]any{
+			"content":   content.Content,
+			"encoding":  content.Encoding,
+			"mime_type": content.MimeType,
+			"size":      content.Size,
+		})
+	}
+
+	// For text files, return as plain text
+	c.Response().Header().Set("Content-Type", content.MimeType)
+	return c.String(http.StatusOK, content.Content)
+}
+
+// getSkillResourceThumbnail serves a small JPEG/PNG thumbnail of an image resource
+func (s *Server) getSkillResourceThumbnail(c *echo.Context, skill *domain.Skill, resourcePath string) error {
+	size := domain.DefaultThumbnailSize
+	if sizeParam := c.QueryParam("size"); sizeParam != "" {
+		parsed, err := strconv.Atoi(sizeParam)
+		if err != nil || parsed < 1 || parsed > domain.MaxThumbnailSize {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("size must be between 1 and %d", domain.MaxThumbnailSize),
+			})
+		}
+		size = parsed
+	}
+
+	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "unsupported manager type",
+		})
+	}
+
+	thumb, err := fsManager.GetResourceThumbnail(skill.ID, resourcePath, size)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotImage):
+			return c.JSON(http.StatusUnsupportedMediaType, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrImageTooLarge):
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+				"error": err.Error(),
+			})
+		case errors.Is(err, domain.ErrLFSPointer):
+			return c.JSON(http.StatusConflict, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "resource not found",
+		})
+	}
+
+	return c.Blob(http.StatusOK, thumb.MimeType, thumb.Data)
+}
+
 // createSkillResource creates/uploads a new resource
 func (s *Server) createSkillResource(c *echo.Context) error {
 	skillName := c.Param("name")
@@ -526,7 +1436,7 @@ func (s *Server) createSkillResource(c *echo.Context) error {
 			"error": "skill not found",
 		})
 	}
-	if skill.ReadOnly {
+	if skill.ReadOnly && !s.overlayEnabled() {
 		return c.JSON(http.StatusForbidden, map[string]string{
 			"error": "cannot create resources in read-only skill from git repository",
 		})
@@ -556,21 +1466,23 @@ func (s *Server) createSkillResource(c *echo.Context) error {
 
 		resourceType := c.FormValue("type")
 		if resourceType == "" {
-			return c.JSON(http.StatusBadRequest, map[string]string{
+			return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 				"error": "type is required (script, reference, or asset)",
 			})
 		}
 
-		pathValue := c.FormValue("path")
-		if pathValue != "" {
-			resourcePath = pathValue
-		} else {
-			resourcePath = resourceType + "s/" + file.Filename
+		resourceDirs := s.resourceDirs()
+		dir, ok := resourceDirs.DirFor(domain.ResourceType(resourceType))
+		if !ok {
+			return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+				"error": "type must be script, reference, or asset",
+			})
 		}
 
-		// Validate path starts with correct directory
-		if !strings.HasPrefix(resourcePath, resourceType+"s/") {
-			resourcePath = resourceType + "s/" + file.Filename
+		// Keep the given path only if it is in a directory of the given type
+		resourcePath = c.FormValue("path")
+		if resourcePath == "" || resourceDirs.ValidatePath(resourcePath) != nil || resourceDirs.TypeOf(resourcePath) != domain.ResourceType(resourceType) {
+			resourcePath = dir + "/" + file.Filename
 		}
 
 		src, err := file.Open()
@@ -608,24 +1520,39 @@ func (s *Server) createSkillResource(c *echo.Context) error {
 	}
 
 	// Validate path
-	if err := domain.ValidateResourcePath(resourcePath); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+	if err := s.resourceDirs().ValidatePath(resourcePath); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": err.Error(),
 		})
 	}
 
-	// Check size limit (10MB)
-	const maxFileSize = 10 * 1024 * 1024
-	if len(fileContent) > maxFileSize {
+	// Enforce the configured file type policy
+	if err := s.importOptions.Extensions.Check(resourcePath); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": fmt.Sprintf("file too large (max %d bytes)", maxFileSize),
+			"error": err.Error(),
 		})
 	}
 
-	// Write file
-	skillsDir := fsManager.GetSkillsDir()
-	skillDir := filepath.Join(skillsDir, skillName)
-	fullPath := filepath.Join(skillDir, resourcePath)
+	// Check size limit
+	if len(fileContent) > s.maxResourceSize {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": fmt.Sprintf("file too large (max %d bytes)", s.maxResourceSize),
+		})
+	}
+
+	// Write file, to the local overlay for git repository skills
+	skillDir, err := fsManager.WritableSkillDir(skill)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	fullPath, err := domain.ResolveResourcePath(skillDir, resourcePath)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
 
 	// Create parent directories if needed
 	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
@@ -655,6 +1582,7 @@ func (s *Server) createSkillResource(c *echo.Context) error {
 		"mime_type": info.MimeType,
 		"readable":  info.Readable,
 		"modified":  info.Modified.Format(time.RFC3339),
+		"url":       resourceURL(skill.Name, info.Path),
 	})
 }
 
@@ -664,7 +1592,7 @@ func (s *Server) updateSkillResource(c *echo.Context) error {
 	resourcePath := c.Param("*")
 
 	if resourcePath == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": "resource path is required",
 		})
 	}
@@ -676,14 +1604,21 @@ func (s *Server) updateSkillResource(c *echo.Context) error {
 			"error": "skill not found",
 		})
 	}
-	if skill.ReadOnly {
+	if skill.ReadOnly && !s.overlayEnabled() {
 		return c.JSON(http.StatusForbidden, map[string]string{
 			"error": "cannot update resources in read-only skill from git repository",
 		})
 	}
 
 	// Validate path
-	if err := domain.ValidateResourcePath(resourcePath); err != nil {
+	if err := s.resourceDirs().ValidatePath(resourcePath); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Enforce the configured file type policy
+	if err := s.importOptions.Extensions.Check(resourcePath); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": err.Error(),
 		})
@@ -698,10 +1633,9 @@ func (s *Server) updateSkillResource(c *echo.Context) error {
 	}
 
 	// Check size limit
-	const maxFileSize = 10 * 1024 * 1024
-	if len(body) > maxFileSize {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": fmt.Sprintf("file too large (max %d bytes)", maxFileSize),
+	if len(body) > s.maxResourceSize {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": fmt.Sprintf("file too large (max %d bytes)", s.maxResourceSize),
 		})
 	}
 
@@ -712,11 +1646,26 @@ func (s *Server) updateSkillResource(c *echo.Context) error {
 		})
 	}
 
-	// Write file
-	skillsDir := fsManager.GetSkillsDir()
-	skillDir := filepath.Join(skillsDir, skillName)
-	fullPath := filepath.Join(skillDir, resourcePath)
+	// Write file, to the local overlay for git repository skills
+	skillDir, err := fsManager.WritableSkillDir(skill)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	fullPath, err := domain.ResolveResourcePath(skillDir, resourcePath)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
 
+	// The resource directory may not exist yet in the overlay
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
 	if err := os.WriteFile(fullPath, body, 0644); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
@@ -738,16 +1687,119 @@ func (s *Server) updateSkillResource(c *echo.Context) error {
 		"mime_type": info.MimeType,
 		"readable":  info.Readable,
 		"modified":  info.Modified.Format(time.RFC3339),
+		"url":       resourceURL(skill.Name, info.Path),
+	})
+}
+
+// uploadResourceArchive extracts a tar.gz or zip archive of resources into a skill
+func (s *Server) uploadResourceArchive(c *echo.Context) error {
+	skillName := c.Param("name")
+
+	// Check if skill exists and is not read-only
+	skill, err := s.skillManager.ReadSkill(skillName)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "skill not found",
+		})
+	}
+	if skill.ReadOnly && !s.overlayEnabled() {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "cannot create resources in read-only skill from git repository",
+		})
+	}
+
+	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "unsupported manager type",
+		})
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "file is required",
+		})
+	}
+
+	if file.Size > int64(s.maxArchiveSize) {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": fmt.Sprintf("archive too large (max %d bytes)", s.maxArchiveSize),
+		})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to open uploaded file",
+		})
+	}
+	defer src.Close()
+
+	archiveData, err := io.ReadAll(io.LimitReader(src, int64(s.maxArchiveSize)))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to read uploaded file",
+		})
+	}
+
+	// Extract to the local overlay for git repository skills
+	skillDir, err := fsManager.WritableSkillDir(skill)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	opts := s.importOptions
+	opts.ResourceDirs = s.resourceDirs()
+	opts.MaxResourceFileSize = int64(s.maxResourceSize)
+	paths, err := domain.ExtractResourceArchive(archiveData, skillDir, opts)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	// Return the added resources
+	resources := make([]map[string]any, 0, len(paths))
+	for _, resourcePath := range paths {
+		info, err := s.skillManager.GetSkillResourceInfo(skill.ID, resourcePath)
+		if err != nil {
+			continue
+		}
+		resources = append(resources, map[string]any{
+			"path":      info.Path,
+			"name":      info.Name,
+			"size":      info.Size,
+			"mime_type": info.MimeType,
+			"readable":  info.Readable,
+			"modified":  info.Modified.Format(time.RFC3339),
+			"url":       resourceURL(skill.Name, info.Path),
+		})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{
+		"resources": resources,
 	})
 }
 
+// resourceURL returns the API URL of a resource, with the skill name as one escaped segment and each segment of
+// the resource path escaped
+func resourceURL(skillName, resourcePath string) string {
+	segments := strings.Split(resourcePath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "/api/skills/" + url.PathEscape(skillName) + "/resources/" + strings.Join(segments, "/")
+}
+
 // deleteSkillResource deletes a resource
 func (s *Server) deleteSkillResource(c *echo.Context) error {
 	skillName := c.Param("name")
 	resourcePath := c.Param("*")
 
 	if resourcePath == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": "resource path is required",
 		})
 	}
@@ -759,31 +1811,46 @@ func (s *Server) deleteSkillResource(c *echo.Context) error {
 			"error": "skill not found",
 		})
 	}
-	if skill.ReadOnly {
+	if skill.ReadOnly && !s.overlayEnabled() {
 		return c.JSON(http.StatusForbidden, map[string]string{
 			"error": "cannot delete resources from read-only skill from git repository",
 		})
 	}
-
-	// Validate path
-	if err := domain.ValidateResourcePath(resourcePath); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
+
+	// Validate path
+	if err := s.resourceDirs().ValidatePath(resourcePath); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "unsupported manager type",
+		})
+	}
+
+	// Delete file; for git repository skills only the local overlay copy can be deleted, which
+	// restores the file from git
+	skillDir, err := fsManager.WritableSkillDir(skill)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	fullPath, err := domain.ResolveResourcePath(skillDir, resourcePath)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{
 			"error": err.Error(),
 		})
 	}
-
-	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
-	if !ok {
-		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "unsupported manager type",
+	if _, err := os.Lstat(fullPath); skill.ReadOnly && errors.Is(err, os.ErrNotExist) {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "cannot delete resources from read-only skill from git repository",
 		})
 	}
 
-	// Delete file
-	skillsDir := fsManager.GetSkillsDir()
-	skillDir := filepath.Join(skillsDir, skillName)
-	fullPath := filepath.Join(skillDir, resourcePath)
-
 	if err := os.Remove(fullPath); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
@@ -800,10 +1867,6 @@ func (s *Server) exportSkill(c *echo.Context) error {
 	name := c.Param("*")
 	// Remove leading slash if present
 	name = strings.TrimPrefix(name, "/")
-	// URL decode the name (Echo should do this automatically, but be explicit)
-	if decoded, err := url.PathUnescape(name); err == nil {
-		name = decoded
-	}
 
 	// Check if skill exists
 	skill, err := s.skillManager.ReadSkill(name)
@@ -822,7 +1885,7 @@ func (s *Server) exportSkill(c *echo.Context) error {
 	}
 
 	// Create archive
-	archiveData, err := domain.ExportSkill(skill.ID, fsManager.GetSkillsDir())
+	archiveData, stats, err := domain.ExportSkillWithStats(skill.Name, fsManager.GetSkillsDir())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": fmt.Sprintf("failed to create archive: %v", err),
@@ -833,10 +1896,56 @@ func (s *Server) exportSkill(c *echo.Context) error {
 	c.Response().Header().Set("Content-Type", "application/gzip")
 	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.tar.gz\"", name))
 	c.Response().Header().Set("Content-Length", fmt.Sprintf("%d", len(archiveData)))
+	c.Response().Header().Set(uncompressedSizeHeader, strconv.FormatInt(stats.UncompressedSize, 10))
+	c.Response().Header().Set(fileCountHeader, strconv.Itoa(stats.Files))
 
 	return c.Blob(http.StatusOK, "application/gzip", archiveData)
 }
 
+// exportAllSkills streams a tar.gz archive of every local skill, and of git repository skills if include_git is set
+func (s *Server) exportAllSkills(c *echo.Context) error {
+	includeGit := false
+	if param := c.QueryParam("include_git"); param != "" {
+		var err error
+		includeGit, err = strconv.ParseBool(param)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "query parameter 'include_git' must be a boolean",
+			})
+		}
+	}
+
+	fsManager, ok := s.skillManager.(*domain.FileSystemManager)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "unsupported manager type",
+		})
+	}
+
+	skills, err := s.skillManager.ListSkills()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	var skillIDs []string
+	for _, skill := range skills {
+		if skill.ReadOnly && !includeGit {
+			continue
+		}
+		skillIDs = append(skillIDs, skill.Name)
+	}
+
+	// The archive is streamed, so a failure part way through can only truncate it
+	c.Response().Header().Set("Content-Type", "application/gzip")
+	c.Response().Header().Set("Content-Disposition", "attachment; filename=\"skills.tar.gz\"")
+	c.Response().WriteHeader(http.StatusOK)
+	if _, err := domain.WriteSkillsArchive(c.Response(), skillIDs, fsManager.GetSkillsDir()); err != nil {
+		s.logf(slog.LevelError, "failed to export all skills: %v", err)
+	}
+	return nil
+}
+
 // importSkill imports a skill from a compressed archive
 func (s *Server) importSkill(c *echo.Context) error {
 	// Get uploaded file
@@ -857,13 +1966,12 @@ func (s *Server) importSkill(c *echo.Context) error {
 	defer src.Close()
 
 	// Read file content
-	const maxArchiveSize = 50 * 1024 * 1024 // 50MB limit
-	archiveData := make([]byte, file.Size)
-	if file.Size > maxArchiveSize {
+	if file.Size > int64(s.maxArchiveSize) {
 		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": fmt.Sprintf("archive too large (max %d bytes)", maxArchiveSize),
+			"error": fmt.Sprintf("archive too large (max %d bytes)", s.maxArchiveSize),
 		})
 	}
+	archiveData := make([]byte, file.Size)
 
 	n, err := io.ReadFull(src, archiveData)
 	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
@@ -881,18 +1989,50 @@ func (s *Server) importSkill(c *echo.Context) error {
 		})
 	}
 
-	// Import skill
-	skillName, err := domain.ImportSkill(archiveData, fsManager.GetSkillsDir())
+	// Import skill, optionally under a new name
+	opts := s.importOptions
+	opts.Name = c.FormValue("name")
+	if opts.Name != "" {
+		if err := domain.ValidateSkillName(opts.Name); err != nil {
+			return c.JSON(http.StatusUnprocessableEntity, map[string]string{
+				"error": err.Error(),
+			})
+		}
+	}
+	if param := c.FormValue("overwrite"); param != "" {
+		opts.Overwrite, err = strconv.ParseBool(param)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "form field 'overwrite' must be a boolean",
+			})
+		}
+	}
+	if param := c.FormValue("autofix"); param != "" {
+		opts.AutoFix, err = strconv.ParseBool(param)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "form field 'autofix' must be a boolean",
+			})
+		}
+	}
+	skillName, err := domain.ImportSkillWithOptions(archiveData, fsManager.GetSkillsDir(), opts)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{
-			"error": err.Error(),
+		status := http.StatusBadRequest
+		message := err.Error()
+		if errors.Is(err, domain.ErrReadOnlySkill) {
+			status = http.StatusForbidden
+		} else if errors.Is(err, domain.ErrNameMismatch) {
+			message += ", or import with autofix=true to use the frontmatter name"
+		}
+		return c.JSON(status, map[string]string{
+			"error": message,
 		})
 	}
 
-	// Rebuild index
-	if err := s.skillManager.RebuildIndex(); err != nil {
+	// Index the skill
+	if err := s.reindexSkill(skillName); err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
-			"error": "failed to rebuild index",
+			"error": "failed to update index",
 		})
 	}
 
@@ -904,20 +2044,7 @@ func (s *Server) importSkill(c *echo.Context) error {
 		})
 	}
 
-	response := SkillResponse{
-		Name:     skill.Name,
-		Content:  skill.Content,
-		ReadOnly: skill.ReadOnly,
-	}
-	if skill.Metadata != nil {
-		response.Description = skill.Metadata.Description
-		response.License = skill.Metadata.License
-		response.Compatibility = skill.Metadata.Compatibility
-		response.Metadata = skill.Metadata.Metadata
-		response.AllowedTools = skill.Metadata.AllowedTools
-	}
-
-	return c.JSON(http.StatusCreated, response)
+	return c.JSON(http.StatusCreated, skillResponse(*skill, true))
 }
 
 // Git repository management handlers
@@ -928,17 +2055,82 @@ type GitRepoResponse struct {
 	URL     string `json:"url"`
 	Name    string `json:"name"`
 	Enabled bool   `json:"enabled"`
+	Ref     string `json:"ref,omitempty"` // Pinned tag or commit, empty when tracking the default branch
+	Shallow bool   `json:"shallow"`       // Shallow clone: history and pinned commits outside the fetched depth are unavailable
+
+	Status      string     `json:"status"` // "ok", "error", "pending" (not synced yet) or "disabled"
+	LastSynced  *time.Time `json:"last_synced,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	LastError   string     `json:"last_error,omitempty"` // Error of the last sync, e.g. an authentication failure
+}
+
+// gitRepoResponse converts a git repository configuration to its API response, with its sync status
+func (s *Server) gitRepoResponse(repo git.GitRepoConfig) GitRepoResponse {
+	response := GitRepoResponse{
+		ID:      repo.ID,
+		URL:     repo.URL,
+		Name:    repo.Name,
+		Enabled: repo.Enabled,
+		Ref:     repo.Ref,
+		Shallow: s.isShallowRepo(repo.Name),
+		Status:  "pending",
+	}
+	if s.gitSyncer != nil {
+		if status, ok := s.gitSyncer.GetRepoStatus()[repo.URL]; ok {
+			response.LastSynced = &status.LastSynced
+			if !status.LastSuccess.IsZero() {
+				response.LastSuccess = &status.LastSuccess
+			}
+			response.LastError = status.LastError
+			response.Status = "ok"
+			if status.LastError != "" {
+				response.Status = "error"
+			}
+		}
+	}
+	if !repo.Enabled {
+		response.Status = "disabled"
+	}
+	return response
 }
 
 // AddGitRepoRequest represents a request to add a git repository
 type AddGitRepoRequest struct {
 	URL string `json:"url"`
+	Ref string `json:"ref,omitempty"` // Optional tag or commit SHA to pin the repository to
+}
+
+// ValidateGitRepoRequest represents a request to dry-run adding a git repository
+type ValidateGitRepoRequest struct {
+	URL    string `json:"url"`
+	Branch string `json:"branch,omitempty"` // Optional branch to clone instead of the default branch
+	Ref    string `json:"ref,omitempty"`    // Optional tag or commit SHA to check out
+}
+
+// ValidateGitRepoResponse is the result of a git repository dry run
+type ValidateGitRepoResponse struct {
+	Valid  bool     `json:"valid"`
+	Name   string   `json:"name,omitempty"`
+	Skills []string `json:"skills"`
+	Count  int      `json:"count"`
+	Error  string   `json:"error,omitempty"` // Reachability or authentication error if the clone failed
 }
 
 // UpdateGitRepoRequest represents a request to update a git repository
 type UpdateGitRepoRequest struct {
-	URL     string `json:"url"`
-	Enabled bool   `json:"enabled"`
+	URL     string  `json:"url"`
+	Enabled bool    `json:"enabled"`
+	Ref     *string `json:"ref,omitempty"` // Pinned ref; omit to keep the current one, empty string to unpin
+}
+
+// isShallowRepo reports whether the checkout of a git repository is a shallow clone
+// Repositories that are not cloned yet are reported as not shallow
+func (s *Server) isShallowRepo(name string) bool {
+	if s.gitSyncer == nil {
+		return false
+	}
+	shallow, err := git.IsShallow(filepath.Join(s.gitSyncer.GetSkillsDir(), name))
+	return err == nil && shallow
 }
 
 // listGitRepos lists all configured git repositories
@@ -958,12 +2150,7 @@ func (s *Server) listGitRepos(c *echo.Context) error {
 	// Convert to response format
 	repos := make([]GitRepoResponse, len(configRepos))
 	for i, repo := range configRepos {
-		repos[i] = GitRepoResponse{
-			ID:      repo.ID,
-			URL:     repo.URL,
-			Name:    repo.Name,
-			Enabled: repo.Enabled,
-		}
+		repos[i] = s.gitRepoResponse(repo)
 	}
 
 	return c.JSON(http.StatusOK, repos)
@@ -984,6 +2171,7 @@ func (s *Server) addGitRepo(c *echo.Context) error {
 		})
 	}
 
+	req.URL = git.NormalizeRepoURL(req.URL)
 	if req.URL == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": "URL is required",
@@ -991,7 +2179,7 @@ func (s *Server) addGitRepo(c *echo.Context) error {
 	}
 
 	// Validate URL format (basic check)
-	if !strings.HasPrefix(req.URL, "http://") && !strings.HasPrefix(req.URL, "https://") && !strings.HasPrefix(req.URL, "git@") {
+	if err := git.ValidateRepoURL(req.URL); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": "invalid URL format",
 		})
@@ -1005,9 +2193,9 @@ func (s *Server) addGitRepo(c *echo.Context) error {
 		})
 	}
 
-	// Check if repo already exists
+	// Check if repo already exists, e.g. with or without a .git suffix
 	for _, repo := range configRepos {
-		if repo.URL == req.URL {
+		if git.SameRepoURL(repo.URL, req.URL) {
 			return c.JSON(http.StatusBadRequest, map[string]string{
 				"error": "repository already exists",
 			})
@@ -1015,12 +2203,9 @@ func (s *Server) addGitRepo(c *echo.Context) error {
 	}
 
 	// Add new repo to config (enabled by default)
-	newRepo := git.GitRepoConfig{
-		ID:      git.GenerateID(req.URL),
-		URL:     req.URL,
-		Name:    git.ExtractRepoName(req.URL),
-		Enabled: true,
-	}
+	// A repository named like another one (e.g. from a different host) gets a distinct directory
+	newRepo := git.NewRepoConfig(req.URL, configRepos)
+	newRepo.Ref = strings.TrimSpace(req.Ref)
 	configRepos = append(configRepos, newRepo)
 
 	// Save config
@@ -1032,12 +2217,15 @@ func (s *Server) addGitRepo(c *echo.Context) error {
 
 	// Add repo to syncer and update FileSystemManager
 	if s.gitSyncer != nil {
+		s.gitSyncer.SetRepoConfigs(configRepos)
 		if err := s.gitSyncer.AddRepo(req.URL); err != nil {
 			// Remove from config if sync failed
 			for i, repo := range configRepos {
 				if repo.URL == req.URL {
 					configRepos = append(configRepos[:i], configRepos[i+1:]...)
-					s.configManager.SaveConfig(configRepos)
+					if err := s.configManager.SaveConfig(configRepos); err != nil {
+						s.logf(slog.LevelError, "failed to save config after removing repository %s: %v", req.URL, err)
+					}
 					break
 				}
 			}
@@ -1048,105 +2236,74 @@ func (s *Server) addGitRepo(c *echo.Context) error {
 
 		// Update FileSystemManager's git repos list for read-only detection
 		if s.fsManager != nil {
-			enabledRepos := make([]string, 0)
-			for _, repo := range configRepos {
-				if repo.Enabled {
-					enabledRepos = append(enabledRepos, repo.URL)
-				}
-			}
-			gitRepoNames := make([]string, len(enabledRepos))
-			for i, url := range enabledRepos {
-				gitRepoNames[i] = git.ExtractRepoName(url)
-			}
-			s.fsManager.UpdateGitRepos(gitRepoNames)
+			s.fsManager.UpdateGitRepos(git.EnabledRepoDirNames(configRepos))
 		}
 	}
 
-	response := GitRepoResponse{
-		ID:      git.GenerateID(req.URL),
-		URL:     req.URL,
-		Name:    git.ExtractRepoName(req.URL),
-		Enabled: true,
-	}
+	response := s.gitRepoResponse(newRepo)
 
 	return c.JSON(http.StatusCreated, response)
 }
 
-// updateGitRepo updates a git repository
-func (s *Server) updateGitRepo(c *echo.Context) error {
+// validateGitRepo clones a git repository to a temporary directory and reports the skills it would
+// contribute, without persisting it
+func (s *Server) validateGitRepo(c *echo.Context) error {
 	if s.gitSyncer == nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "git syncer not available",
 		})
 	}
 
-	id := c.Param("id")
-
-	var req UpdateGitRepoRequest
+	var req ValidateGitRepoRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": "invalid request",
 		})
 	}
 
-	// Find repo by ID
-	repos := s.gitSyncer.GetRepos()
-	var foundURL string
-	for _, url := range repos {
-		if git.GenerateID(url) == id {
-			foundURL = url
-			break
-		}
+	if req.URL == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "URL is required",
+		})
 	}
 
-	if foundURL == "" {
-		return c.JSON(http.StatusNotFound, map[string]string{
-			"error": "repository not found",
+	if err := git.ValidateRepoURL(req.URL); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid URL format",
 		})
 	}
 
-	// If URL changed, remove old and add new
-	if req.URL != "" && req.URL != foundURL {
-		if err := s.gitSyncer.RemoveRepo(foundURL); err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": err.Error(),
-			})
-		}
-		if err := s.gitSyncer.AddRepo(req.URL); err != nil {
-			// Try to restore old repo on error
-			s.gitSyncer.AddRepo(foundURL)
-			return c.JSON(http.StatusBadRequest, map[string]string{
-				"error": err.Error(),
-			})
-		}
-		foundURL = req.URL
+	result, err := s.gitSyncer.ValidateRepo(req.URL, strings.TrimSpace(req.Branch), strings.TrimSpace(req.Ref))
+	if err != nil {
+		return c.JSON(http.StatusOK, ValidateGitRepoResponse{
+			Skills: []string{},
+			Error:  err.Error(),
+		})
 	}
 
-	// Update FileSystemManager's git repos list for read-only detection
-	if s.fsManager != nil {
-		repos := s.gitSyncer.GetRepos()
-		gitRepoNames := make([]string, len(repos))
-		for i, url := range repos {
-			gitRepoNames[i] = git.ExtractRepoName(url)
-		}
-		s.fsManager.UpdateGitRepos(gitRepoNames)
+	return c.JSON(http.StatusOK, ValidateGitRepoResponse{
+		Valid:  true,
+		Name:   result.Name,
+		Skills: result.Skills,
+		Count:  len(result.Skills),
+	})
+}
+
+// updateGitRepo updates a git repository
+func (s *Server) updateGitRepo(c *echo.Context) error {
+	if s.gitSyncer == nil || s.configManager == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "git syncer or config manager not available",
+		})
 	}
 
-	// Save config
-	if s.configManager != nil {
-		repos := s.gitSyncer.GetRepos()
-		configs := make([]git.GitRepoConfig, len(repos))
-		for i, url := range repos {
-			configs[i] = git.GitRepoConfig{
-				ID:      git.GenerateID(url),
-				URL:     url,
-				Name:    git.ExtractRepoName(url),
-				Enabled: true,
-			}
-		}
-		if err := s.configManager.SaveConfig(configs); err != nil {
-			fmt.Printf("Warning: failed to save config: %v\n", err)
-		}
+	id := c.Param("id")
+
+	var req UpdateGitRepoRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request",
+		})
 	}
 
 	// Load current config
@@ -1157,13 +2314,62 @@ func (s *Server) updateGitRepo(c *echo.Context) error {
 		})
 	}
 
-	// Update enabled status for the repo
+	// Find repo by ID
+	idx := -1
 	for i := range configRepos {
 		if configRepos[i].ID == id {
-			configRepos[i].Enabled = req.Enabled
+			idx = i
 			break
 		}
 	}
+	if idx == -1 {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "repository not found",
+		})
+	}
+	oldRepo := configRepos[idx]
+
+	// Update the repo settings
+	configRepos[idx].Enabled = req.Enabled
+	if req.Ref != nil {
+		configRepos[idx].Ref = strings.TrimSpace(*req.Ref)
+	}
+	req.URL = git.NormalizeRepoURL(req.URL)
+	urlChanged := req.URL != "" && req.URL != oldRepo.URL
+	if urlChanged {
+		for i, repo := range configRepos {
+			if i != idx && git.SameRepoURL(repo.URL, req.URL) {
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": "repository already exists",
+				})
+			}
+		}
+		others := append(append([]git.GitRepoConfig{}, configRepos[:idx]...), configRepos[idx+1:]...)
+		updated := git.NewRepoConfig(req.URL, others)
+		configRepos[idx].ID = updated.ID
+		configRepos[idx].URL = updated.URL
+		configRepos[idx].Name = updated.Name
+	}
+	s.gitSyncer.SetRepoConfigs(configRepos)
+
+	// If URL changed, remove old and add new
+	if urlChanged {
+		// The old repo is only known to the syncer if it was enabled
+		s.gitSyncer.RemoveRepo(oldRepo.URL)
+		if configRepos[idx].Enabled {
+			if err := s.gitSyncer.AddRepo(req.URL); err != nil {
+				// Try to restore old repo on error
+				configRepos[idx] = oldRepo
+				s.gitSyncer.SetRepoConfigs(configRepos)
+				if oldRepo.Enabled {
+					s.gitSyncer.AddRepo(oldRepo.URL)
+				}
+				return c.JSON(http.StatusBadRequest, map[string]string{
+					"error": err.Error(),
+				})
+			}
+		}
+	}
 
 	// Save updated config
 	if err := s.configManager.SaveConfig(configRepos); err != nil {
@@ -1173,52 +2379,35 @@ func (s *Server) updateGitRepo(c *echo.Context) error {
 	}
 
 	// Update syncer and FileSystemManager based on enabled repos
-	if s.gitSyncer != nil {
-		enabledRepos := make([]string, 0)
-		for _, repo := range configRepos {
-			if repo.Enabled {
-				enabledRepos = append(enabledRepos, repo.URL)
-			}
-		}
-		
-		// Update syncer repos
-		if err := s.gitSyncer.UpdateRepos(enabledRepos); err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": fmt.Sprintf("failed to update syncer: %v", err),
-			})
+	enabledRepos := make([]string, 0)
+	for _, repo := range configRepos {
+		if repo.Enabled {
+			enabledRepos = append(enabledRepos, repo.URL)
 		}
+	}
 
-		// Update FileSystemManager's git repos list
-		if s.fsManager != nil {
-			gitRepoNames := make([]string, len(enabledRepos))
-			for i, url := range enabledRepos {
-				gitRepoNames[i] = git.ExtractRepoName(url)
-			}
-			s.fsManager.UpdateGitRepos(gitRepoNames)
-		}
+	// Update syncer repos
+	if err := s.gitSyncer.UpdateRepos(enabledRepos); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to update syncer: %v", err),
+		})
+	}
 
-		// Rebuild index
-		if err := s.skillManager.RebuildIndex(); err != nil {
-			return c.JSON(http.StatusInternalServerError, map[string]string{
-				"error": "failed to rebuild index",
-			})
-		}
+	// Update FileSystemManager's git repos list
+	if s.fsManager != nil {
+		s.fsManager.UpdateGitRepos(git.EnabledRepoDirNames(configRepos))
 	}
 
-	// Find updated repo for response
-	var response GitRepoResponse
-	for _, repo := range configRepos {
-		if repo.ID == id {
-			response = GitRepoResponse{
-				ID:      repo.ID,
-				URL:     repo.URL,
-				Name:    repo.Name,
-				Enabled: repo.Enabled,
-			}
-			break
-		}
+	// Rebuild index
+	if err := s.skillManager.RebuildIndex(); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed to rebuild index",
+		})
 	}
 
+	repo := configRepos[idx]
+	response := s.gitRepoResponse(repo)
+
 	return c.JSON(http.StatusOK, response)
 }
 
@@ -1292,22 +2481,12 @@ func (s *Server) deleteGitRepo(c *echo.Context) error {
 	repoDir := filepath.Join(skillsDir, repoName)
 	if err := os.RemoveAll(repoDir); err != nil {
 		// Log error but don't fail the request - repo is already removed from config
-		fmt.Printf("Warning: failed to delete repository directory %s: %v\n", repoDir, err)
+		s.logf(slog.LevelWarn, "failed to delete repository directory %s: %v", repoDir, err)
 	}
 
 	// Update FileSystemManager's git repos list for read-only detection
 	if s.fsManager != nil {
-		enabledRepos := make([]string, 0)
-		for _, repo := range updatedConfigs {
-			if repo.Enabled {
-				enabledRepos = append(enabledRepos, repo.URL)
-			}
-		}
-		gitRepoNames := make([]string, len(enabledRepos))
-		for i, url := range enabledRepos {
-			gitRepoNames[i] = git.ExtractRepoName(url)
-		}
-		s.fsManager.UpdateGitRepos(gitRepoNames)
+		s.fsManager.UpdateGitRepos(git.EnabledRepoDirNames(updatedConfigs))
 	}
 
 	// Trigger re-indexing
@@ -1367,16 +2546,64 @@ func (s *Server) syncGitRepo(c *echo.Context) error {
 		})
 	}
 
-	response := GitRepoResponse{
-		ID:      foundRepo.ID,
-		URL:     foundRepo.URL,
-		Name:    foundRepo.Name,
-		Enabled: foundRepo.Enabled,
-	}
+	response := s.gitRepoResponse(*foundRepo)
 
 	return c.JSON(http.StatusOK, response)
 }
 
+// GitSyncResult is the outcome of syncing a repository with the sync-all endpoint
+type GitSyncResult struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Status string `json:"status"` // "synced" or "failed"
+	Error  string `json:"error,omitempty"`
+}
+
+// syncAllGitRepos syncs every enabled git repository now and reports the outcome of each
+// Repositories still unsynced when the timeout expires are reported as failed
+func (s *Server) syncAllGitRepos(c *echo.Context) error {
+	if s.gitSyncer == nil || s.configManager == nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "git syncer or config manager not available",
+		})
+	}
+
+	configRepos, err := s.configManager.LoadConfig()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to load config: %v", err),
+		})
+	}
+	ids := make(map[string]string, len(configRepos))
+	for _, repo := range configRepos {
+		ids[repo.URL] = repo.ID
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), syncAllTimeout)
+	defer cancel()
+	synced, err := s.gitSyncer.SyncAll(ctx)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	results := make([]GitSyncResult, 0, len(synced))
+	for _, result := range synced {
+		status := "synced"
+		if result.Error != "" {
+			status = "failed"
+		}
+		results = append(results, GitSyncResult{
+			ID:     ids[result.URL],
+			URL:    result.URL,
+			Status: status,
+			Error:  result.Error,
+		})
+	}
+	return c.JSON(http.StatusOK, results)
+}
+
 // toggleGitRepo toggles the enabled status of a git repository
 func (s *Server) toggleGitRepo(c *echo.Context) error {
 	if s.configManager == nil {
@@ -1420,6 +2647,7 @@ func (s *Server) toggleGitRepo(c *echo.Context) error {
 
 	// Update syncer and FileSystemManager based on enabled repos
 	if s.gitSyncer != nil {
+		s.gitSyncer.SetRepoConfigs(configRepos)
 		enabledRepos := make([]string, 0)
 		for _, repo := range configRepos {
 			if repo.Enabled {
@@ -1436,11 +2664,7 @@ func (s *Server) toggleGitRepo(c *echo.Context) error {
 
 		// Update FileSystemManager's git repos list
 		if s.fsManager != nil {
-			gitRepoNames := make([]string, len(enabledRepos))
-			for i, url := range enabledRepos {
-				gitRepoNames[i] = git.ExtractRepoName(url)
-			}
-			s.fsManager.UpdateGitRepos(gitRepoNames)
+			s.fsManager.UpdateGitRepos(git.EnabledRepoDirNames(configRepos))
 		}
 
 		// Rebuild index
@@ -1451,18 +2675,37 @@ func (s *Server) toggleGitRepo(c *echo.Context) error {
 		}
 	}
 
-	response := GitRepoResponse{
-		ID:      foundRepo.ID,
-		URL:     foundRepo.URL,
-		Name:    foundRepo.Name,
-		Enabled: foundRepo.Enabled,
-	}
+	response := s.gitRepoResponse(*foundRepo)
 
 	return c.JSON(http.StatusOK, response)
 }
 
 // Helper functions
 
+// optionalField returns the frontmatter value of an optional string field, nil (removing the key) if empty
+func optionalField(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// optionalList returns the frontmatter value of an optional list field, nil (removing the key) if empty
+func optionalList(values []string) any {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// optionalMap returns the frontmatter value of an optional map field, nil (removing the key) if empty
+func optionalMap(values map[string]string) any {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
 func writeFile(filename, content string) error {
 	return os.WriteFile(filename, []byte(content), 0644)
 }
@@ -0,0 +1,1265 @@
+package web_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/git"
+	"github.com/mudler/skillserver/pkg/web"
+)
+
+var _ = Describe("Skill listing", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	list := func(query string) (int, web.SkillListResponse) {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills"+query, nil))
+		var response web.SkillListResponse
+		if rec.Code == http.StatusOK {
+			Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		}
+		return rec.Code, response
+	}
+
+	names := func(response web.SkillListResponse) []string {
+		var names []string
+		for _, item := range response.Items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < 5; i++ {
+			name := fmt.Sprintf("skill-%d", i)
+			skillDir := filepath.Join(tempDir, name)
+			Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: "+name+"\ndescription: A skill\n---\n# Skill"), 0644)).To(Succeed())
+		}
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should return the first page with the offset of the next one", func() {
+		code, response := list("?limit=2")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(names(response)).To(Equal([]string{"skill-0", "skill-1"}))
+		Expect(response.Total).To(Equal(5))
+		Expect(response.NextOffset).NotTo(BeNil())
+		Expect(*response.NextOffset).To(Equal(2))
+	})
+
+	It("should return a middle page", func() {
+		code, response := list("?limit=2&offset=2")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(names(response)).To(Equal([]string{"skill-2", "skill-3"}))
+		Expect(*response.NextOffset).To(Equal(4))
+
+		_, response = list("?limit=2&offset=4")
+		Expect(names(response)).To(Equal([]string{"skill-4"}))
+		Expect(response.NextOffset).To(BeNil())
+	})
+
+	It("should return an empty page for an offset past the end", func() {
+		code, response := list("?offset=10")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(response.Items).To(BeEmpty())
+		Expect(response.Total).To(Equal(5))
+		Expect(response.NextOffset).To(BeNil())
+	})
+
+	It("should include the content unless content=false is requested", func() {
+		_, response := list("?limit=1")
+		Expect(response.Items[0].Content).To(Equal("# Skill"))
+
+		code, response := list("?limit=1&content=false")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(response.Items[0].Content).To(BeEmpty())
+		Expect(response.Items[0].Description).To(Equal("A skill"))
+		Expect(response.Items[0].ContentLength).NotTo(BeZero())
+
+		code, _ = list("?content=maybe")
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should filter by tag", func() {
+		Expect(os.WriteFile(filepath.Join(tempDir, "skill-1", "SKILL.md"), []byte("---\nname: skill-1\ndescription: A skill\ntags: [networking, dns]\n---\n# Skill"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tempDir, "skill-3", "SKILL.md"), []byte("---\nname: skill-3\ndescription: A skill\ntags: [networking]\n---\n# Skill"), 0644)).To(Succeed())
+
+		code, response := list("?tag=networking")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(names(response)).To(Equal([]string{"skill-1", "skill-3"}))
+		Expect(response.Total).To(Equal(2))
+
+		_, response = list("?tag=networking&tag=dns&tagMatch=all")
+		Expect(names(response)).To(Equal([]string{"skill-1"}))
+
+		code, _ = list("?tag=dns&tagMatch=some")
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should reject invalid limits and offsets", func() {
+		code, _ := list("?limit=0")
+		Expect(code).To(Equal(http.StatusBadRequest))
+		code, _ = list("?limit=501")
+		Expect(code).To(Equal(http.StatusBadRequest))
+		code, _ = list("?offset=-1")
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should list skills left out of the listing with the reason", func() {
+		brokenDir := filepath.Join(tempDir, "broken")
+		Expect(os.MkdirAll(brokenDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(brokenDir, "SKILL.md"), []byte("---\nname: broken\n---\n# Skill"), 0644)).To(Succeed())
+
+		_, response := list("")
+		Expect(names(response)).NotTo(ContainElement("broken"))
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/issues", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var issues []web.SkillIssueResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &issues)).To(Succeed())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Path).To(Equal("broken"))
+		Expect(issues[0].Error).To(ContainSubstring("'description' field is required"))
+	})
+})
+
+var _ = Describe("Skill version and tags", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should round-trip version and tags through the frontmatter", func() {
+		body := `{"name":"docker","description":"Docker","content":"# Docker","version":"1.10","tags":["containers","devops"]}`
+		req := httptest.NewRequest(http.MethodPost, "/api/skills", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/docker", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var skill web.SkillResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &skill)).To(Succeed())
+		Expect(skill.Version).To(Equal("1.10"))
+		Expect(skill.Tags).To(Equal([]string{"containers", "devops"}))
+	})
+
+	It("should return allowed tools as a list", func() {
+		body := `{"name":"docker","description":"Docker","content":"# Docker","allowed-tools":"Bash Read Bash"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/skills", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+
+		var skill web.SkillResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &skill)).To(Succeed())
+		Expect(skill.AllowedTools).To(Equal("Bash Read Bash"))
+		Expect(skill.AllowedToolsList).To(Equal([]string{"Bash", "Read"}))
+	})
+
+	It("should reject comma-separated allowed tools", func() {
+		req := httptest.NewRequest(http.MethodPost, "/api/skills", strings.NewReader(`{"name":"docker","description":"Docker","content":"# Docker","allowed-tools":"Bash, Read"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(filepath.Join(tempDir, "docker")).NotTo(BeADirectory())
+	})
+
+	It("should keep author-defined frontmatter keys on update", func() {
+		skillDir := filepath.Join(tempDir, "docker")
+		Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: docker\ndescription: Docker\nauthor: Jane Doe\nreviewers: [ops, sec]\n---\n# Docker"), 0644)).To(Succeed())
+
+		body := `{"description":"Docker guide","content":"# Docker\nUpdated"}`
+		req := httptest.NewRequest(http.MethodPut, "/api/skills/docker", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/docker?fresh=true", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var skill web.SkillResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &skill)).To(Succeed())
+		Expect(skill.Description).To(Equal("Docker guide"))
+		Expect(skill.Extra).To(HaveKeyWithValue("author", "Jane Doe"))
+		Expect(skill.Extra).To(HaveKeyWithValue("reviewers", []any{"ops", "sec"}))
+	})
+
+	It("should only change the updated frontmatter fields and keep the body as given", func() {
+		skillDir := filepath.Join(tempDir, "docker")
+		Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+		original := "---\nname: docker\nlicense: MIT\ndescription: Docker\ntags: [containers, devops]\n---\n\n# Docker\n"
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(original), 0644)).To(Succeed())
+
+		body, err := json.Marshal(map[string]any{
+			"description": "Docker guide",
+			"license":     "MIT",
+			"tags":        []string{"containers", "devops"},
+			"content":     "# Docker\n\n    docker run  --rm alpine\n\n",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(http.MethodPut, "/api/skills/docker", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		content, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("---\nname: docker\nlicense: MIT\ndescription: Docker guide\ntags: [containers, devops]\n---\n\n# Docker\n\n    docker run  --rm alpine\n\n"))
+	})
+
+	It("should write frontmatter that parses back whatever the description contains", func() {
+		for i, description := range []string{
+			`Deploy: build and ship`,
+			`Say "hello" and 'bye'`,
+			"Ship it 🚀 # not a comment",
+			"First line\nsecond line: too",
+		} {
+			name := fmt.Sprintf("skill-%d", i)
+			body, err := json.Marshal(map[string]any{"name": name, "description": description, "content": "# Skill", "license": "MIT: see LICENSE"})
+			Expect(err).NotTo(HaveOccurred())
+			req := httptest.NewRequest(http.MethodPost, "/api/skills", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, req)
+			Expect(rec.Code).To(Equal(http.StatusCreated), "description %q", description)
+
+			rec = httptest.NewRecorder()
+			server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/"+name+"?fresh=true", nil))
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			var skill web.SkillResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &skill)).To(Succeed())
+			Expect(skill.Description).To(Equal(description))
+			Expect(skill.License).To(Equal("MIT: see LICENSE"))
+		}
+	})
+
+	It("should reject invalid tags", func() {
+		req := httptest.NewRequest(http.MethodPost, "/api/skills", strings.NewReader(`{"name":"docker","description":"Docker","content":"# Docker","tags":["a,b"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+	})
+})
+
+var _ = Describe("Request status codes", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeSkill(filepath.Join(tempDir, "docker"), "name: docker\ndescription: Docker\n", "# Docker")
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should return 400 for malformed JSON", func() {
+		Expect(send(http.MethodPost, "/api/skills", `{"name":"helm"`).Code).To(Equal(http.StatusBadRequest))
+		Expect(send(http.MethodPut, "/api/skills/docker", `{"description":`).Code).To(Equal(http.StatusBadRequest))
+		Expect(send(http.MethodPost, "/api/skills/docker/resources", `{"path":"scripts/run.sh",`).Code).To(Equal(http.StatusBadRequest))
+		Expect(filepath.Join(tempDir, "helm")).NotTo(BeADirectory())
+	})
+
+	It("should return 422 for a create with invalid fields", func() {
+		rec := send(http.MethodPost, "/api/skills", `{"name":"Not_Valid","description":"Helm","content":"# Helm"}`)
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(rec.Body.String()).To(ContainSubstring(`"error"`))
+
+		Expect(send(http.MethodPost, "/api/skills", `{"name":"helm","content":"# Helm"}`).Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(filepath.Join(tempDir, "helm")).NotTo(BeADirectory())
+	})
+
+	It("should return 422 for an update with invalid fields", func() {
+		Expect(send(http.MethodPut, "/api/skills/docker", `{"description":"","content":"# Docker"}`).Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(send(http.MethodPut, "/api/skills/docker", `{"description":"Docker","content":"# Docker","version":"1.0\n2.0"}`).Code).To(Equal(http.StatusUnprocessableEntity))
+
+		content, err := os.ReadFile(filepath.Join(tempDir, "docker", "SKILL.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("description: Docker\n"))
+	})
+
+	It("should return 422 for a resource with an invalid path", func() {
+		Expect(send(http.MethodPost, "/api/skills/docker/resources", `{"path":"../escape.sh","content":"#!/bin/sh"}`).Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(send(http.MethodPost, "/api/skills/docker/resources", `{"path":"notes/todo.md","content":"# Todo"}`).Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(send(http.MethodPut, "/api/skills/docker/resources/notes/todo.md", "# Todo").Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(filepath.Join(tempDir, "escape.sh")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(tempDir, "docker", "notes")).NotTo(BeAnExistingFile())
+	})
+})
+
+var _ = Describe("Exporting all skills", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	// exportAll returns the names of the entries of the exported archive
+	exportAll := func(query string) []string {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/export-all"+query, nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/gzip"))
+
+		gzr, err := gzip.NewReader(rec.Body)
+		Expect(err).NotTo(HaveOccurred())
+		tr := tar.NewReader(gzr)
+		var entries []string
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).NotTo(HaveOccurred())
+			entries = append(entries, header.Name)
+		}
+		return entries
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeSkill(filepath.Join(tempDir, "docker"), "name: docker\ndescription: A skill\n", "# Skill")
+		writeSkill(filepath.Join(tempDir, "linux"), "name: linux\ndescription: A skill\n", "# Skill")
+		Expect(os.MkdirAll(filepath.Join(tempDir, "linux", "scripts"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tempDir, "linux", "scripts", "setup.sh"), []byte("#!/bin/sh"), 0755)).To(Succeed())
+		writeSkill(filepath.Join(tempDir, "repo", "helm"), "name: helm\ndescription: A skill\n", "# Skill")
+
+		manager, err := domain.NewFileSystemManager(tempDir, []string{"repo"})
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should put every local skill under its own directory", func() {
+		entries := exportAll("")
+		Expect(entries).To(ContainElements("docker/SKILL.md", "linux/SKILL.md", "linux/scripts/setup.sh"))
+		Expect(entries).NotTo(ContainElement(HavePrefix("repo/")))
+	})
+
+	It("should include git repository skills when requested", func() {
+		entries := exportAll("?include_git=true")
+		Expect(entries).To(ContainElements("docker/SKILL.md", "linux/SKILL.md", "repo/helm/SKILL.md"))
+	})
+})
+
+var _ = Describe("Nested git repository skills", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+		writeSkill(filepath.Join(tempDir, "repo", "category", "docker"), "name: docker\ndescription: Docker\n", "# Docker")
+
+		manager, err := domain.NewFileSystemManager(tempDir, []string{"repo"})
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should read a skill by its full path in the repository", func() {
+		for _, name := range []string{"repo/category/docker", "repo/docker"} {
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/"+url.PathEscape(name), nil))
+			Expect(rec.Code).To(Equal(http.StatusOK), name)
+			var skill web.SkillResponse
+			Expect(json.Unmarshal(rec.Body.Bytes(), &skill)).To(Succeed())
+			Expect(skill.Name).To(Equal("repo/category/docker"), name)
+			Expect(skill.ReadOnly).To(BeTrue())
+		}
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/repo%2Fcategory%2Fdocker/files", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+})
+
+var _ = Describe("Skill history", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	history := func(name string) []git.CommitInfo {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/"+url.PathEscape(name)+"/history", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+		var commits []git.CommitInfo
+		Expect(json.Unmarshal(rec.Body.Bytes(), &commits)).To(Succeed())
+		return commits
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		repoDir := filepath.Join(tempDir, "repo")
+		repo, err := gogit.PlainInit(repoDir, false)
+		Expect(err).NotTo(HaveOccurred())
+		writeSkill(filepath.Join(repoDir, "helm"), "name: helm\ndescription: Helm\n", "# Helm")
+		w, err := repo.Worktree()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Add("helm/SKILL.md")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Commit("add helm", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		writeSkill(filepath.Join(tempDir, "docker"), "name: docker\ndescription: Docker\n", "# Docker")
+
+		manager, err := domain.NewFileSystemManager(tempDir, []string{"repo"})
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should return the commits of a git repo skill addressed by its name", func() {
+		commits := history("repo/helm")
+		Expect(commits).To(HaveLen(1))
+		Expect(commits[0].Message).To(Equal("add helm"))
+	})
+
+	It("should return no commits for a local skill", func() {
+		Expect(history("docker")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Git repository status", func() {
+	It("should report the error of a repository that fails to sync", func() {
+		tempDir, err := os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tempDir)
+
+		// Nothing listens on port 1, so the clone fails
+		bogusURL := "http://127.0.0.1:1/bogus.git"
+		configManager := git.NewConfigManager(tempDir)
+		Expect(configManager.SaveConfig([]git.GitRepoConfig{
+			{ID: "bogus", URL: bogusURL, Name: "bogus", Enabled: true},
+			{ID: "other", URL: "http://127.0.0.1:1/other.git", Name: "other", Enabled: true},
+		})).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		syncer := git.NewGitSyncer(tempDir, []string{bogusURL}, nil)
+		Expect(syncer.Start()).To(Succeed())
+		syncer.Stop()
+		server := web.NewServer(manager, manager, nil, syncer, configManager, false)
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/git-repos", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var repos []web.GitRepoResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &repos)).To(Succeed())
+		Expect(repos).To(HaveLen(2))
+
+		Expect(repos[0].Status).To(Equal("error"))
+		Expect(repos[0].LastError).To(ContainSubstring("failed to clone repository"))
+		Expect(repos[0].LastSynced).NotTo(BeNil())
+		Expect(repos[0].LastSuccess).To(BeNil())
+
+		// Not synced yet
+		Expect(repos[1].Status).To(Equal("pending"))
+		Expect(repos[1].LastSynced).To(BeNil())
+	})
+})
+
+var _ = Describe("Syncing all git repositories", func() {
+	It("should report the repositories that synced and those that failed", func() {
+		tempDir, err := os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tempDir)
+		skillsDir := filepath.Join(tempDir, "skills")
+		Expect(os.MkdirAll(skillsDir, 0755)).To(Succeed())
+
+		upstreamDir := filepath.Join(tempDir, "upstream")
+		upstream, err := gogit.PlainInit(upstreamDir, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(upstreamDir, "my-skill"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(upstreamDir, "my-skill", "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# My Skill"), 0644)).To(Succeed())
+		w, err := upstream.Worktree()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Add("my-skill/SKILL.md")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Commit("add skill", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Nothing listens on port 1, so the clone fails
+		bogusURL := "http://127.0.0.1:1/bogus.git"
+		configManager := git.NewConfigManager(skillsDir)
+		Expect(configManager.SaveConfig([]git.GitRepoConfig{
+			{ID: "upstream", URL: upstreamDir, Name: "upstream", Enabled: true},
+			{ID: "bogus", URL: bogusURL, Name: "bogus", Enabled: true},
+		})).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(skillsDir, []string{"upstream", "bogus"})
+		Expect(err).NotTo(HaveOccurred())
+		syncer := git.NewGitSyncer(skillsDir, []string{upstreamDir, bogusURL}, manager.RebuildIndex)
+		defer syncer.Stop()
+		server := web.NewServer(manager, manager, []string{upstreamDir, bogusURL}, syncer, configManager, false)
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/git-repos/sync", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var results []web.GitSyncResult
+		Expect(json.Unmarshal(rec.Body.Bytes(), &results)).To(Succeed())
+		Expect(results).To(HaveLen(2))
+
+		Expect(results[0].ID).To(Equal("upstream"))
+		Expect(results[0].Status).To(Equal("synced"))
+		Expect(results[0].Error).To(BeEmpty())
+		Expect(results[1].ID).To(Equal("bogus"))
+		Expect(results[1].Status).To(Equal("failed"))
+		Expect(results[1].Error).To(ContainSubstring("failed to clone repository"))
+
+		// The skills of the repository that synced are indexed
+		Expect(manager.ReadSkill("upstream/my-skill")).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("Adding git repositories", func() {
+	It("should normalize the URL and reject variants of a configured repository", func() {
+		tempDir, err := os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tempDir)
+		skillsDir := filepath.Join(tempDir, "skills")
+		Expect(os.MkdirAll(skillsDir, 0755)).To(Succeed())
+
+		upstreamDir := filepath.Join(tempDir, "upstream")
+		upstream, err := gogit.PlainInit(upstreamDir, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(upstreamDir, "my-skill"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(upstreamDir, "my-skill", "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# My Skill"), 0644)).To(Succeed())
+		w, err := upstream.Worktree()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Add("my-skill/SKILL.md")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Commit("add skill", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		configManager := git.NewConfigManager(skillsDir)
+		manager, err := domain.NewFileSystemManager(skillsDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		syncer := git.NewGitSyncer(skillsDir, []string{}, manager.RebuildIndex)
+		defer syncer.Stop()
+		server := web.NewServer(manager, manager, nil, syncer, configManager, false)
+
+		add := func(url string) *httptest.ResponseRecorder {
+			body, err := json.Marshal(web.AddGitRepoRequest{URL: url})
+			Expect(err).NotTo(HaveOccurred())
+			req := httptest.NewRequest(http.MethodPost, "/api/git-repos", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, req)
+			return rec
+		}
+
+		repoURL := "file://" + upstreamDir
+		rec := add(repoURL + "/")
+		Expect(rec.Code).To(Equal(http.StatusCreated), rec.Body.String())
+		var repo web.GitRepoResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &repo)).To(Succeed())
+		Expect(repo.URL).To(Equal(repoURL))
+		Expect(repo.Name).To(Equal("upstream"))
+
+		for _, variant := range []string{repoURL, repoURL + ".git", repoURL + ".git/"} {
+			rec = add(variant)
+			Expect(rec.Code).To(Equal(http.StatusBadRequest), variant)
+			Expect(rec.Body.String()).To(ContainSubstring("repository already exists"))
+		}
+		Expect(syncer.GetRepos()).To(Equal([]string{repoURL}))
+	})
+})
+
+var _ = Describe("Reindexing", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeSkill(filepath.Join(tempDir, "docker"), "name: docker\ndescription: A skill\n", "# Skill")
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should index skills added outside the API and report the count", func() {
+		writeSkill(filepath.Join(tempDir, "linux"), "name: linux\ndescription: A skill\n", "# Skill")
+		writeSkill(filepath.Join(tempDir, "helm"), "name: helm\ndescription: A skill\n", "# Skill")
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/reindex", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var response web.ReindexResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Skills).To(Equal(3))
+		Expect(response.Duration).NotTo(BeEmpty())
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/search/status", nil))
+		var status domain.SearchStatusInfo
+		Expect(json.Unmarshal(rec.Body.Bytes(), &status)).To(Succeed())
+		Expect(status.Documents).To(Equal(3))
+	})
+
+	It("should require the API key", func() {
+		server.SetAPIKey("secret", false)
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/reindex", nil))
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+	})
+})
+
+var _ = Describe("Stats", func() {
+	It("should count local and git repository skills and their resources", func() {
+		tempDir, err := os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tempDir)
+
+		for _, dir := range []string{"docker", "repo/helm", "repo/linux"} {
+			Expect(os.MkdirAll(filepath.Join(tempDir, dir, "references"), 0755)).To(Succeed())
+			name := filepath.Base(dir)
+			Expect(os.WriteFile(filepath.Join(tempDir, dir, "SKILL.md"), []byte("---\nname: "+name+"\ndescription: A skill\n---\n# Skill"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(tempDir, dir, "references", "guide.md"), []byte("# Guide"), 0644)).To(Succeed())
+		}
+		manager, err := domain.NewFileSystemManager(tempDir, []string{"repo"})
+		Expect(err).NotTo(HaveOccurred())
+		server := web.NewServer(manager, manager, nil, nil, nil, false)
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var stats web.StatsResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &stats)).To(Succeed())
+		Expect(stats.Skills).To(Equal(3))
+		Expect(stats.LocalSkills).To(Equal(1))
+		Expect(stats.GitSkills).To(Equal(2))
+		Expect(stats.GitRepos).To(Equal(1))
+		Expect(stats.RepoSkills).To(Equal(map[string]int{"repo": 2}))
+		Expect(stats.Resources).To(Equal(3))
+		Expect(stats.ResourceBytes).To(Equal(int64(3 * len("# Guide"))))
+	})
+})
+
+var _ = Describe("Skill timestamps", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	listSkills := func(query string) []web.SkillResponse {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills"+query, nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var response web.SkillListResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		return response.Items
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		now := time.Now().Truncate(time.Second)
+		for name, age := range map[string]time.Duration{"docker": 2 * time.Hour, "helm": time.Hour, "linux": 3 * time.Hour} {
+			writeSkill(filepath.Join(tempDir, name), "name: "+name+"\ndescription: A skill\n", "# Skill")
+			modified := now.Add(-age)
+			Expect(os.Chtimes(filepath.Join(tempDir, name, "SKILL.md"), modified, modified)).To(Succeed())
+		}
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should return the created and modified times", func() {
+		skills := listSkills("")
+		Expect(skills).To(HaveLen(3))
+		for _, skill := range skills {
+			Expect(skill.Created).NotTo(BeZero())
+			Expect(skill.Modified).NotTo(BeZero())
+		}
+	})
+
+	It("should list the most recently modified skills first", func() {
+		var names []string
+		for _, skill := range listSkills("?sort=modified") {
+			names = append(names, skill.Name)
+		}
+		Expect(names).To(Equal([]string{"helm", "docker", "linux"}))
+	})
+
+	It("should reject unknown orders", func() {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills?sort=size", nil))
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+	})
+})
+
+var _ = Describe("Copying skills", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	copySkill := func(name, body string) (*httptest.ResponseRecorder, web.SkillResponse) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/skills/"+url.PathEscape(name)+"/copy", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		server.ServeHTTP(rec, req)
+		var response web.SkillResponse
+		if rec.Code == http.StatusCreated {
+			Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		}
+		return rec, response
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeSkill(filepath.Join(tempDir, "docker"), "name: docker\ndescription: Docker\n", "# Docker")
+		writeSkill(filepath.Join(tempDir, "repo", "helm"), "name: helm\ndescription: Helm charts\n", "# Helm")
+
+		manager, err := domain.NewFileSystemManager(tempDir, []string{"repo"})
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should copy a local skill", func() {
+		rec, skill := copySkill("docker", `{"name": "docker-copy"}`)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+		Expect(skill.Name).To(Equal("docker-copy"))
+		Expect(skill.ReadOnly).To(BeFalse())
+		Expect(skill.ID).To(Equal("docker-copy"))
+	})
+
+	It("should copy a git skill into a searchable local skill", func() {
+		rec, skill := copySkill("repo/helm", `{"name": "my-helm"}`)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+		Expect(skill.ReadOnly).To(BeFalse())
+		Expect(skill.ID).To(Equal("my-helm"))
+		skillMd, err := os.ReadFile(filepath.Join(tempDir, "my-helm", "SKILL.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(skillMd)).To(ContainSubstring("name: my-helm\n"))
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/search?q=helm", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring(`"my-helm"`))
+	})
+
+	It("should reject copies over existing skills", func() {
+		rec, _ := copySkill("repo/helm", `{"name": "docker"}`)
+		Expect(rec.Code).To(Equal(http.StatusConflict))
+	})
+
+	It("should reject invalid names", func() {
+		rec, _ := copySkill("docker", `{"name": "Not Valid"}`)
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+	})
+
+	It("should return 404 for unknown skills", func() {
+		rec, _ := copySkill("missing", `{"name": "copy"}`)
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+})
+
+var _ = Describe("Importing skills", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	// importSkill uploads a skill archive whose single directory dir holds the SKILL.md content
+	importSkill := func(path, dir, skillMd string) *httptest.ResponseRecorder {
+		var archive bytes.Buffer
+		gzw := gzip.NewWriter(&archive)
+		tw := tar.NewWriter(gzw)
+		Expect(tw.WriteHeader(&tar.Header{Name: dir + "/SKILL.md", Mode: 0644, Size: int64(len(skillMd)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tw.Write([]byte(skillMd))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tw.Close()).To(Succeed())
+		Expect(gzw.Close()).To(Succeed())
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", "skill.tar.gz")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = part.Write(archive.Bytes())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	const skillMd = "---\nname: docker\ndescription: Docker\n---\n# Docker"
+
+	It("should explain how to fix an archive whose directory was renamed", func() {
+		rec := importSkill("/api/skills/import", "docker-v2", skillMd)
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		var response map[string]string
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		Expect(response["error"]).To(ContainSubstring(`names the skill "docker" but the archive's directory is "docker-v2"`))
+		Expect(response["error"]).To(ContainSubstring("autofix=true"))
+		Expect(filepath.Join(tempDir, "docker-v2")).NotTo(BeADirectory())
+	})
+
+	It("should import under the frontmatter name with autofix", func() {
+		rec := importSkill("/api/skills/import?autofix=true", "docker-v2", skillMd)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+		var skill web.SkillResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &skill)).To(Succeed())
+		Expect(skill.Name).To(Equal("docker"))
+		Expect(filepath.Join(tempDir, "docker", "SKILL.md")).To(BeARegularFile())
+	})
+})
+
+var _ = Describe("Validating skills", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	validate := func(body string) web.ValidateSkillResponse {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/skills/validate", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var response web.ValidateSkillResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		return response
+	}
+
+	skillMdBody := func(frontmatter string) string {
+		body, err := json.Marshal(map[string]string{"content": "---\n" + frontmatter + "\n---\n# Skill\n"})
+		Expect(err).NotTo(HaveOccurred())
+		return string(body)
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		entries, err := os.ReadDir(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+		for _, entry := range entries {
+			Expect(entry.Name()).To(HavePrefix("."), "validation must not write skills")
+		}
+		os.RemoveAll(tempDir)
+	})
+
+	It("should return the parsed metadata of valid content", func() {
+		response := validate(skillMdBody("name: docker\ndescription: Docker\ntags: [containers]"))
+		Expect(response.Valid).To(BeTrue())
+		Expect(response.Errors).To(BeEmpty())
+		Expect(response.Metadata.Name).To(Equal("docker"))
+		Expect(response.Metadata.Tags).To(Equal([]string{"containers"}))
+	})
+
+	It("should report a missing name", func() {
+		response := validate(skillMdBody("description: Docker"))
+		Expect(response.Valid).To(BeFalse())
+		Expect(response.Errors).To(Equal([]string{"frontmatter 'name' field is required"}))
+	})
+
+	It("should report a description that is too long", func() {
+		response := validate(skillMdBody("name: docker\ndescription: " + strings.Repeat("a", 1025)))
+		Expect(response.Valid).To(BeFalse())
+		Expect(response.Errors).To(ConsistOf(ContainSubstring("description must be 1-1024 characters")))
+	})
+
+	It("should report a bad name", func() {
+		response := validate(skillMdBody("name: Docker Tools\ndescription: Docker"))
+		Expect(response.Valid).To(BeFalse())
+		Expect(response.Errors).To(ConsistOf(ContainSubstring("invalid skill name")))
+	})
+
+	It("should check the frontmatter name against the given directory name", func() {
+		response := validate(`{"content": "---\nname: docker\ndescription: Docker\n---\n# Docker", "name": "docker-v2"}`)
+		Expect(response.Valid).To(BeFalse())
+		Expect(response.Errors).To(ConsistOf(ContainSubstring(`its directory is "docker-v2"`)))
+	})
+
+	It("should validate uploaded skill archives", func() {
+		var archive bytes.Buffer
+		gzw := gzip.NewWriter(&archive)
+		tw := tar.NewWriter(gzw)
+		skillMd := "---\nname: docker\n---\n# Docker"
+		Expect(tw.WriteHeader(&tar.Header{Name: "docker/SKILL.md", Mode: 0644, Size: int64(len(skillMd)), Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tw.Write([]byte(skillMd))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tw.Close()).To(Succeed())
+		Expect(gzw.Close()).To(Succeed())
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", "docker.tar.gz")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = part.Write(archive.Bytes())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/skills/validate", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var response web.ValidateSkillResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Valid).To(BeFalse())
+		Expect(response.Name).To(Equal("docker"))
+		Expect(response.Errors).To(Equal([]string{"frontmatter 'description' field is required"}))
+	})
+})
+
+var _ = Describe("Renaming skills", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	renameSkill := func(name, body string) (*httptest.ResponseRecorder, web.SkillResponse) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/skills/"+name+"/rename", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		server.ServeHTTP(rec, req)
+		var response web.SkillResponse
+		if rec.Code == http.StatusOK {
+			Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		}
+		return rec, response
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, name := range []string{"docker", "helm"} {
+			dir := filepath.Join(tempDir, name)
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: "+name+"\ndescription: Containers\n---\n# Skill"), 0644)).To(Succeed())
+		}
+		gitDir := filepath.Join(tempDir, "repo", "linux")
+		Expect(os.MkdirAll(gitDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(gitDir, "SKILL.md"), []byte("---\nid: git-linux\nname: linux\ndescription: Linux\n---\n# Linux"), 0644)).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(tempDir, []string{"repo"})
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should rename the skill and update the index", func() {
+		rec, skill := renameSkill("docker", `{"name": "containers"}`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(skill.Name).To(Equal("containers"))
+		skillMd, err := os.ReadFile(filepath.Join(tempDir, "containers", "SKILL.md"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(skillMd)).To(ContainSubstring("name: containers\n"))
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/docker", nil))
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/search?q=containers", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring(`"containers"`))
+		Expect(rec.Body.String()).NotTo(ContainSubstring(`"docker"`))
+	})
+
+	It("should reject names of existing skills", func() {
+		rec, _ := renameSkill("docker", `{"name": "helm"}`)
+		Expect(rec.Code).To(Equal(http.StatusConflict))
+	})
+
+	It("should reject renaming git skills", func() {
+		rec, _ := renameSkill("git-linux", `{"name": "my-linux"}`)
+		Expect(rec.Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("should reject invalid names", func() {
+		rec, _ := renameSkill("docker", `{"name": "Not Valid"}`)
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+	})
+})
+
+var _ = Describe("Bulk deleting skills", func() {
+	var (
+		tempDir string
+		manager *domain.FileSystemManager
+		server  *web.Server
+	)
+
+	bulkDelete := func(body string) (*httptest.ResponseRecorder, []web.BulkDeleteResult) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/skills/bulk-delete", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		server.ServeHTTP(rec, req)
+		var response struct {
+			Deleted int                    `json:"deleted"`
+			Results []web.BulkDeleteResult `json:"results"`
+		}
+		if rec.Code == http.StatusOK {
+			Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		}
+		return rec, response.Results
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, name := range []string{"docker", "helm", "kubectl"} {
+			dir := filepath.Join(tempDir, name)
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: "+name+"\ndescription: Containers\n---\n# Skill"), 0644)).To(Succeed())
+		}
+		gitDir := filepath.Join(tempDir, "repo", "linux")
+		Expect(os.MkdirAll(gitDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(gitDir, "SKILL.md"), []byte("---\nname: linux\ndescription: Containers\n---\n# Linux"), 0644)).To(Succeed())
+
+		manager, err = domain.NewFileSystemManager(tempDir, []string{"repo"})
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should delete each skill and report a result per name", func() {
+		rec, results := bulkDelete(`{"names": ["docker", "repo/linux", "missing", "helm"]}`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring(`"deleted":2`))
+		Expect(results).To(Equal([]web.BulkDeleteResult{
+			{Name: "docker", Deleted: true, Status: http.StatusNoContent},
+			{Name: "repo/linux", Deleted: false, Status: http.StatusForbidden, Error: "cannot delete read-only skill from git repository"},
+			{Name: "missing", Deleted: false, Status: http.StatusNotFound, Error: "skill not found"},
+			{Name: "helm", Deleted: true, Status: http.StatusNoContent},
+		}))
+
+		Expect(filepath.Join(tempDir, "docker")).NotTo(BeADirectory())
+		Expect(filepath.Join(tempDir, "helm")).NotTo(BeADirectory())
+		Expect(filepath.Join(tempDir, "kubectl")).To(BeADirectory())
+		Expect(filepath.Join(tempDir, "repo", "linux")).To(BeADirectory())
+	})
+
+	It("should rebuild the index once for the whole batch", func() {
+		before := manager.SearchStatus().Rebuilds
+		rec, _ := bulkDelete(`{"names": ["docker", "helm"]}`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(manager.SearchStatus().Rebuilds).To(Equal(before + 1))
+
+		results, err := manager.SearchSkills("containers")
+		Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, skill := range results {
+			names = append(names, skill.Name)
+		}
+		Expect(names).To(ConsistOf("kubectl", "repo/linux"))
+
+		// Nothing deleted, nothing to rebuild
+		rec, _ = bulkDelete(`{"names": ["missing"]}`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(manager.SearchStatus().Rebuilds).To(Equal(before + 1))
+	})
+
+	It("should reject a request without names", func() {
+		rec, _ := bulkDelete(`{"names": []}`)
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+	})
+})
+
+var _ = Describe("Skill content length", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	send := func(method, path, content string) int {
+		body, err := json.Marshal(map[string]any{"name": "docker", "description": "Docker", "content": content})
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manager.SetMaxContentLength(100)).To(Succeed())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should create skills with content up to the limit", func() {
+		Expect(send(http.MethodPost, "/api/skills", strings.Repeat("x", 100))).To(Equal(http.StatusCreated))
+
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/docker", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+
+	It("should reject content over the limit", func() {
+		Expect(send(http.MethodPost, "/api/skills", strings.Repeat("x", 101))).To(Equal(http.StatusUnprocessableEntity))
+		Expect(filepath.Join(tempDir, "docker")).NotTo(BeADirectory())
+
+		Expect(send(http.MethodPost, "/api/skills", "# Docker")).To(Equal(http.StatusCreated))
+		Expect(send(http.MethodPut, "/api/skills/docker", strings.Repeat("x", 101))).To(Equal(http.StatusUnprocessableEntity))
+		Expect(send(http.MethodPut, "/api/skills/docker", strings.Repeat("x", 100))).To(Equal(http.StatusOK))
+	})
+})
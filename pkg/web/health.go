@@ -0,0 +1,65 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v5"
+
+	"github.com/mudler/skillserver/pkg/domain"
+)
+
+// HealthResponse is the body of the liveness and readiness probes
+type HealthResponse struct {
+	Status   string     `json:"status"` // "ok", or what the server is still waiting for
+	Skills   int        `json:"skills"` // Number of indexed skills
+	GitRepos int        `json:"git_repos"`
+	LastSync *time.Time `json:"last_sync,omitempty"` // Completion time of the last git sync
+}
+
+// health reports the probe body and whether the search index has been built, and the initial git sync completed
+func (s *Server) health() (HealthResponse, bool, bool) {
+	response := HealthResponse{Status: "ok"}
+
+	indexed := false
+	if fsManager, ok := s.skillManager.(*domain.FileSystemManager); ok {
+		status := fsManager.SearchStatus()
+		response.Skills = status.Documents
+		indexed = status.LastIndexed != nil
+	}
+
+	synced := true
+	if s.gitSyncer != nil {
+		response.GitRepos = len(s.gitSyncer.GetRepos())
+		if lastSync := s.gitSyncer.LastSync(); !lastSync.IsZero() {
+			response.LastSync = &lastSync
+		} else if response.GitRepos > 0 {
+			synced = false
+		}
+	}
+	return response, indexed, synced
+}
+
+// getHealth is the liveness probe: 200 once the skill index has been built
+func (s *Server) getHealth(c *echo.Context) error {
+	response, indexed, _ := s.health()
+	if !indexed {
+		response.Status = "indexing"
+		return c.JSON(http.StatusServiceUnavailable, response)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// getReadiness is the readiness probe: 503 until the skill index has been built and the initial git sync completed
+func (s *Server) getReadiness(c *echo.Context) error {
+	response, indexed, synced := s.health()
+	switch {
+	case !indexed:
+		response.Status = "indexing"
+	case !synced:
+		response.Status = "syncing"
+	default:
+		return c.JSON(http.StatusOK, response)
+	}
+	return c.JSON(http.StatusServiceUnavailable, response)
+}
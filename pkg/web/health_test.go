@@ -0,0 +1,92 @@
+package web_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	gogit "github.com/go-git/go-git/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/git"
+	"github.com/mudler/skillserver/pkg/web"
+)
+
+var _ = Describe("Health probes", func() {
+	var (
+		tempDir   string
+		skillsDir string
+		manager   *domain.FileSystemManager
+		err       error
+	)
+
+	get := func(server *web.Server, path string) (int, web.HealthResponse) {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var response web.HealthResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		return rec.Code, response
+	}
+
+	BeforeEach(func() {
+		tempDir, err = os.MkdirTemp("", "skillserver-health-test")
+		Expect(err).NotTo(HaveOccurred())
+		skillsDir = filepath.Join(tempDir, "skills")
+
+		skillDir := filepath.Join(skillsDir, "my-skill")
+		Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# My Skill"), 0644)).To(Succeed())
+
+		manager, err = domain.NewFileSystemManager(skillsDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should be live and ready once the index is built when no repositories are configured", func() {
+		syncer := git.NewGitSyncer(skillsDir, []string{}, nil)
+		server := web.NewServer(manager, manager, nil, syncer, nil, false)
+
+		code, response := get(server, "/healthz")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(response.Status).To(Equal("ok"))
+		Expect(response.Skills).To(Equal(1))
+
+		code, _ = get(server, "/readyz")
+		Expect(code).To(Equal(http.StatusOK))
+	})
+
+	It("should not be ready until the initial git sync has completed", func() {
+		upstreamDir := filepath.Join(tempDir, "upstream")
+		_, err := gogit.PlainInit(upstreamDir, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		syncer := git.NewGitSyncer(skillsDir, []string{upstreamDir}, nil)
+		server := web.NewServer(manager, manager, nil, syncer, nil, false)
+
+		code, response := get(server, "/readyz")
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+		Expect(response.Status).To(Equal("syncing"))
+		Expect(response.GitRepos).To(Equal(1))
+		Expect(response.LastSync).To(BeNil())
+
+		// Liveness does not depend on the sync
+		code, _ = get(server, "/healthz")
+		Expect(code).To(Equal(http.StatusOK))
+
+		// The sync of the empty repository fails, but the initial sync is complete
+		Expect(syncer.Start()).To(Succeed())
+		defer syncer.Stop()
+
+		code, response = get(server, "/readyz")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(response.Status).To(Equal("ok"))
+		Expect(response.LastSync).NotTo(BeNil())
+	})
+})
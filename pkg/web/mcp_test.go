@@ -0,0 +1,84 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/mcp"
+	"github.com/mudler/skillserver/pkg/web"
+)
+
+var _ = Describe("MCP over HTTP", func() {
+	var (
+		tempDir    string
+		server     *web.Server
+		httpServer *httptest.Server
+		ctx        context.Context
+		cancel     context.CancelFunc
+	)
+
+	connect := func(httpClient *http.Client) (*gomcp.ClientSession, error) {
+		client := gomcp.NewClient(&gomcp.Implementation{Name: "test", Version: "v1.0.0"}, nil)
+		return client.Connect(ctx, &gomcp.StreamableClientTransport{
+			Endpoint:   httpServer.URL + "/mcp",
+			HTTPClient: httpClient,
+			MaxRetries: -1,
+		}, nil)
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-mcp-http-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		skillDir := filepath.Join(tempDir, "my-skill")
+		Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# My Skill"), 0644)).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+		server.MountMCP("/mcp", mcp.NewServer(manager).HTTPHandler())
+		httpServer = httptest.NewServer(server)
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		cancel()
+		httpServer.Close()
+		os.RemoveAll(tempDir)
+	})
+
+	It("should list skills", func() {
+		session, err := connect(nil)
+		Expect(err).NotTo(HaveOccurred())
+		defer session.Close()
+
+		result, err := session.CallTool(ctx, &gomcp.CallToolParams{Name: "list_skills"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.IsError).To(BeFalse())
+
+		data, err := json.Marshal(result.StructuredContent)
+		Expect(err).NotTo(HaveOccurred())
+		var output mcp.ListSkillsOutput
+		Expect(json.Unmarshal(data, &output)).To(Succeed())
+		Expect(output.Skills).To(HaveLen(1))
+		Expect(output.Skills[0].ID).To(Equal("my-skill"))
+	})
+
+	It("should require the API key when reads are authenticated", func() {
+		server.SetAPIKey("s3cret", true)
+
+		_, err := connect(nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
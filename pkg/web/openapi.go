@@ -0,0 +1,18 @@
+package web
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/labstack/echo/v5"
+)
+
+// openAPISpec is the OpenAPI 3 document of the REST API, kept by hand alongside the handlers
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// getOpenAPI serves the OpenAPI document of the REST API, e.g. to generate clients from
+func (s *Server) getOpenAPI(c *echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, openAPISpec)
+}
@@ -0,0 +1,62 @@
+package web_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/web"
+)
+
+var _ = Describe("OpenAPI document", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-openapi-test")
+		Expect(err).NotTo(HaveOccurred())
+		manager, err := domain.NewFileSystemManager(filepath.Join(tempDir, "skills"), nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should serve a valid OpenAPI 3 document describing the skill endpoints", func() {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+
+		var document struct {
+			OpenAPI    string                    `json:"openapi"`
+			Paths      map[string]map[string]any `json:"paths"`
+			Components struct {
+				Schemas map[string]any `json:"schemas"`
+			} `json:"components"`
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &document)).To(Succeed())
+		Expect(document.OpenAPI).To(HavePrefix("3."))
+
+		Expect(document.Paths).To(HaveKey("/skills"))
+		Expect(document.Paths["/skills"]).To(HaveKey("get"))
+		Expect(document.Paths["/skills"]).To(HaveKey("post"))
+		Expect(document.Paths).To(HaveKey("/skills/{name}"))
+		Expect(document.Paths).To(HaveKey("/skills/{name}/resources/{path}"))
+		Expect(document.Paths).To(HaveKey("/git-repos/{id}"))
+		Expect(document.Components.Schemas).To(HaveKey("SkillResponse"))
+		Expect(document.Components.Schemas).To(HaveKey("CreateSkillRequest"))
+		Expect(document.Components.Schemas).To(HaveKey("GitRepoResponse"))
+	})
+})
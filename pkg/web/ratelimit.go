@@ -0,0 +1,43 @@
+package web
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/middleware"
+)
+
+// SetRateLimit limits each client IP to requestsPerSecond requests to the REST API and the MCP HTTP endpoints,
+// with bursts of up to one second's worth of requests; zero or less disables rate limiting
+// The UI and health probes are never limited. It must be called before the server starts serving requests
+func (s *Server) SetRateLimit(requestsPerSecond float64) {
+	if requestsPerSecond <= 0 {
+		return
+	}
+	s.rateLimit = requestsPerSecond
+
+	// Clients that exceed the limit are told to wait for the next request to be allowed
+	retryAfter := strconv.Itoa(int(math.Max(1, math.Ceil(1/requestsPerSecond))))
+	limiter := middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		Skipper: func(c *echo.Context) bool {
+			path := c.Request().URL.Path
+			return !strings.HasPrefix(path, "/api/") && !s.isMCPPath(path)
+		},
+		// Identify clients by their connection, as forwarding headers can be set by the client to evade the limit
+		IdentifierExtractor: func(c *echo.Context) (string, error) {
+			return echo.ExtractIPDirect()(c.Request()), nil
+		},
+		Store: middleware.NewRateLimiterMemoryStore(requestsPerSecond),
+		DenyHandler: func(c *echo.Context, identifier string, err error) error {
+			c.Response().Header().Set(echo.HeaderRetryAfter, retryAfter)
+			return c.JSON(http.StatusTooManyRequests, map[string]string{
+				"error": "rate limit exceeded",
+			})
+		},
+	})
+	// Run before routing and authentication, so requests with a wrong API key count towards the limit too
+	s.echo.Pre(limiter)
+}
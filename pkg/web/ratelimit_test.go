@@ -0,0 +1,68 @@
+package web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/web"
+)
+
+var _ = Describe("Rate limiting", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	request := func(path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-ratelimit-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should be disabled by default", func() {
+		for range 20 {
+			Expect(request("/api/skills", "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+		}
+	})
+
+	It("should reject requests beyond the limit with 429", func() {
+		server.SetRateLimit(0.5)
+
+		Expect(request("/api/skills", "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+		rec := request("/api/skills", "192.0.2.1:1234")
+		Expect(rec.Code).To(Equal(http.StatusTooManyRequests))
+		Expect(rec.Header().Get("Retry-After")).To(Equal("2"))
+
+		// Other clients have their own limit
+		Expect(request("/api/skills", "192.0.2.2:1234").Code).To(Equal(http.StatusOK))
+	})
+
+	It("should not limit health probes", func() {
+		server.SetRateLimit(0.5)
+
+		for range 5 {
+			Expect(request("/healthz", "192.0.2.1:1234").Code).To(Equal(http.StatusOK))
+		}
+	})
+})
@@ -0,0 +1,40 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// SetReadOnly refuses REST API requests that would write to the skills directory, e.g. when it is mounted
+// read-only; reads, validation and reindexing (the search index lives outside the skills directory) stay available
+func (s *Server) SetReadOnly(readOnly bool) {
+	s.readOnly = readOnly
+}
+
+// rejectWrites refuses requests that write to the skills directory while the server is read-only
+func (s *Server) rejectWrites(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		if !s.readOnly || !writesSkillsDir(c.Request()) {
+			return next(c)
+		}
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "skills directory is read-only",
+		})
+	}
+}
+
+// writesSkillsDir reports whether a REST API request may change the skills directory
+func writesSkillsDir(req *http.Request) bool {
+	path := req.URL.Path
+	switch {
+	case !strings.HasPrefix(path, "/api/"):
+		return false
+	case req.Method == http.MethodGet || req.Method == http.MethodHead || req.Method == http.MethodOptions:
+		return false
+	case path == "/api/skills/validate", path == "/api/git-repos/validate", path == "/api/reindex":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package web_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/web"
+)
+
+var _ = Describe("Read-only mode", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-readonly-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		writeSkill(filepath.Join(tempDir, "docker"), "name: docker\ndescription: Docker\n", "# Docker")
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+		server.SetReadOnly(true)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should refuse writes to the skills directory", func() {
+		Expect(send(http.MethodPost, "/api/skills", `{"name":"helm","description":"Helm","content":"# Helm"}`).Code).To(Equal(http.StatusForbidden))
+		Expect(send(http.MethodPut, "/api/skills/docker", `{"description":"Docker guide","content":"# Docker"}`).Code).To(Equal(http.StatusForbidden))
+		Expect(send(http.MethodDelete, "/api/skills/docker", "").Code).To(Equal(http.StatusForbidden))
+		Expect(send(http.MethodPost, "/api/skills/docker/resources", `{"path":"scripts/run.sh","content":"#!/bin/sh"}`).Code).To(Equal(http.StatusForbidden))
+		Expect(filepath.Join(tempDir, "helm")).NotTo(BeADirectory())
+		Expect(filepath.Join(tempDir, "docker", "SKILL.md")).To(BeAnExistingFile())
+	})
+
+	It("should keep serving reads, validation and reindexing", func() {
+		Expect(send(http.MethodGet, "/api/skills/docker", "").Code).To(Equal(http.StatusOK))
+		Expect(send(http.MethodPost, "/api/skills/validate", `{"content":"---\nname: docker\ndescription: Docker\n---\n# Docker"}`).Code).To(Equal(http.StatusOK))
+		Expect(send(http.MethodPost, "/api/reindex", "").Code).To(Equal(http.StatusOK))
+	})
+
+	It("should report read-only mode in the configuration", func() {
+		rec := send(http.MethodGet, "/api/config", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var config web.ConfigResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &config)).To(Succeed())
+		Expect(config.ReadOnly).To(BeTrue())
+	})
+})
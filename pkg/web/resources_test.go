@@ -0,0 +1,557 @@
+package web_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/web"
+)
+
+var _ = Describe("Upload size limits", func() {
+	const limit = 100
+
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	upload := func(path, field, filename string, content []byte, fields map[string]string) int {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for key, value := range fields {
+			Expect(writer.WriteField(key, value)).To(Succeed())
+		}
+		part, err := writer.CreateFormFile(field, filename)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = part.Write(content)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		req := httptest.NewRequest(http.MethodPost, path, &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	put := func(path string, content []byte) int {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, path, bytes.NewReader(content)))
+		return rec.Code
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		skillDir := filepath.Join(tempDir, "my-skill")
+		Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# My Skill"), 0644)).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+		server.SetMaxResourceSize(limit)
+		server.SetMaxArchiveSize(limit)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should accept a resource at the configured limit and reject one over it", func() {
+		fields := map[string]string{"type": "reference"}
+		code := upload("/api/skills/my-skill/resources", "file", "small.md", []byte(strings.Repeat("a", limit)), fields)
+		Expect(code).To(Equal(http.StatusCreated))
+
+		code = upload("/api/skills/my-skill/resources", "file", "large.md", []byte(strings.Repeat("a", limit+1)), fields)
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(filepath.Join(tempDir, "my-skill", "references", "large.md")).NotTo(BeAnExistingFile())
+	})
+
+	It("should apply the limit to resource updates", func() {
+		Expect(os.MkdirAll(filepath.Join(tempDir, "my-skill", "references"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tempDir, "my-skill", "references", "small.md"), []byte("a"), 0644)).To(Succeed())
+
+		Expect(put("/api/skills/my-skill/resources/references/small.md", []byte(strings.Repeat("a", limit)))).To(Equal(http.StatusOK))
+		Expect(put("/api/skills/my-skill/resources/references/small.md", []byte(strings.Repeat("a", limit+1)))).To(Equal(http.StatusUnprocessableEntity))
+	})
+
+	It("should reject archives over the configured limit", func() {
+		code := upload("/api/skills/import", "file", "skill.tar.gz", bytes.Repeat([]byte{0}, limit+1), nil)
+		Expect(code).To(Equal(http.StatusBadRequest))
+
+		code = upload("/api/skills/my-skill/resources/upload-archive", "file", "resources.tar.gz", bytes.Repeat([]byte{0}, limit+1), nil)
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should apply the limit to each file of a resource archive", func() {
+		server.SetMaxArchiveSize(1024 * 1024)
+		resourceArchive := func(size int) []byte {
+			var buf bytes.Buffer
+			gzw := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gzw)
+			Expect(tw.WriteHeader(&tar.Header{Name: "references/guide.md", Mode: 0644, Size: int64(size), Typeflag: tar.TypeReg})).To(Succeed())
+			_, err := tw.Write([]byte(strings.Repeat("a", size)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tw.Close()).To(Succeed())
+			Expect(gzw.Close()).To(Succeed())
+			return buf.Bytes()
+		}
+
+		code := upload("/api/skills/my-skill/resources/upload-archive", "file", "resources.tar.gz", resourceArchive(limit+1), nil)
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(filepath.Join(tempDir, "my-skill", "references", "guide.md")).NotTo(BeAnExistingFile())
+
+		code = upload("/api/skills/my-skill/resources/upload-archive", "file", "resources.tar.gz", resourceArchive(limit), nil)
+		Expect(code).To(Equal(http.StatusCreated))
+		Expect(filepath.Join(tempDir, "my-skill", "references", "guide.md")).To(BeAnExistingFile())
+	})
+})
+
+var _ = Describe("Resource downloads", func() {
+	var (
+		tempDir string
+		server  *web.Server
+		content []byte
+	)
+
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		skillDir := filepath.Join(tempDir, "my-skill")
+		Expect(os.MkdirAll(filepath.Join(skillDir, "assets"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# My Skill"), 0644)).To(Succeed())
+		content = make([]byte, 4096)
+		for i := range content {
+			content[i] = byte(i % 251)
+		}
+		Expect(os.WriteFile(filepath.Join(skillDir, "assets", "data.bin"), content, 0644)).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should stream the whole file with its length", func() {
+		rec := get("/api/skills/my-skill/resources/assets/data.bin", nil)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Length")).To(Equal("4096"))
+		Expect(rec.Header().Get("Accept-Ranges")).To(Equal("bytes"))
+		Expect(rec.Body.Bytes()).To(Equal(content))
+	})
+
+	It("should serve a byte range", func() {
+		rec := get("/api/skills/my-skill/resources/assets/data.bin", http.Header{"Range": {"bytes=1000-1999"}})
+		Expect(rec.Code).To(Equal(http.StatusPartialContent))
+		Expect(rec.Header().Get("Content-Range")).To(Equal("bytes 1000-1999/4096"))
+		Expect(rec.Header().Get("Content-Length")).To(Equal("1000"))
+		Expect(rec.Body.Bytes()).To(Equal(content[1000:2000]))
+	})
+
+	It("should still return base64-encoded JSON when requested", func() {
+		rec := get("/api/skills/my-skill/resources/assets/data.bin?encoding=base64", nil)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+		Expect(rec.Body.String()).To(ContainSubstring(`"encoding":"base64"`))
+	})
+})
+
+var _ = Describe("Custom resource directories", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		skillDir := filepath.Join(tempDir, "my-skill")
+		Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# My Skill"), 0644)).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		dirs, err := domain.ParseResourceDirs("bin=script,docs=reference,files=asset")
+		Expect(err).NotTo(HaveOccurred())
+		manager.SetResourceDirs(dirs)
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should upload resources to the directory of their type", func() {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		Expect(writer.WriteField("type", "script")).To(Succeed())
+		part, err := writer.CreateFormFile("file", "run.sh")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = part.Write([]byte("#!/bin/sh"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/skills/my-skill/resources", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+		Expect(filepath.Join(tempDir, "my-skill", "bin", "run.sh")).To(BeAnExistingFile())
+	})
+
+	It("should reject writes outside the configured directories", func() {
+		req := httptest.NewRequest(http.MethodPost, "/api/skills/my-skill/resources",
+			strings.NewReader(`{"path": "scripts/run.sh", "content": "#!/bin/sh"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(filepath.Join(tempDir, "my-skill", "scripts")).NotTo(BeAnExistingFile())
+	})
+})
+
+var _ = Describe("Resource symlinks", func() {
+	var (
+		tempDir string
+		outside string
+		server  *web.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+		outside, err = os.MkdirTemp("", "skillserver-outside")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644)).To(Succeed())
+
+		skillDir := filepath.Join(tempDir, "my-skill")
+		Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# My Skill"), 0644)).To(Succeed())
+		Expect(os.Symlink(outside, filepath.Join(skillDir, "assets"))).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+		os.RemoveAll(outside)
+	})
+
+	It("should not serve files outside the skill", func() {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/my-skill/resources/assets/secret.txt", nil))
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+		Expect(rec.Body.String()).NotTo(ContainSubstring("secret"))
+	})
+
+	It("should not write or delete files outside the skill", func() {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/skills/my-skill/resources/assets/secret.txt", strings.NewReader("overwritten")))
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/skills/my-skill/resources/assets/secret.txt", nil))
+		Expect(rec.Code).To(Equal(http.StatusUnprocessableEntity))
+
+		content, err := os.ReadFile(filepath.Join(outside, "secret.txt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("secret"))
+	})
+})
+
+var _ = Describe("Local overlay", func() {
+	var (
+		tempDir    string
+		overlayDir string
+		server     *web.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+		overlayDir, err = os.MkdirTemp("", "skillserver-overlay")
+		Expect(err).NotTo(HaveOccurred())
+
+		skillDir := filepath.Join(tempDir, "repo", "docker")
+		Expect(os.MkdirAll(filepath.Join(skillDir, "scripts"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: docker\nid: git-docker\ndescription: Docker skill\n---\n# Docker"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "scripts", "run.sh"), []byte("echo git"), 0644)).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(tempDir, []string{"repo"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manager.SetOverlayDir(overlayDir)).To(Succeed())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+		os.RemoveAll(overlayDir)
+	})
+
+	It("should write git repository skill resources to the overlay", func() {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/skills/git-docker/resources/scripts/run.sh", strings.NewReader("echo overlay")))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/git-docker/resources/scripts/run.sh", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("echo overlay"))
+
+		content, err := os.ReadFile(filepath.Join(tempDir, "repo", "docker", "scripts", "run.sh"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("echo git"))
+	})
+
+	It("should write overlay resources of a git repository skill addressed by its name", func() {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/skills/repo%2Fdocker/resources", strings.NewReader(`{"path": "references/notes.md", "content": "# Notes"}`))
+		req.Header.Set("Content-Type", "application/json")
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+		Expect(filepath.Join(overlayDir, "repo", "docker", "references", "notes.md")).To(BeAnExistingFile())
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/skills/repo%2Fdocker/resources/scripts/run.sh", strings.NewReader("echo overlay")))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		content, err := os.ReadFile(filepath.Join(overlayDir, "repo", "docker", "scripts", "run.sh"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("echo overlay"))
+
+		var archive bytes.Buffer
+		gzw := gzip.NewWriter(&archive)
+		tw := tar.NewWriter(gzw)
+		Expect(tw.WriteHeader(&tar.Header{Name: "assets/logo.txt", Mode: 0644, Size: 4, Typeflag: tar.TypeReg})).To(Succeed())
+		_, err = tw.Write([]byte("logo"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tw.Close()).To(Succeed())
+		Expect(gzw.Close()).To(Succeed())
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", "resources.tar.gz")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = part.Write(archive.Bytes())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writer.Close()).To(Succeed())
+
+		rec = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodPost, "/api/skills/repo%2Fdocker/resources/upload-archive", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		server.ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusCreated), rec.Body.String())
+		Expect(filepath.Join(overlayDir, "repo", "docker", "assets", "logo.txt")).To(BeAnExistingFile())
+
+		// The checkout is left untouched
+		Expect(filepath.Join(tempDir, "repo", "docker", "references")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(tempDir, "repo", "docker", "assets")).NotTo(BeAnExistingFile())
+	})
+
+	It("should return resource URLs that address a git repository skill", func() {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/skills/repo%2Fdocker/resources/scripts/my%20run.sh", strings.NewReader("echo overlay")))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var resource struct {
+			URL string `json:"url"`
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resource)).To(Succeed())
+		Expect(resource.URL).To(Equal("/api/skills/repo%2Fdocker/resources/scripts/my%20run.sh"))
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, resource.URL, nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("echo overlay"))
+	})
+
+	It("should restore the git version when the overlay copy is deleted", func() {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/skills/git-docker/resources/scripts/run.sh", strings.NewReader("echo overlay")))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/skills/git-docker/resources/scripts/run.sh", nil))
+		Expect(rec.Code).To(Equal(http.StatusNoContent))
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/git-docker/resources/scripts/run.sh", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("echo git"))
+
+		rec = httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/skills/git-docker/resources/scripts/run.sh", nil))
+		Expect(rec.Code).To(Equal(http.StatusForbidden))
+	})
+})
+
+var _ = Describe("Resource thumbnails", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		skillDir := filepath.Join(tempDir, "repo", "gallery")
+		writeSkill(skillDir, "name: gallery\ndescription: Images\n", "# Gallery")
+		Expect(os.MkdirAll(filepath.Join(skillDir, "assets"), 0755)).To(Succeed())
+		img := image.NewNRGBA(image.Rect(0, 0, 256, 128))
+		var buf bytes.Buffer
+		Expect(png.Encode(&buf, img)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "assets", "banner.png"), buf.Bytes(), 0644)).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(tempDir, []string{"repo"})
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should return the thumbnail of an image of a git repository skill addressed by its name", func() {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/skills/repo%2Fgallery/resources/assets/banner.png/thumbnail?size=64", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK), rec.Body.String())
+		Expect(rec.Header().Get("Content-Type")).To(Equal("image/png"))
+
+		thumb, err := png.Decode(bytes.NewReader(rec.Body.Bytes()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(thumb.Bounds().Dx()).To(Equal(64))
+	})
+})
+
+var _ = Describe("Skill files", func() {
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		skillDir := filepath.Join(tempDir, "my-skill")
+		Expect(os.MkdirAll(skillDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# My Skill"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(skillDir, "USAGE.md"), []byte("# Usage"), 0644)).To(Succeed())
+		writeSkill(filepath.Join(tempDir, "repo", "helm"), "name: helm\ndescription: Helm\n", "# Helm")
+		Expect(os.WriteFile(filepath.Join(tempDir, "repo", "helm", "CHARTS.md"), []byte("# Charts"), 0644)).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(tempDir, []string{"repo"})
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should return a file next to SKILL.md as text", func() {
+		rec := get("/api/skills/my-skill/files/USAGE.md")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(HavePrefix("text/markdown"))
+		Expect(rec.Body.String()).To(Equal("# Usage"))
+	})
+
+	It("should return it base64-encoded in JSON when requested", func() {
+		rec := get("/api/skills/my-skill/files/USAGE.md?encoding=base64")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring(`"content":"IyBVc2FnZQ=="`))
+		Expect(rec.Body.String()).To(ContainSubstring(`"encoding":"base64"`))
+	})
+
+	It("should list files inside and outside the resource directories", func() {
+		Expect(os.MkdirAll(filepath.Join(tempDir, "my-skill", "assets"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tempDir, "my-skill", "assets", "logo.txt"), []byte("logo"), 0644)).To(Succeed())
+
+		rec := get("/api/skills/my-skill/files")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var response struct {
+			Files []struct {
+				Type string `json:"type"`
+				Path string `json:"path"`
+			} `json:"files"`
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		types := map[string]string{}
+		for _, file := range response.Files {
+			types[file.Path] = file.Type
+		}
+		Expect(types).To(Equal(map[string]string{"USAGE.md": "other", "assets/logo.txt": "asset"}))
+	})
+
+	It("should list the files of a git repository skill addressed by its name", func() {
+		rec := get("/api/skills/repo%2Fhelm/files")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var response struct {
+			Files []struct {
+				Path string `json:"path"`
+			} `json:"files"`
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		var paths []string
+		for _, file := range response.Files {
+			paths = append(paths, file.Path)
+		}
+		Expect(paths).To(ConsistOf("CHARTS.md"))
+	})
+
+	It("should return a file of a git repository skill addressed by its name", func() {
+		rec := get("/api/skills/repo%2Fhelm/files/CHARTS.md")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("# Charts"))
+	})
+
+	It("should reject hidden files and missing files", func() {
+		Expect(get("/api/skills/my-skill/files/.skillmeta.json").Code).To(Equal(http.StatusBadRequest))
+		Expect(get("/api/skills/my-skill/files/MISSING.md").Code).To(Equal(http.StatusNotFound))
+		Expect(get("/api/skills/missing/files/USAGE.md").Code).To(Equal(http.StatusNotFound))
+	})
+})
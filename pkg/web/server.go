@@ -2,11 +2,17 @@ package web
 
 import (
 	"context"
+	"crypto/tls"
 	"embed"
+	"fmt"
 	"io"
 	"io/fs"
 	"log/slog"
+	"net"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v5"
@@ -28,21 +34,51 @@ type Server struct {
 	gitRepos      []string
 	gitSyncer     *git.GitSyncer
 	configManager *git.ConfigManager
+	importOptions domain.ImportOptions
+	runtime       RuntimeConfig
+	logger        *slog.Logger // Logger for handler messages (nil = disabled)
+
+	maxResourceSize int // Largest resource that can be created or uploaded, in bytes
+	maxArchiveSize  int // Largest skill or resource archive that can be uploaded, in bytes
+
+	apiKey         string // Bearer token required by protected requests (empty = no authentication)
+	apiKeyForReads bool   // Whether reads and the MCP HTTP endpoints require the API key too
+	webhookSecret  string // Shared secret git webhooks are signed with (empty = unsigned webhooks accepted)
+
+	rateLimit float64 // Requests per second allowed per client IP (0 = unlimited)
+
+	readOnly bool // Whether requests writing to the skills directory are refused
+
+	mcpPaths []string // Paths MCP HTTP handlers are mounted at
+
+	tlsCertFile string // Certificate served over HTTPS (empty = plain HTTP)
+	tlsKeyFile  string // Private key of the certificate
 }
 
+const (
+	// DefaultMaxResourceSize is the largest resource that can be created or uploaded by default
+	DefaultMaxResourceSize = 10 * 1024 * 1024
+	// DefaultMaxArchiveSize is the largest skill or resource archive that can be uploaded by default
+	DefaultMaxArchiveSize = 50 * 1024 * 1024
+)
+
 // NewServer creates a new web server
 func NewServer(skillManager domain.SkillManager, fsManager *domain.FileSystemManager, gitRepos []string, gitSyncer *git.GitSyncer, configManager *git.ConfigManager, enableLogging bool) *Server {
-	e := echo.New()
+	// Routes match the escaped path, so a git repo skill is addressed as one segment (repo%2Fhelm) that
+	// handlers get unescaped (repo/helm)
+	e := echo.NewWithConfig(echo.Config{
+		Router: echo.NewRouter(echo.RouterConfig{UnescapePathParamValues: true}),
+	})
 
 	// Middleware
 	// Only enable request logging if explicitly enabled (to avoid interfering with MCP stdio)
 	if enableLogging {
-		e.Use(middleware.RequestLogger())
+		e.Use(requestLogger())
 	} else {
 		e.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
 	}
 	e.Use(middleware.Recover())
-	//e.Use(middleware.CORS())
+	// CORS is off unless enabled with SetCORSOrigins
 
 	server := &Server{
 		echo:          e,
@@ -51,7 +87,15 @@ func NewServer(skillManager domain.SkillManager, fsManager *domain.FileSystemMan
 		gitRepos:      gitRepos,
 		gitSyncer:     gitSyncer,
 		configManager: configManager,
+		importOptions: domain.DefaultImportOptions,
+
+		maxResourceSize: DefaultMaxResourceSize,
+		maxArchiveSize:  DefaultMaxArchiveSize,
 	}
+	// Does nothing until an API key is set with SetAPIKey
+	e.Use(server.requireAPIKey)
+	// Does nothing unless the server is made read-only with SetReadOnly
+	e.Use(server.rejectWrites)
 
 	// API routes
 	api := e.Group("/api")
@@ -60,28 +104,51 @@ func NewServer(skillManager domain.SkillManager, fsManager *domain.FileSystemMan
 	api.POST("/skills", server.createSkill)
 	api.PUT("/skills/:name", server.updateSkill)
 	api.DELETE("/skills/:name", server.deleteSkill)
+	api.POST("/skills/:name/copy", server.copySkill)
+	api.POST("/skills/:name/rename", server.renameSkill)
+	api.POST("/skills/bulk-delete", server.bulkDeleteSkills)
 	api.GET("/skills/search", server.searchSkills)
+	api.GET("/skills/issues", server.listSkillIssues)
+	api.GET("/skills/:name/history", server.getSkillHistory)
+	api.GET("/search/status", server.getSearchStatus)
+	api.GET("/stats", server.getStats)
+	api.GET("/config", server.getConfig)
+	api.POST("/reindex", server.reindex)
+	api.GET("/openapi.json", server.getOpenAPI)
 
 	// Import/Export routes
 	// Use wildcard for export to handle skill names with slashes (repoName/skillName)
 	// Register before other /skills routes to ensure it matches first
 	api.GET("/skills/export/*", server.exportSkill)
+	api.GET("/skills/export-all", server.exportAllSkills)
 	api.POST("/skills/import", server.importSkill)
+	api.POST("/skills/validate", server.validateSkill)
 
 	// Resource management routes
 	api.GET("/skills/:name/resources", server.listSkillResources)
 	api.GET("/skills/:name/resources/*", server.getSkillResource)
+	api.GET("/skills/:name/files", server.listSkillFiles)
+	api.GET("/skills/:name/files/*", server.getSkillFile)
 	api.POST("/skills/:name/resources", server.createSkillResource)
+	api.POST("/skills/:name/resources/upload-archive", server.uploadResourceArchive)
 	api.PUT("/skills/:name/resources/*", server.updateSkillResource)
 	api.DELETE("/skills/:name/resources/*", server.deleteSkillResource)
 
 	// Git repository management routes
 	api.GET("/git-repos", server.listGitRepos)
 	api.POST("/git-repos", server.addGitRepo)
+	api.POST("/git-repos/validate", server.validateGitRepo)
+	api.POST("/git-repos/sync", server.syncAllGitRepos)
 	api.PUT("/git-repos/:id", server.updateGitRepo)
 	api.DELETE("/git-repos/:id", server.deleteGitRepo)
 	api.POST("/git-repos/:id/sync", server.syncGitRepo)
 	api.POST("/git-repos/:id/toggle", server.toggleGitRepo)
+	api.POST("/git-repos/webhook", server.gitWebhook)
+	api.POST("/git-repos/:id/webhook", server.gitRepoWebhook)
+
+	// Health probes
+	e.GET("/healthz", server.getHealth)
+	e.GET("/readyz", server.getReadiness)
 
 	// Serve UI
 	uiFS, err := fs.Sub(uiFiles, "ui")
@@ -93,21 +160,224 @@ func NewServer(skillManager domain.SkillManager, fsManager *domain.FileSystemMan
 	return server
 }
 
-// Start starts the web server
+// SetLogger sets the logger for handler messages and, when request logging is enabled, requests
+func (s *Server) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+	s.echo.Logger = logger
+}
+
+// logf logs a message at level to the configured logger, if any, with URL credentials masked
+func (s *Server) logf(level slog.Level, format string, args ...any) {
+	if s.logger == nil || !s.logger.Enabled(context.Background(), level) {
+		return
+	}
+	s.logger.Log(context.Background(), level, git.RedactCredentials(fmt.Sprintf(format, args...)))
+}
+
+// requestLogger logs each request at a level matching its outcome: server errors at error level,
+// health probes at debug level, and everything else at info level
+func requestLogger() echo.MiddlewareFunc {
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogLatency:  true,
+		LogRemoteIP: true,
+		LogMethod:   true,
+		LogURI:      true,
+		LogStatus:   true,
+		HandleError: true,
+		LogValuesFunc: func(c *echo.Context, v middleware.RequestLoggerValues) error {
+			level := slog.LevelInfo
+			switch {
+			case v.Status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case v.URI == "/healthz" || v.URI == "/readyz":
+				level = slog.LevelDebug
+			}
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.String("uri", git.RedactCredentials(v.URI)),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency),
+				slog.String("remote_ip", v.RemoteIP),
+			}
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("error", git.RedactCredentials(v.Error.Error())))
+			}
+			c.Logger().LogAttrs(context.Background(), level, "request", attrs...)
+			return nil
+		},
+	})
+}
+
+// SetImportOptions sets the permissions and extension policy applied when extracting imported skill archives
+func (s *Server) SetImportOptions(opts domain.ImportOptions) {
+	s.importOptions = opts
+}
+
+// resourceDirs returns the skill subdirectories holding resources
+func (s *Server) resourceDirs() domain.ResourceDirs {
+	if s.fsManager == nil {
+		return domain.DefaultResourceDirs
+	}
+	return s.fsManager.ResourceDirs()
+}
+
+// maxContentLength returns the longest SKILL.md body that can be stored, in bytes (0 = no limit)
+func (s *Server) maxContentLength() int {
+	if s.fsManager == nil {
+		return domain.DefaultMaxContentLength
+	}
+	return s.fsManager.MaxContentLength()
+}
+
+// overlayEnabled reports whether writes to git repository skills go to the local overlay
+func (s *Server) overlayEnabled() bool {
+	return s.fsManager != nil && s.fsManager.OverlayDir() != ""
+}
+
+// SetMaxResourceSize sets the largest resource that can be created or uploaded, in bytes
+func (s *Server) SetMaxResourceSize(size int) {
+	s.maxResourceSize = size
+}
+
+// SetMaxArchiveSize sets the largest skill or resource archive that can be uploaded, in bytes
+func (s *Server) SetMaxArchiveSize(size int) {
+	s.maxArchiveSize = size
+}
+
+// SetCORSOrigins lets browser frontends on the given origins, or any origin with "*", call the REST API
+// It must be called before the server starts serving requests
+func (s *Server) SetCORSOrigins(origins []string) error {
+	cors, err := corsMiddleware(origins)
+	if err != nil {
+		return err
+	}
+	// Run before routing and authentication, so preflight requests and 401 responses get CORS headers too
+	s.echo.Pre(cors)
+	return nil
+}
+
+// ValidateCORSOrigins returns an error if origins cannot be passed to SetCORSOrigins
+func ValidateCORSOrigins(origins []string) error {
+	_, err := corsMiddleware(origins)
+	return err
+}
+
+// corsMiddleware returns the CORS middleware for the REST API
+func corsMiddleware(origins []string) (echo.MiddlewareFunc, error) {
+	cors, err := middleware.CORSConfig{
+		Skipper: func(c *echo.Context) bool {
+			return !strings.HasPrefix(c.Request().URL.Path, "/api/")
+		},
+		AllowOrigins:  origins,
+		AllowMethods:  []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodDelete},
+		AllowHeaders:  []string{echo.HeaderContentType, echo.HeaderAuthorization, "Range"},
+		ExposeHeaders: []string{searchStatusHeader, echo.HeaderContentDisposition, "Content-Range"},
+	}.ToMiddleware()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS origins: %w", err)
+	}
+	return cors, nil
+}
+
+// SetRuntimeConfig sets the startup settings reported by the config endpoint
+func (s *Server) SetRuntimeConfig(cfg RuntimeConfig) {
+	s.runtime = cfg
+}
+
+// MountMCP serves an MCP HTTP handler under the given path (e.g. a scoped MCP server)
+func (s *Server) MountMCP(path string, handler http.Handler) {
+	s.mcpPaths = append(s.mcpPaths, path)
+	s.echo.Any(path, echo.WrapHandler(handler))
+}
+
+// isMCPPath reports whether a request path is served by an MCP HTTP handler
+func (s *Server) isMCPPath(path string) bool {
+	return slices.Contains(s.mcpPaths, path)
+}
+
+// ServeHTTP serves a request with the web server's routes, e.g. in tests
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.echo.ServeHTTP(w, r)
+}
+
+// ListenAddr combines a host and a port into the address the web server listens on
+// An empty host listens on all interfaces
+func ListenAddr(host, port string) (string, error) {
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return "", fmt.Errorf("invalid port %q (expected a number from 0 to 65535)", port)
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if host != "" && net.ParseIP(host) == nil && !isHostname(host) {
+		return "", fmt.Errorf("invalid host %q (expected an IP address or hostname such as 127.0.0.1 or localhost)", host)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// isHostname reports whether s is a syntactically valid DNS hostname
+func isHostname(s string) bool {
+	if len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(s, "."), ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SetTLS serves HTTPS with the given certificate and private key files instead of plain HTTP
+// The files are loaded once to validate them; Start loads them again
+func (s *Server) SetTLS(certFile, keyFile string) error {
+	if err := ValidateTLS(certFile, keyFile); err != nil {
+		return err
+	}
+	s.tlsCertFile = certFile
+	s.tlsKeyFile = keyFile
+	return nil
+}
+
+// ValidateTLS returns an error if the certificate and private key files cannot be passed to SetTLS
+func ValidateTLS(certFile, keyFile string) error {
+	if certFile == "" || keyFile == "" {
+		return fmt.Errorf("both a TLS certificate and a TLS key are required")
+	}
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		return fmt.Errorf("invalid TLS certificate %s or key %s: %w", certFile, keyFile, err)
+	}
+	return nil
+}
+
+// Start starts the web server, over HTTPS if SetTLS was called
 func (s *Server) Start(addr string) error {
 	s.httpServer = &http.Server{
 		Addr:    addr,
 		Handler: s.echo,
 	}
+	if s.tlsCertFile != "" {
+		return s.httpServer.ListenAndServeTLS(s.tlsCertFile, s.tlsKeyFile)
+	}
 	return s.httpServer.ListenAndServe()
 }
 
-// Shutdown gracefully shuts down the server
-func (s *Server) Shutdown() error {
+// DefaultShutdownTimeout is how long Shutdown waits for in-flight requests by default
+const DefaultShutdownTimeout = 10 * time.Second
+
+// Shutdown gracefully shuts down the server, waiting up to timeout for in-flight requests to finish
+// A timeout of zero or less uses DefaultShutdownTimeout
+func (s *Server) Shutdown(timeout time.Duration) error {
 	if s.httpServer == nil {
 		return nil
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	return s.httpServer.Shutdown(ctx)
 }
@@ -0,0 +1,194 @@
+package web_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/web"
+)
+
+var _ = Describe("CORS", func() {
+	const origin = "https://app.example.com"
+
+	var (
+		tempDir string
+		server  *web.Server
+	)
+
+	request := func(method, path, requestOrigin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Origin", requestOrigin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-web-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		manager, err := domain.NewFileSystemManager(tempDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should be disabled by default", func() {
+		rec := request(http.MethodGet, "/api/skills", origin)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+	})
+
+	It("should allow a configured origin", func() {
+		Expect(server.SetCORSOrigins([]string{origin})).To(Succeed())
+
+		rec := request(http.MethodGet, "/api/skills", origin)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(Equal(origin))
+
+		rec = request(http.MethodOptions, "/api/skills", origin)
+		Expect(rec.Code).To(Equal(http.StatusNoContent))
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(Equal(origin))
+		Expect(rec.Header().Get("Access-Control-Allow-Methods")).To(ContainSubstring(http.MethodPost))
+
+		rec = request(http.MethodGet, "/api/skills", "https://evil.example.com")
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+	})
+
+	It("should allow any origin with a wildcard", func() {
+		Expect(server.SetCORSOrigins([]string{"*"})).To(Succeed())
+		rec := request(http.MethodGet, "/api/skills", origin)
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"))
+	})
+
+	It("should reject malformed origins", func() {
+		Expect(web.ValidateCORSOrigins([]string{"app.example.com/path"})).NotTo(Succeed())
+		Expect(server.SetCORSOrigins([]string{"app.example.com/path"})).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("ListenAddr", func() {
+	It("should listen on all interfaces without a host", func() {
+		Expect(web.ListenAddr("", "8080")).To(Equal(":8080"))
+	})
+
+	It("should combine a host and a port", func() {
+		Expect(web.ListenAddr("127.0.0.1", "8080")).To(Equal("127.0.0.1:8080"))
+		Expect(web.ListenAddr("localhost", "9090")).To(Equal("localhost:9090"))
+		Expect(web.ListenAddr("::1", "8080")).To(Equal("[::1]:8080"))
+		Expect(web.ListenAddr("[::1]", "8080")).To(Equal("[::1]:8080"))
+	})
+
+	It("should reject invalid hosts and ports", func() {
+		for _, host := range []string{"127.0.0.1:80", "bad host", "-example.com", "http://localhost"} {
+			_, err := web.ListenAddr(host, "8080")
+			Expect(err).To(HaveOccurred(), host)
+		}
+		for _, port := range []string{"", "http", "-1", "65536"} {
+			_, err := web.ListenAddr("127.0.0.1", port)
+			Expect(err).To(HaveOccurred(), port)
+		}
+	})
+})
+
+var _ = Describe("TLS", func() {
+	var (
+		tempDir  string
+		certFile string
+		keyFile  string
+		server   *web.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-tls-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Self-signed certificate for 127.0.0.1
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "skillserver-test"},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).NotTo(HaveOccurred())
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		Expect(err).NotTo(HaveOccurred())
+
+		certFile = filepath.Join(tempDir, "cert.pem")
+		keyFile = filepath.Join(tempDir, "key.pem")
+		Expect(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644)).To(Succeed())
+		Expect(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)).To(Succeed())
+
+		skillsDir := filepath.Join(tempDir, "skills")
+		Expect(os.MkdirAll(skillsDir, 0755)).To(Succeed())
+		manager, err := domain.NewFileSystemManager(skillsDir, nil)
+		Expect(err).NotTo(HaveOccurred())
+		server = web.NewServer(manager, manager, nil, nil, nil, false)
+	})
+
+	AfterEach(func() {
+		server.Shutdown(time.Second)
+		os.RemoveAll(tempDir)
+	})
+
+	It("should serve HTTPS", func() {
+		Expect(server.SetTLS(certFile, keyFile)).To(Succeed())
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		addr := ln.Addr().String()
+		ln.Close()
+		go server.Start(addr)
+
+		certPEM, err := os.ReadFile(certFile)
+		Expect(err).NotTo(HaveOccurred())
+		roots := x509.NewCertPool()
+		Expect(roots.AppendCertsFromPEM(certPEM)).To(BeTrue())
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+
+		Eventually(func() (int, error) {
+			resp, err := client.Get("https://" + addr + "/api/skills")
+			if err != nil {
+				return 0, err
+			}
+			resp.Body.Close()
+			return resp.StatusCode, nil
+		}).Should(Equal(http.StatusOK))
+	})
+
+	It("should reject a missing or mismatched key", func() {
+		Expect(server.SetTLS(certFile, "")).NotTo(Succeed())
+		Expect(web.ValidateTLS(certFile, filepath.Join(tempDir, "missing.pem"))).NotTo(Succeed())
+		Expect(web.ValidateTLS(keyFile, certFile)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,22 @@
+package web_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestWeb(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Web Suite")
+}
+
+// writeSkill writes a SKILL.md with the given frontmatter lines and body to dir, creating the directory
+func writeSkill(dir, frontmatter, content string) {
+	GinkgoHelper()
+	Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\n"+frontmatter+"---\n"+content), 0644)).To(Succeed())
+}
@@ -0,0 +1,122 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+
+	"github.com/mudler/skillserver/pkg/domain"
+)
+
+// ValidateSkillRequest represents a request to dry-run validating SKILL.md content
+type ValidateSkillRequest struct {
+	Content string `json:"content"`        // Raw SKILL.md, frontmatter included
+	Name    string `json:"name,omitempty"` // Optional directory name the frontmatter name must match
+}
+
+// SkillMetadataResponse is the frontmatter of a validated SKILL.md
+type SkillMetadataResponse struct {
+	ID            string            `json:"id,omitempty"`
+	Name          string            `json:"name"`
+	Description   string            `json:"description"`
+	License       string            `json:"license,omitempty"`
+	Compatibility string            `json:"compatibility,omitempty"`
+	Version       string            `json:"version,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	AllowedTools  string            `json:"allowed-tools,omitempty"`
+	Extra         map[string]any    `json:"extra,omitempty"`
+	Icon          string            `json:"icon,omitempty"`
+	Color         string            `json:"color,omitempty"`
+}
+
+// ValidateSkillResponse is the result of a skill dry run
+type ValidateSkillResponse struct {
+	Valid    bool                   `json:"valid"`
+	Name     string                 `json:"name,omitempty"`     // Directory name of the skill, if known
+	Metadata *SkillMetadataResponse `json:"metadata,omitempty"` // Parsed frontmatter, omitted if it cannot be parsed
+	Errors   []string               `json:"errors"`
+	Warnings []string               `json:"warnings"`
+}
+
+// gzipMagic starts every gzip stream, telling skill archives from plain SKILL.md uploads
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// validateSkill checks SKILL.md content, or an uploaded SKILL.md or skill archive, and reports every problem
+// found, without writing anything to disk
+func (s *Server) validateSkill(c *echo.Context) error {
+	var validation *domain.SkillValidation
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "file is required",
+			})
+		}
+		if file.Size > int64(s.maxArchiveSize) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": fmt.Sprintf("file too large (max %d bytes)", s.maxArchiveSize),
+			})
+		}
+		src, err := file.Open()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "failed to open uploaded file",
+			})
+		}
+		defer src.Close()
+		data, err := io.ReadAll(io.LimitReader(src, int64(s.maxArchiveSize)))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "failed to read uploaded file",
+			})
+		}
+
+		if bytes.HasPrefix(data, gzipMagic) {
+			validation = domain.ValidateSkillArchive(data, s.importOptions, s.maxContentLength())
+		} else {
+			validation = domain.ValidateSkillMd(string(data), c.FormValue("name"), s.maxContentLength())
+		}
+	} else {
+		var req ValidateSkillRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "invalid request",
+			})
+		}
+		if strings.TrimSpace(req.Content) == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "content is required",
+			})
+		}
+		validation = domain.ValidateSkillMd(req.Content, req.Name, s.maxContentLength())
+	}
+
+	response := ValidateSkillResponse{
+		Valid:    validation.Valid(),
+		Name:     validation.Name,
+		Errors:   append([]string{}, validation.Errors...),
+		Warnings: append([]string{}, validation.Warnings...),
+	}
+	if metadata := validation.Metadata; metadata != nil {
+		response.Metadata = &SkillMetadataResponse{
+			ID:            metadata.ID,
+			Name:          metadata.Name,
+			Description:   metadata.Description,
+			License:       metadata.License,
+			Compatibility: metadata.Compatibility,
+			Version:       metadata.Version,
+			Tags:          metadata.Tags,
+			Metadata:      metadata.Metadata,
+			AllowedTools:  metadata.AllowedTools,
+			Extra:         metadata.Extra,
+			Icon:          metadata.Icon,
+			Color:         metadata.Color,
+		}
+	}
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,206 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+
+	"github.com/mudler/skillserver/pkg/git"
+)
+
+// maxWebhookPayloadSize is the largest webhook payload accepted, matching GitHub's own limit
+const maxWebhookPayloadSize = 25 * 1024 * 1024
+
+// WebhookResponse lists the repositories a webhook synced
+type WebhookResponse struct {
+	Synced []string `json:"synced"` // IDs of the synced repositories
+}
+
+// webhookPayload holds the repository URLs sent by GitHub and GitLab push events
+type webhookPayload struct {
+	Repository struct {
+		CloneURL   string `json:"clone_url"`
+		SSHURL     string `json:"ssh_url"`
+		GitURL     string `json:"git_url"`
+		HTMLURL    string `json:"html_url"`
+		URL        string `json:"url"`
+		GitHTTPURL string `json:"git_http_url"`
+		GitSSHURL  string `json:"git_ssh_url"`
+		Homepage   string `json:"homepage"`
+	} `json:"repository"`
+	Project struct {
+		GitHTTPURL string `json:"git_http_url"`
+		GitSSHURL  string `json:"git_ssh_url"`
+		WebURL     string `json:"web_url"`
+	} `json:"project"`
+}
+
+// urls returns every repository URL in the payload
+func (p webhookPayload) urls() []string {
+	r := p.Repository
+	return []string{
+		r.CloneURL, r.SSHURL, r.GitURL, r.HTMLURL, r.URL, r.GitHTTPURL, r.GitSSHURL, r.Homepage,
+		p.Project.GitHTTPURL, p.Project.GitSSHURL, p.Project.WebURL,
+	}
+}
+
+// SetWebhookSecret sets the shared secret webhooks must be signed with, as GitHub's X-Hub-Signature-256
+// or GitLab's X-Gitlab-Token; webhooks signed with it do not need the API key
+// An empty secret accepts unsigned webhooks
+func (s *Server) SetWebhookSecret(secret string) {
+	s.webhookSecret = secret
+}
+
+// isWebhookPath reports whether a request path is one of the git webhook endpoints
+func isWebhookPath(path string) bool {
+	return strings.HasPrefix(path, "/api/git-repos/") && strings.HasSuffix(path, "/webhook")
+}
+
+// verifyWebhook reports whether a webhook request carries a valid signature for the configured secret
+func (s *Server) verifyWebhook(req *http.Request, body []byte) bool {
+	if s.webhookSecret == "" {
+		return true
+	}
+	if token := req.Header.Get("X-Gitlab-Token"); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(s.webhookSecret)) == 1
+	}
+	signature, ok := strings.CutPrefix(req.Header.Get("X-Hub-Signature-256"), "sha256=")
+	if !ok {
+		return false
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// readWebhook reads and verifies a webhook request, writing the error response if it is rejected
+// handled is true when the request needs no further processing (it was rejected, or is a ping)
+func (s *Server) readWebhook(c *echo.Context) (body []byte, handled bool, err error) {
+	if s.gitSyncer == nil || s.configManager == nil {
+		return nil, true, c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "git syncer or config manager not available",
+		})
+	}
+
+	body, err = io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookPayloadSize+1))
+	if err != nil {
+		return nil, true, c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to read webhook payload",
+		})
+	}
+	if len(body) > maxWebhookPayloadSize {
+		return nil, true, c.JSON(http.StatusRequestEntityTooLarge, map[string]string{
+			"error": "webhook payload too large",
+		})
+	}
+	if !s.verifyWebhook(c.Request(), body) {
+		return nil, true, c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "invalid webhook signature",
+		})
+	}
+
+	// GitHub sends a ping when the webhook is created
+	if c.Request().Header.Get("X-GitHub-Event") == "ping" {
+		return nil, true, c.JSON(http.StatusOK, WebhookResponse{Synced: []string{}})
+	}
+	return body, false, nil
+}
+
+// gitWebhook syncs the enabled repositories matching the repository of a GitHub or GitLab push event
+func (s *Server) gitWebhook(c *echo.Context) error {
+	body, handled, err := s.readWebhook(c)
+	if handled {
+		return err
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid webhook payload",
+		})
+	}
+	pushed := make(map[string]bool)
+	for _, u := range payload.urls() {
+		if u != "" {
+			pushed[git.RepoLocation(u)] = true
+		}
+	}
+
+	configRepos, err := s.configManager.LoadConfig()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to load config: %v", err),
+		})
+	}
+	var matched []git.GitRepoConfig
+	for _, repo := range configRepos {
+		if repo.Enabled && pushed[git.RepoLocation(repo.URL)] {
+			matched = append(matched, repo)
+		}
+	}
+	if len(matched) == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			"error": "no enabled repository matches the webhook",
+		})
+	}
+
+	return s.syncWebhookRepos(c, matched)
+}
+
+// gitRepoWebhook syncs the repository with the given ID, whatever the payload
+func (s *Server) gitRepoWebhook(c *echo.Context) error {
+	_, handled, err := s.readWebhook(c)
+	if handled {
+		return err
+	}
+
+	configRepos, err := s.configManager.LoadConfig()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": fmt.Sprintf("failed to load config: %v", err),
+		})
+	}
+	for _, repo := range configRepos {
+		if repo.ID != c.Param("id") {
+			continue
+		}
+		if !repo.Enabled {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "cannot sync disabled repository",
+			})
+		}
+		return s.syncWebhookRepos(c, []git.GitRepoConfig{repo})
+	}
+
+	return c.JSON(http.StatusNotFound, map[string]string{
+		"error": "repository not found",
+	})
+}
+
+// syncWebhookRepos syncs the repositories a webhook matched, which re-indexes the skills
+func (s *Server) syncWebhookRepos(c *echo.Context, repos []git.GitRepoConfig) error {
+	response := WebhookResponse{Synced: []string{}}
+	for _, repo := range repos {
+		if err := s.gitSyncer.SyncRepo(repo.URL); err != nil {
+			s.logf(slog.LevelWarn, "webhook sync of repo %s failed: %v", repo.URL, err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": err.Error(),
+			})
+		}
+		response.Synced = append(response.Synced, repo.ID)
+	}
+	return c.JSON(http.StatusOK, response)
+}
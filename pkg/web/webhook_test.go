@@ -0,0 +1,132 @@
+package web_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/mudler/skillserver/pkg/domain"
+	"github.com/mudler/skillserver/pkg/git"
+	"github.com/mudler/skillserver/pkg/web"
+)
+
+var _ = Describe("Git webhooks", func() {
+	const secret = "webhook-secret"
+
+	var (
+		tempDir     string
+		skillsDir   string
+		upstreamDir string
+		server      *web.Server
+	)
+
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	post := func(path, body, signature string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-GitHub-Event", "push")
+		if signature != "" {
+			req.Header.Set("X-Hub-Signature-256", signature)
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "skillserver-webhook-test")
+		Expect(err).NotTo(HaveOccurred())
+		skillsDir = filepath.Join(tempDir, "skills")
+		Expect(os.MkdirAll(skillsDir, 0755)).To(Succeed())
+
+		// Upstream repository with one skill
+		upstreamDir = filepath.Join(tempDir, "upstream")
+		upstream, err := gogit.PlainInit(upstreamDir, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(upstreamDir, "my-skill"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(upstreamDir, "my-skill", "SKILL.md"), []byte("---\nname: my-skill\ndescription: A skill\n---\n# My Skill"), 0644)).To(Succeed())
+		w, err := upstream.Worktree()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Add("my-skill/SKILL.md")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = w.Commit("add skill", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		configManager := git.NewConfigManager(skillsDir)
+		Expect(configManager.SaveConfig([]git.GitRepoConfig{{
+			ID:      git.GenerateID(upstreamDir),
+			URL:     upstreamDir,
+			Name:    git.ExtractRepoName(upstreamDir),
+			Enabled: true,
+		}})).To(Succeed())
+
+		manager, err := domain.NewFileSystemManager(skillsDir, []string{"upstream"})
+		Expect(err).NotTo(HaveOccurred())
+		syncer := git.NewGitSyncer(skillsDir, []string{upstreamDir}, manager.RebuildIndex)
+		server = web.NewServer(manager, manager, []string{upstreamDir}, syncer, configManager, false)
+		server.SetWebhookSecret(secret)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tempDir)
+	})
+
+	It("should sync the repository of a signed push event", func() {
+		body := `{"ref":"refs/heads/main","repository":{"clone_url":"file://` + upstreamDir + `.git"}}`
+		rec := post("/api/git-repos/webhook", body, sign(body))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var response web.WebhookResponse
+		Expect(json.Unmarshal(rec.Body.Bytes(), &response)).To(Succeed())
+		Expect(response.Synced).To(Equal([]string{"upstream"}))
+		Expect(filepath.Join(skillsDir, "upstream", "my-skill", "SKILL.md")).To(BeAnExistingFile())
+	})
+
+	It("should sync a repository by ID", func() {
+		rec := post("/api/git-repos/upstream/webhook", "{}", sign("{}"))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(filepath.Join(skillsDir, "upstream", "my-skill", "SKILL.md")).To(BeAnExistingFile())
+	})
+
+	It("should reject an invalid signature", func() {
+		body := `{"repository":{"clone_url":"` + upstreamDir + `"}}`
+		rec := post("/api/git-repos/webhook", body, sign("tampered"))
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+
+		rec = post("/api/git-repos/webhook", body, "")
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		Expect(filepath.Join(skillsDir, "upstream")).NotTo(BeADirectory())
+	})
+
+	It("should report push events for unknown repositories", func() {
+		body := `{"repository":{"clone_url":"https://github.com/org/other.git"}}`
+		rec := post("/api/git-repos/webhook", body, sign(body))
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should not need the API key when signed", func() {
+		server.SetAPIKey("s3cret", false)
+		rec := post("/api/git-repos/upstream/webhook", "{}", sign("{}"))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+})